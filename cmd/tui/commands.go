@@ -0,0 +1,386 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bcdxn/f1cli/f1livetiming"
+	"github.com/bcdxn/f1cli/internal/recording"
+	"github.com/bcdxn/f1cli/tui"
+)
+
+const (
+	exitOK    = 0 // exitOK is returned when the command completes or is interrupted by the user
+	exitError = 1 // exitError is returned when the command fails, e.g. the connection is lost
+	exitUsage = 2 // exitUsage is returned for unknown commands and invalid flags, arguments or config
+)
+
+// version information set at build time with `-ldflags "-X main.version=..."`
+var (
+	version = "dev"
+	commit  = "none"
+	date    = "unknown"
+)
+
+// command is a subcommand of the f1 CLI.
+type command struct {
+	name        string
+	args        string // args describes the positional arguments of the command, e.g. "<file>"
+	description string
+	// setup registers the flags of the command on the given flag set; the returned function runs the
+	// command with the positional arguments once the flags are parsed and returns the exit code.
+	setup func(fs *flag.FlagSet) func(args []string) int
+}
+
+// commands returns every subcommand of the f1 CLI; the first is run when no command is given.
+func commands() []command {
+	return []command{
+		{name: "live", description: "Show the live timing board of the current session", setup: liveCmd},
+		{name: "replay", args: "<file>", description: "Show the timing board of a recorded session", setup: replayCmd},
+		{name: "record", args: "<file>", description: "Record the raw feed of the current session to a file ('-' for stdout)", setup: recordCmd},
+		{name: "stream", description: "Write every update of the current session to stdout", setup: streamCmd},
+		{name: "serve", description: "Serve the live state of the current session over HTTP", setup: serveCmd},
+		{name: "relay", description: "Re-broadcast the F1 LiveTiming API feed to local clients", setup: relayCmd},
+		{name: "export", args: "<file>", description: "Export the classification of a recorded session", setup: exportCmd},
+		{name: "version", description: "Print the version of f1", setup: versionCmd},
+		{name: "completion", args: "<bash|zsh|fish>", description: "Generate a shell completion script", setup: completionCmd},
+	}
+}
+
+// run runs the command given by the first argument with the remaining arguments, or the default
+// command when the first argument is a flag, and returns the exit code.
+func run(args []string) int {
+	if len(args) > 0 && slices.Contains([]string{"help", "-h", "-help", "--help"}, args[0]) {
+		if len(args) > 1 {
+			// `f1 help <command>` is the same as `f1 <command> -h`
+			return run([]string{args[1], "-h"})
+		}
+		printUsage(os.Stdout)
+		return exitOK
+	}
+
+	cmds := commands()
+	cmd := cmds[0]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		i := slices.IndexFunc(cmds, func(c command) bool { return c.name == args[0] })
+		if i < 0 {
+			fmt.Fprintf(os.Stderr, "unknown command '%s'\n\n", args[0])
+			printUsage(os.Stderr)
+			return exitUsage
+		}
+		cmd, args = cmds[i], args[1:]
+	}
+
+	fs := flag.NewFlagSet("f1 "+cmd.name, flag.ContinueOnError)
+	runCmd := cmd.setup(fs)
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintf(out, "Usage: f1 %s [flags] %s\n\n%s\n", cmd.name, cmd.args, cmd.description)
+		if hasFlags(fs) {
+			fmt.Fprintf(out, "\nFlags:\n")
+			fs.PrintDefaults()
+		}
+	}
+	// flags may also follow the positional arguments, e.g. `f1 replay race.jsonl -speed 10`
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if err == flag.ErrHelp {
+				return exitOK
+			}
+			return exitUsage
+		}
+		if args = fs.Args(); len(args) == 0 {
+			break
+		}
+		positional, args = append(positional, args[0]), args[1:]
+	}
+	return runCmd(positional)
+}
+
+// printUsage writes the list of commands to w.
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: f1 [command] [flags]\n\nCommands:\n")
+	for i, c := range commands() {
+		desc := c.description
+		if i == 0 {
+			desc += " (default)"
+		}
+		fmt.Fprintf(w, "  %-11s %s\n", c.name, desc)
+	}
+	fmt.Fprintf(w, "\nRun 'f1 <command> -h' for the flags of a command.\n")
+}
+
+// hasFlags indicates if any flag is registered on the flag set.
+func hasFlags(fs *flag.FlagSet) bool {
+	found := false
+	fs.VisitAll(func(*flag.Flag) { found = true })
+	return found
+}
+
+// isSet indicates if the flag with the given name was set on the command line, e.g. so an explicit
+// zero value takes precedence over the config file.
+func isSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) { set = set || f.Name == name })
+	return set
+}
+
+// usageError reports an invalid usage of the command and returns the corresponding exit code.
+func usageError(fs *flag.FlagSet, format string, args ...any) int {
+	fmt.Fprintf(fs.Output(), format+"\n", args...)
+	fs.Usage()
+	return exitUsage
+}
+
+/* Commands
+------------------------------------------------------------------------------------------------- */
+
+// liveCmd shows the timing board of the session currently running.
+func liveCmd(fs *flag.FlagSet) func(args []string) int {
+	loadConfig := configFlag(fs)
+	newLogger := logFlags(fs)
+	upstream := upstreamFlags(fs)
+	tuiOpts := tuiFlags(fs)
+	watch := fs.Bool("watch", false, "count down to the next session when none is running and show the timing board once it starts")
+	delay := fs.Duration("delay", 0, "broadcast delay of the timing board, e.g. '30s' to sync it with a TV stream; adjusted live with +/- and s")
+	return func(args []string) int {
+		if len(args) > 0 {
+			return usageError(fs, "unexpected arguments: %s", strings.Join(args, " "))
+		}
+		if *delay < 0 || *delay > f1livetiming.MaxDelay {
+			return usageError(fs, "delay must be between 0 and %s", f1livetiming.MaxDelay)
+		}
+		cfg := loadConfig()
+		l, f := newLogger(cfg)
+		defer f.Close()
+
+		opts, n, err := tuiOpts(l, cfg)
+		if err != nil {
+			return usageError(fs, "%s", err)
+		}
+		src := liveSource
+		if *watch {
+			src = watchSource
+		}
+		if !isSet(fs, "delay") {
+			*delay = time.Duration(cfg.Delay * float64(time.Second))
+		}
+		// the delay is shared by the clients of every retry so adjusting it lasts until the TUI exits
+		d := f1livetiming.NewDelay(*delay)
+		if err := runTUI(l, append(upstream(cfg), f1livetiming.WithDelay(d)), src, n, append(opts, tui.WithDelay(d))...); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitError
+		}
+		return exitOK
+	}
+}
+
+// replayCmd shows the timing board of a session recorded with the record command.
+func replayCmd(fs *flag.FlagSet) func(args []string) int {
+	loadConfig := configFlag(fs)
+	newLogger := logFlags(fs)
+	tuiOpts := tuiFlags(fs)
+	speed := fs.Float64("speed", 1, "playback speed relative to the recording, e.g. 10 to replay 10 times faster (0 for no delay)")
+	return func(args []string) int {
+		if len(args) != 1 {
+			return usageError(fs, "expected the recording to replay")
+		}
+		if *speed < 0 {
+			return usageError(fs, "speed must not be negative")
+		}
+		cfg := loadConfig()
+		l, f := newLogger(cfg)
+		defer f.Close()
+
+		opts, n, err := tuiOpts(l, cfg)
+		if err != nil {
+			return usageError(fs, "%s", err)
+		}
+		r, err := os.Open(args[0])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitError
+		}
+		defer r.Close()
+		if err := runTUI(l, nil, replaySource(l, r, *speed), n, opts...); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitError
+		}
+		return exitOK
+	}
+}
+
+// recordCmd records the raw feed of the session currently running so it can be replayed.
+func recordCmd(fs *flag.FlagSet) func(args []string) int {
+	loadConfig := configFlag(fs)
+	newLogger := logFlags(fs)
+	upstream := upstreamFlags(fs)
+	return func(args []string) int {
+		if len(args) != 1 {
+			return usageError(fs, "expected the file to record to")
+		}
+		cfg := loadConfig()
+		l, f := newLogger(cfg)
+		defer f.Close()
+
+		var w io.WriteCloser = os.Stdout
+		if args[0] != "-" {
+			file, err := os.Create(args[0])
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return exitError
+			}
+			w = file
+		}
+		defer w.Close()
+		if err := runRecord(l, upstream(cfg), recording.NewWriter(w)); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitError
+		}
+		return exitOK
+	}
+}
+
+// streamCmd writes every update of the session currently running to stdout.
+func streamCmd(fs *flag.FlagSet) func(args []string) int {
+	loadConfig := configFlag(fs)
+	newLogger := logFlags(fs)
+	upstream := upstreamFlags(fs)
+	format := fs.String("format", "json", "output format: 'json' for newline-delimited JSON records")
+	return func(args []string) int {
+		if len(args) > 0 {
+			return usageError(fs, "unexpected arguments: %s", strings.Join(args, " "))
+		}
+		if *format != "json" {
+			return usageError(fs, "invalid format '%s'", *format)
+		}
+		cfg := loadConfig()
+		l, f := newLogger(cfg)
+		defer f.Close()
+
+		if err := runStream(l, upstream(cfg)); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitError
+		}
+		return exitOK
+	}
+}
+
+// serveCmd serves the live state of the session currently running over HTTP.
+func serveCmd(fs *flag.FlagSet) func(args []string) int {
+	loadConfig := configFlag(fs)
+	newLogger := logFlags(fs)
+	upstream := upstreamFlags(fs)
+	addr := fs.String("addr", ":8080", "address to serve the HTTP API on")
+	return func(args []string) int {
+		if len(args) > 0 {
+			return usageError(fs, "unexpected arguments: %s", strings.Join(args, " "))
+		}
+		cfg := loadConfig()
+		l, f := newLogger(cfg)
+		defer f.Close()
+
+		if err := runServe(l, upstream(cfg), *addr); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitError
+		}
+		return exitOK
+	}
+}
+
+// relayCmd re-broadcasts the F1 LiveTiming API to local clients.
+func relayCmd(fs *flag.FlagSet) func(args []string) int {
+	loadConfig := configFlag(fs)
+	newLogger := logFlags(fs)
+	upstream := upstreamFlags(fs)
+	addr := fs.String("addr", ":8081", "address to serve the relayed F1 LiveTiming API on")
+	return func(args []string) int {
+		if len(args) > 0 {
+			return usageError(fs, "unexpected arguments: %s", strings.Join(args, " "))
+		}
+		cfg := loadConfig()
+		l, f := newLogger(cfg)
+		defer f.Close()
+
+		if err := runRelay(l, upstream(cfg), *addr); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitError
+		}
+		return exitOK
+	}
+}
+
+// exportCmd writes the final classification of a session recorded with the record command.
+func exportCmd(fs *flag.FlagSet) func(args []string) int {
+	loadConfig := configFlag(fs)
+	newLogger := logFlags(fs)
+	format := fs.String("format", "csv", "output format: 'csv' for the classification or 'json' for the full state of the session")
+	return func(args []string) int {
+		if len(args) != 1 {
+			return usageError(fs, "expected the recording to export")
+		}
+		if *format != "csv" && *format != "json" {
+			return usageError(fs, "invalid format '%s'", *format)
+		}
+		cfg := loadConfig()
+		l, f := newLogger(cfg)
+		defer f.Close()
+
+		r, err := os.Open(args[0])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitError
+		}
+		defer r.Close()
+		if err := runExport(l, r, *format, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitError
+		}
+		return exitOK
+	}
+}
+
+// versionCmd prints the version of the binary.
+func versionCmd(fs *flag.FlagSet) func(args []string) int {
+	return func(args []string) int {
+		v, c, d := version, commit, date
+		// binaries built with `go install` carry their version in the build info instead
+		if info, ok := debug.ReadBuildInfo(); ok && v == "dev" {
+			if info.Main.Version != "" && info.Main.Version != "(devel)" {
+				v = info.Main.Version
+			}
+			for _, s := range info.Settings {
+				switch s.Key {
+				case "vcs.revision":
+					c = s.Value
+				case "vcs.time":
+					d = s.Value
+				}
+			}
+		}
+		fmt.Printf("f1 %s (commit %s, built %s)\n", v, c, d)
+		return exitOK
+	}
+}
+
+// completionCmd prints the completion script of the given shell.
+func completionCmd(fs *flag.FlagSet) func(args []string) int {
+	return func(args []string) int {
+		if len(args) != 1 {
+			return usageError(fs, "expected the shell to generate the completion script for")
+		}
+		script, err := completionScript(args[0], commands())
+		if err != nil {
+			return usageError(fs, "%s", err)
+		}
+		fmt.Print(script)
+		return exitOK
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// completionScript returns the script completing the commands, flags and arguments of the f1 CLI
+// in the given shell.
+func completionScript(shell string, cmds []command) (string, error) {
+	switch shell {
+	case "bash":
+		return bashCompletion(cmds), nil
+	case "zsh":
+		// zsh runs the bash completion through its bash compatibility layer
+		return "#compdef f1\nautoload -U +X bashcompinit && bashcompinit\n" + bashCompletion(cmds), nil
+	case "fish":
+		return fishCompletion(cmds), nil
+	default:
+		return "", fmt.Errorf("unsupported shell '%s', expected one of: bash, zsh, fish", shell)
+	}
+}
+
+// bashCompletion returns the bash completion script; file names are completed for any word that
+// isn't a command or a flag.
+func bashCompletion(cmds []command) string {
+	var sb strings.Builder
+	names := make([]string, 0, len(cmds))
+	for _, c := range cmds {
+		names = append(names, c.name)
+	}
+	fmt.Fprintf(&sb, `_f1() {
+    local cur="${COMP_WORDS[COMP_CWORD]}" cmd=%s
+    if [[ ${COMP_CWORD} -gt 1 && "${COMP_WORDS[1]}" != -* ]]; then
+        cmd="${COMP_WORDS[1]}"
+    elif [[ ${COMP_CWORD} -eq 1 && "${cur}" != -* ]]; then
+        COMPREPLY=($(compgen -W "%s" -- "${cur}"))
+        return
+    fi
+    case "${cmd}" in
+`, cmds[0].name, strings.Join(names, " "))
+	for _, c := range cmds {
+		words := commandFlags(c)
+		if choices := argChoices(c); len(choices) > 0 {
+			words = append(words, choices...)
+		}
+		fmt.Fprintf(&sb, "        %s) COMPREPLY=($(compgen -W \"%s\" -- \"${cur}\")) ;;\n", c.name, strings.Join(words, " "))
+	}
+	sb.WriteString("    esac\n}\ncomplete -o default -F _f1 f1\n")
+	return sb.String()
+}
+
+// fishCompletion returns the fish completion script; the flags of the default command are also
+// completed before any command is given.
+func fishCompletion(cmds []command) string {
+	var sb strings.Builder
+	for _, c := range cmds {
+		fmt.Fprintf(&sb, "complete -c f1 -n __fish_use_subcommand -a %s -d %s\n", c.name, fishQuote(c.description))
+	}
+	for i, c := range cmds {
+		cond := "__fish_seen_subcommand_from " + c.name
+		if i == 0 {
+			cond += "; or __fish_use_subcommand"
+		}
+		fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+		c.setup(fs)
+		fs.VisitAll(func(f *flag.Flag) {
+			fmt.Fprintf(&sb, "complete -c f1 -n %s -o %s -d %s\n", fishQuote(cond), f.Name, fishQuote(firstSentence(f.Usage)))
+		})
+		if choices := argChoices(c); len(choices) > 0 {
+			fmt.Fprintf(&sb, "complete -c f1 -n %s -f -a %s\n", fishQuote(cond), fishQuote(strings.Join(choices, " ")))
+		}
+	}
+	return sb.String()
+}
+
+// commandFlags returns the flags of the command prefixed with a dash.
+func commandFlags(c command) []string {
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	c.setup(fs)
+	flags := make([]string, 0)
+	fs.VisitAll(func(f *flag.Flag) { flags = append(flags, "-"+f.Name) })
+	return flags
+}
+
+// argChoices returns the values a command accepts as argument, e.g. the shells of the completion
+// command described as `<bash|zsh|fish>`, or nil if the argument isn't one of a set of values.
+func argChoices(c command) []string {
+	if !strings.HasPrefix(c.args, "<") || !strings.Contains(c.args, "|") {
+		return nil
+	}
+	return strings.Split(strings.Trim(c.args, "<>"), "|")
+}
+
+// firstSentence returns the usage of a flag up to the first parenthesis or colon.
+func firstSentence(usage string) string {
+	if i := strings.IndexAny(usage, "(:"); i > 0 {
+		return strings.TrimSpace(usage[:i])
+	}
+	return usage
+}
+
+// fishQuote quotes the string for fish.
+func fishQuote(s string) string {
+	return "'" + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), "'", `\'`) + "'"
+}
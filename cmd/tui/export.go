@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+
+	"github.com/bcdxn/f1cli/domain"
+	"github.com/bcdxn/f1cli/f1livetiming"
+	"github.com/bcdxn/f1cli/internal/recording"
+)
+
+// sessionExport is the final state of a recorded session written by the export command.
+type sessionExport struct {
+	Meeting     domain.Meeting       `json:"meeting"`
+	Drivers     []domain.Driver      `json:"drivers"` // Drivers are ordered by their final position
+	RaceControl []domain.RaceCtrlMsg `json:"race_control"`
+}
+
+// runExport replays the recording read from r without delay and writes the final state of the
+// session to w in the given format; 'csv' writes the classification and 'json' the full state.
+func runExport(l *slog.Logger, r io.Reader, format string, w io.Writer) error {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	msgs := make(chan []byte)
+	playErrCh := make(chan error, 1)
+	go func() { playErrCh <- recording.Play(ctx, recording.NewReader(r), 0, msgs) }()
+	client := f1livetiming.New(f1livetiming.WithLogger(l))
+	go client.Replay(ctx, msgs)
+
+	<-client.Done()
+	if err := <-playErrCh; err != nil {
+		return err
+	}
+	// the final state is read once every message of the recording is processed
+	snapshot := client.Snapshot()
+	export := sessionExport{
+		Meeting:     snapshot.Meeting,
+		Drivers:     classification(snapshot.Drivers),
+		RaceControl: snapshot.RaceCtrlMsgs,
+	}
+
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(export)
+	default:
+		return writeClassificationCSV(w, export.Drivers)
+	}
+}
+
+// classification returns the drivers ordered by position; drivers without a position are last.
+func classification(drivers map[string]domain.Driver) []domain.Driver {
+	sorted := make([]domain.Driver, 0, len(drivers))
+	for _, d := range drivers {
+		sorted = append(sorted, d)
+	}
+	slices.SortFunc(sorted, func(a, b domain.Driver) int {
+		pa, pb := a.TimingData.Position, b.TimingData.Position
+		if pa == 0 || pb == 0 {
+			// a zero position sorts after any other
+			return pb - pa
+		}
+		return pa - pb
+	})
+	return sorted
+}
+
+// writeClassificationCSV writes the classification as CSV with a header row.
+func writeClassificationCSV(w io.Writer, drivers []domain.Driver) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"position", "number", "driver", "abbreviation", "team", "laps", "best_lap", "leader_gap", "interval", "status"})
+	for _, d := range drivers {
+		status := ""
+		switch {
+		case d.TimingData.IsRetired:
+			status = "retired"
+		case d.TimingData.IsKnockedOut:
+			status = "knocked out"
+		}
+		cw.Write([]string{
+			strconv.Itoa(d.TimingData.Position),
+			d.Number,
+			d.Name,
+			d.ShortName,
+			d.TeamName,
+			strconv.Itoa(d.TimingData.NumberOfLaps),
+			d.TimingData.BestLapTime,
+			d.TimingData.LeaderGap,
+			d.TimingData.IntervalGap,
+			status,
+		})
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("error writing classification: %w", err)
+	}
+	return nil
+}
@@ -1,32 +1,286 @@
 package main
 
 import (
+	"cmp"
 	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
-	"github.com/bcdxn/f1cli/internal/f1livetiming"
+	"github.com/bcdxn/f1cli/f1livetiming"
+	"github.com/bcdxn/f1cli/internal/config"
 	"github.com/bcdxn/f1cli/internal/logger"
-	"github.com/bcdxn/f1cli/internal/tui"
+	"github.com/bcdxn/f1cli/internal/notify"
+	"github.com/bcdxn/f1cli/internal/recording"
+	"github.com/bcdxn/f1cli/internal/relay"
+	"github.com/bcdxn/f1cli/internal/server"
+	"github.com/bcdxn/f1cli/internal/stream"
+	"github.com/bcdxn/f1cli/tui"
+	"github.com/bcdxn/f1cli/tui/styles"
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// configFlag registers the flag configuring the path of the config file on the given flag set; the
+// returned function loads the config file once the flags are parsed, exiting if it is invalid.
+func configFlag(fs *flag.FlagSet) func() config.Config {
+	path := fs.String("config", "", "path of the TOML config file (defaults to $XDG_CONFIG_HOME/f1cli/config.toml)")
+	return func() config.Config {
+		cfg, err := config.Load(*path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid config:\n%s\n", err)
+			os.Exit(exitUsage)
+		}
+		return cfg
+	}
+}
+
+// logFlags registers the flags configuring the application log on the given flag set; the returned
+// function creates the logger once the flags are parsed, falling back to the log settings in the
+// config file. A log file that can't be opened is reported but doesn't stop the program.
+func logFlags(fs *flag.FlagSet) func(cfg config.Config) (*slog.Logger, io.Closer) {
+	level := fs.String("log-level", "", "minimum level of the log records: "+strings.Join(logger.Levels(), ", ")+" (default info)")
+	file := fs.String("log-file", "", "path of the log file (defaults to $XDG_STATE_HOME/f1cli/f1cli.log)")
+	format := fs.String("log-format", "", "encoding of the log records: 'text' or 'json' (default text)")
+	return func(cfg config.Config) (*slog.Logger, io.Closer) {
+		opts := []logger.LoggerOption{logger.WithPath(cmp.Or(*file, cfg.Log.Path))}
+		if name := cmp.Or(*level, cfg.Log.Level); name != "" {
+			lvl, err := logger.ParseLevel(name)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				fs.Usage()
+				os.Exit(exitUsage)
+			}
+			opts = append(opts, logger.WithLevel(lvl))
+		}
+		if name := cmp.Or(*format, cfg.Log.Format); name != "" {
+			f, err := logger.ParseFormat(name)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				fs.Usage()
+				os.Exit(exitUsage)
+			}
+			opts = append(opts, logger.WithFormat(f))
+		}
+		if cfg.Log.MaxSizeMB > 0 {
+			opts = append(opts, logger.WithMaxSize(int64(cfg.Log.MaxSizeMB)*1024*1024))
+		}
+		if cfg.Log.MaxBackups > 0 {
+			opts = append(opts, logger.WithMaxBackups(cfg.Log.MaxBackups))
+		}
+
+		l, f, err := logger.New(opts...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: logging disabled: %s\n", err)
+		}
+		l.Info("session started", "args", os.Args[1:])
+		return l, f
+	}
+}
+
+// upstreamFlags registers the flags configuring the F1 LiveTiming API endpoint on the given flag
+// set; the returned function builds the corresponding client options once the flags are parsed,
+// falling back to the upstream settings in the config file.
+func upstreamFlags(fs *flag.FlagSet) func(cfg config.Config) []f1livetiming.ClientOption {
+	httpURL := fs.String("http-url", "", "HTTP(S) URL of the F1 LiveTiming API, e.g. 'http://localhost:8081' to connect to a relay")
+	wsURL := fs.String("ws-url", "", "websocket URL of the F1 LiveTiming API, e.g. 'ws://localhost:8081' to connect to a relay")
+	transport := fs.String("transport", "", "protocol of the F1 LiveTiming API: 'signalr' or 'signalrcore' (default signalr)")
+	staleAfter := fs.Duration("stale-after", 30*time.Second, "how long without data before it is marked stale and the connection re-established; 0 disables")
+	return func(cfg config.Config) []f1livetiming.ClientOption {
+		opts := []f1livetiming.ClientOption{f1livetiming.WithStaleAfter(*staleAfter)}
+		if u := cmp.Or(*httpURL, cfg.Upstream.HTTPURL); u != "" {
+			opts = append(opts, f1livetiming.WithHTTPBaseURL(u))
+		}
+		if u := cmp.Or(*wsURL, cfg.Upstream.WSURL); u != "" {
+			opts = append(opts, f1livetiming.WithWSBaseURL(u))
+		}
+		if name := cmp.Or(*transport, cfg.Upstream.Transport); name != "" {
+			t, err := f1livetiming.ParseTransport(name)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				fs.Usage()
+				os.Exit(exitUsage)
+			}
+			opts = append(opts, f1livetiming.WithTransport(t))
+		}
+		return opts
+	}
+}
+
+// tuiFlags registers the flags configuring the timing board on the given flag set; the returned
+// function builds the TUI options and the notifier once the flags are parsed, falling back to the
+// config file.
+func tuiFlags(fs *flag.FlagSet) func(l *slog.Logger, cfg config.Config) ([]tui.TUIOption, *notify.Notifier, error) {
+	pitLoss := fs.Float64("pit-loss", 0, "time lost making a pit stop in seconds (learned from observed stops when not set)")
+	theme := fs.String("theme", "", "theme of the timing board: "+strings.Join(styles.Themes(), ", ")+" or a user defined theme")
+	notifier := notifierFlags(fs)
+	return func(l *slog.Logger, cfg config.Config) ([]tui.TUIOption, *notify.Notifier, error) {
+		n, err := notifier(l, cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		if *pitLoss == 0 {
+			*pitLoss = cfg.PitLoss
+		}
+		if *theme != "" {
+			if cfg, err = cfg.WithTheme(*theme); err != nil {
+				return nil, nil, err
+			}
+		}
+		return tuiOptions(cfg, *pitLoss), n, nil
+	}
+}
+
+// tuiOptions returns the options configuring the TUI from the config file.
+func tuiOptions(cfg config.Config, pitLoss float64) []tui.TUIOption {
+	return []tui.TUIOption{
+		tui.WithPitLoss(pitLoss),
+		tui.WithStyles(cfg.Styles()),
+		tui.WithColumns(cfg.Columns.Race, cfg.Columns.Qualifying),
+		tui.WithCompact(cfg.Compact),
+		tui.WithFavourites(cfg.Favourites.Drivers, cfg.Favourites.Teams),
+		tui.WithKeyMap(tui.DefaultKeyMap().WithKeys(cfg.Keybindings)),
+	}
+}
+
+// notifierFlags registers the flags configuring notifications on the given flag set; the returned
+// function builds the notifier once the flags are parsed, falling back to the notification rules
+// and favourite drivers in the config file, or returns nil if notifications are disabled.
+func notifierFlags(fs *flag.FlagSet) func(l *slog.Logger, cfg config.Config) (*notify.Notifier, error) {
+	methods := fs.String("notify", "", "comma separated terminal notification methods: 'bell', 'osc9' and/or 'osc777' (disabled when not set)")
+	command := fs.String("notify-cmd", "", "shell command run for each notification with F1_EVENT, F1_TITLE and F1_BODY set in the environment")
+	triggers := fs.String("notify-on", "", "comma separated notification triggers (all when not set): "+joinTriggers(notify.Triggers()))
+	favourites := fs.String("favourites", "", "comma separated racing numbers or abbreviations of drivers whose pit stops trigger notifications")
+	return func(l *slog.Logger, cfg config.Config) (*notify.Notifier, error) {
+		ms := orList(*methods, cfg.Notifications.Methods)
+		cmd := cmp.Or(*command, cfg.Notifications.Command)
+		if len(ms) == 0 && cmd == "" {
+			return nil, nil
+		}
+		opts := []notify.NotifierOption{
+			notify.WithLogger(l),
+			notify.WithCommand(cmd),
+			notify.WithFavourites(orList(*favourites, cfg.Favourites.Drivers)...),
+			notify.WithTmuxPassthrough(os.Getenv("TMUX") != ""),
+		}
+		nms := make([]notify.Method, 0, len(ms))
+		for _, m := range ms {
+			switch notify.Method(m) {
+			case notify.MethodBell, notify.MethodOSC9, notify.MethodOSC777:
+				nms = append(nms, notify.Method(m))
+			default:
+				return nil, fmt.Errorf("invalid notification method '%s'", m)
+			}
+		}
+		opts = append(opts, notify.WithMethods(nms...))
+		if on := orList(*triggers, cfg.Notifications.On); len(on) > 0 {
+			var ts []notify.Trigger
+			for _, t := range on {
+				if !slices.Contains(notify.Triggers(), notify.Trigger(t)) {
+					return nil, fmt.Errorf("invalid notification trigger '%s'", t)
+				}
+				ts = append(ts, notify.Trigger(t))
+			}
+			opts = append(opts, notify.WithTriggers(ts...))
+		}
+		return notify.New(opts...), nil
+	}
+}
+
+// splitList splits a comma separated flag value, ignoring surrounding whitespace and empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// orList returns the items of the comma separated flag value if it is set, otherwise the fallback.
+func orList(flagValue string, fallback []string) []string {
+	if items := splitList(flagValue); len(items) > 0 {
+		return items
+	}
+	return fallback
+}
+
+// joinTriggers returns the triggers as a comma separated list for usage messages.
+func joinTriggers(triggers []notify.Trigger) string {
+	names := make([]string, 0, len(triggers))
+	for _, t := range triggers {
+		names = append(names, string(t))
+	}
+	return strings.Join(names, ", ")
+}
+
+// source feeds the client with messages until the context is cancelled, either live from the F1
+// LiveTiming API or replayed from a recording, returning the error the client exited with.
+type source = func(ctx context.Context, c *f1livetiming.Client) error
+
+// liveSource connects the client to the F1 LiveTiming API.
+func liveSource(ctx context.Context, c *f1livetiming.Client) error {
+	c.Listen(ctx)
+	return <-c.Done()
+}
+
+// watchSource waits for the upcoming session to start before connecting the client to the F1
+// LiveTiming API.
+func watchSource(ctx context.Context, c *f1livetiming.Client) error {
+	if err := c.WaitForSession(ctx); err != nil {
+		// waiting only stops early when the context is cancelled
+		return nil
+	}
+	c.Listen(ctx)
+	return <-c.Done()
+}
+
+// replaySource replays the recording read from r at the given speed; the client keeps running once
+// the recording ends.
+func replaySource(l *slog.Logger, r io.Reader, speed float64) source {
+	return func(ctx context.Context, c *f1livetiming.Client) error {
+		msgs := make(chan []byte)
+		go func() {
+			if err := recording.Play(ctx, recording.NewReader(r), speed, msgs); err != nil {
+				l.Error("error replaying recording", "err", err)
+			}
+		}()
+		c.Replay(ctx, msgs)
+		// keep showing the end of the recording until the user quits
+		<-ctx.Done()
+		return nil
+	}
+}
+
+// runTUI feeds a client from the given source and renders the timing board. When the client exits
+// with an error the error screen is shown, from which a new client can be fed from the source. The
+// error of the last client is returned, e.g. if the user quits from the error screen.
+func runTUI(l *slog.Logger, upstream []f1livetiming.ClientOption, src source, n *notify.Notifier, opts ...tui.TUIOption) error {
 	ctx, cancelCtx := context.WithCancel(context.Background())
 	defer cancelCtx()
-	l, f := logger.New()
-	defer f.Close()
-	// Create a wait group that ensures both client *and* TUI exit gracefully if either exits
+	// Create a wait group that ensures the TUI exits gracefully if the client exits
 	wg := sync.WaitGroup{}
-	// create client responsible for listening to messags from the F1 LiveTiming API
-	client := f1livetiming.New(f1livetiming.WithLogger(l))
-	wg.Add(1)
-	go func() {
-		defer cancelCtx() // cancel the shared context between TUI and Client if either exits
-		defer wg.Done()   // decrement shared wit group between TUI and Client
-		client.Listen(ctx)
-		l.Debug("client exited")
-	}()
+	// retries requested from the error screen; buffered so the TUI never waits for the client
+	retryCh := make(chan struct{}, 1)
+	retry := func() {
+		select {
+		case retryCh <- struct{}{}:
+		default:
+		}
+	}
 	// create TUI
-	leaderboard := tui.NewLeaderboard(tui.WithContext(ctx), tui.WithLogger(l))
+	leaderboard := tui.NewLeaderboard(append(opts, tui.WithContext(ctx), tui.WithLogger(l), tui.WithRetry(retry))...)
 	wg.Add(1)
 	go func() {
 		defer cancelCtx() // cancel the shared context between TUI and Client if either exits
@@ -34,25 +288,224 @@ func main() {
 		leaderboard.Run()
 		l.Debug("tui exited")
 	}()
+
 	var err error
+	for {
+		err = runClient(ctx, l, upstream, src, n, leaderboard)
+		if ctx.Err() != nil {
+			l.Debug("context done")
+			break
+		}
+		if err == nil {
+			// the client exited without error, e.g. the connection was closed normally
+			cancelCtx()
+			break
+		}
+		l.Error("Client exited with error", "err", err)
+		leaderboard.Send(tui.ErrorMsg{Err: err})
+		select {
+		case <-ctx.Done():
+		case <-retryCh:
+			l.Info("retrying after client error")
+			continue
+		}
+		break
+	}
+	wg.Wait()
+	return err
+}
+
+// runClient feeds a new client from the given source, passing its updates to the TUI until the
+// source returns, and returns the error the client exited with.
+func runClient(ctx context.Context, l *slog.Logger, upstream []f1livetiming.ClientOption, src source, n *notify.Notifier, leaderboard *tea.Program) error {
+	ctx, cancelCtx := context.WithCancel(ctx)
+	defer cancelCtx()
+	// create client responsible for listening to messags from the F1 LiveTiming API
+	client := f1livetiming.New(append(upstream, f1livetiming.WithLogger(l))...)
+	srcErrCh := make(chan error, 1)
+	go func() { srcErrCh <- src(ctx, client) }()
+	if n != nil {
+		// notifications are observed from a subscription of their own so they don't wait for the TUI
+		sub := client.Subscribe(f1livetiming.WithTopics(f1livetiming.TopicMeeting, f1livetiming.TopicDrivers))
+		defer sub.Close()
+		go observeNotifications(ctx, sub, n)
+	}
 	// pass messages between client and TUI
+	for {
+		select {
+		case err := <-srcErrCh:
+			l.Debug("client exited", "delivery", client.DeliveryStats())
+			return err
+		case drivers := <-client.Drivers():
+			leaderboard.Send(tui.DriversMsg(drivers))
+		case meeting := <-client.Meeting():
+			leaderboard.Send(tui.MeetingMsg(meeting))
+		case raceCtrlMsg := <-client.RaceCtrlMsgs():
+			l.Debug("race control message", "msg", raceCtrlMsg)
+			leaderboard.Send(tui.RaceCtrlMsg(raceCtrlMsg))
+		}
+	}
+}
+
+// observeNotifications passes the updates of the subscription to the notifier until the context is
+// cancelled or the subscription is closed.
+func observeNotifications(ctx context.Context, sub *f1livetiming.Subscription, n *notify.Notifier) {
 	for {
 		select {
 		case <-ctx.Done():
-			l.Debug("context done")
-			wg.Wait()
 			return
-		case err = <-client.Done():
+		case drivers, ok := <-sub.Drivers():
+			if !ok {
+				return
+			}
+			n.ObserveDrivers(drivers)
+		case meeting, ok := <-sub.Meeting():
+			if !ok {
+				return
+			}
+			n.ObserveMeeting(meeting)
+		}
+	}
+}
+
+// runStream connects to the F1 LiveTiming API and writes each update as newline-delimited JSON to
+// stdout until interrupted or the connection is closed.
+func runStream(l *slog.Logger, upstream []f1livetiming.ClientOption) error {
+	ctx, cancelCtx := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancelCtx()
+
+	client := f1livetiming.New(append(upstream, f1livetiming.WithLogger(l))...)
+	go client.Listen(ctx)
+
+	enc := stream.NewEncoder(os.Stdout)
+	for {
+		var err error
+		select {
+		case <-ctx.Done():
+			l.Debug("context done")
+			return nil
+		case err, ok := <-client.Done():
 			if err != nil {
 				l.Error("Client exited with error", "err", err)
+				return err
+			}
+			if !ok {
+				l.Debug("client exited")
+				return nil
 			}
 		case drivers := <-client.Drivers():
-			leaderboard.Send(tui.DriversMsg(drivers))
+			err = enc.Encode(stream.RecordTypeDrivers, drivers)
 		case meeting := <-client.Meeting():
-			leaderboard.Send(tui.MeetingMsg(meeting))
+			err = enc.Encode(stream.RecordTypeMeeting, meeting)
 		case raceCtrlMsg := <-client.RaceCtrlMsgs():
-			l.Debug("race control message", "msg", raceCtrlMsg)
-			leaderboard.Send(tui.RaceCtrlMsg(raceCtrlMsg))
+			err = enc.Encode(stream.RecordTypeRaceControl, raceCtrlMsg)
+		}
+		if err != nil {
+			return fmt.Errorf("error writing to stdout: %w", err)
+		}
+	}
+}
+
+// runServe connects to the F1 LiveTiming API and serves the live state of the session over HTTP
+// until interrupted or the connection is closed.
+func runServe(l *slog.Logger, upstream []f1livetiming.ClientOption, addr string) error {
+	ctx, cancelCtx := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancelCtx()
+
+	client := f1livetiming.New(append(upstream, f1livetiming.WithLogger(l))...)
+	go client.Listen(ctx)
+
+	srv := server.New(server.WithLogger(l), server.WithSnapshotSource(client.Snapshot))
+	srvErrCh := make(chan error, 1)
+	go func() { srvErrCh <- srv.ListenAndServe(ctx, addr) }()
+
+	for {
+		select {
+		case err := <-srvErrCh:
+			return err
+		case err, ok := <-client.Done():
+			if err != nil {
+				l.Error("Client exited with error", "err", err)
+				cancelCtx()
+				<-srvErrCh
+				return err
+			}
+			if !ok {
+				l.Debug("client exited")
+				cancelCtx()
+				return <-srvErrCh
+			}
+		case drivers := <-client.Drivers():
+			srv.SetDrivers(drivers)
+		case meeting := <-client.Meeting():
+			srv.SetMeeting(meeting)
+		case raceCtrlMsg := <-client.RaceCtrlMsgs():
+			srv.AddRaceCtrlMsg(raceCtrlMsg)
+		}
+	}
+}
+
+// runRecord connects to the F1 LiveTiming API and records every raw message received until
+// interrupted or the connection is closed.
+func runRecord(l *slog.Logger, upstream []f1livetiming.ClientOption, w *recording.Writer) error {
+	ctx, cancelCtx := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancelCtx()
+
+	record := func(msg []byte) {
+		if err := w.Write(msg); err != nil {
+			l.Warn("error recording message", "err", err)
+		}
+	}
+	client := f1livetiming.New(append(upstream, f1livetiming.WithLogger(l), f1livetiming.WithRawMessageHandler(record))...)
+	go client.Listen(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			l.Debug("context done")
+			return nil
+		case err, ok := <-client.Done():
+			if err != nil {
+				l.Error("Client exited with error", "err", err)
+				return err
+			}
+			if !ok {
+				l.Debug("client exited")
+				return nil
+			}
+		}
+	}
+}
+
+// runRelay connects to the F1 LiveTiming API and re-broadcasts the feed to local clients until
+// interrupted or the connection is closed.
+func runRelay(l *slog.Logger, upstream []f1livetiming.ClientOption, addr string) error {
+	ctx, cancelCtx := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancelCtx()
+
+	r := relay.New(relay.WithLogger(l))
+	client := f1livetiming.New(append(upstream, f1livetiming.WithLogger(l), f1livetiming.WithRawMessageHandler(r.HandleMessage))...)
+	go client.Listen(ctx)
+
+	relayErrCh := make(chan error, 1)
+	go func() { relayErrCh <- r.ListenAndServe(ctx, addr) }()
+
+	for {
+		select {
+		case err := <-relayErrCh:
+			return err
+		case err, ok := <-client.Done():
+			if err != nil {
+				l.Error("Client exited with error", "err", err)
+				cancelCtx()
+				<-relayErrCh
+				return err
+			}
+			if !ok {
+				l.Debug("client exited")
+				cancelCtx()
+				return <-relayErrCh
+			}
 		}
 	}
 }
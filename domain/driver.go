@@ -0,0 +1,120 @@
+package domain
+
+import "strconv"
+
+const (
+	TireCompoundSoft         TireCompound = "SOFT"
+	TireCompoundMedium       TireCompound = "MEDIUM"
+	TireCompoundHard         TireCompound = "HARD"
+	TireCompoundIntermediate TireCompound = "INTERMEDIATE"
+	TireCompoundFullWet      TireCompound = "WET"
+	TireCompoundTest         TireCompound = "TEST"
+	TireCompoundUnknown      TireCompound = "UNKNOWN"
+)
+
+const (
+	SectorStatusInactive        SectorStatus = 0
+	SectorStatusPersonalBest    SectorStatus = 1
+	SectorStatusOverallBest     SectorStatus = 2
+	SectorStatusNotPersonalBest SectorStatus = 3
+)
+
+// NewDriver returns a new instance of a driver as modeled per the domain with fields initialized
+// to allow safe access (e.g. slices of appropriate length to prevent out of bounds indexing).
+func NewDriver(number string) Driver {
+	return Driver{
+		Number: number,
+		TimingData: DriverTimingData{
+			ShowPosition: true,
+			Sectors:      newSectorMap(),
+			BestLapTimes: make([]string, 3),
+			TireCompound: TireCompoundUnknown,
+		},
+	}
+}
+
+func newSectorMap() map[string]Sector {
+	m := make(map[string]Sector, 3)
+	for i := 0; i < 3; i++ {
+		m[strconv.Itoa(i)] = NewSector()
+	}
+	return m
+}
+
+func NewSector() Sector {
+	return Sector{
+		Status:   SectorStatusInactive,
+		Segments: make(map[string]Segment),
+	}
+}
+
+// TireCompound represents one of the official tire compound types used in a race weekend.
+type TireCompound string
+
+// SectorStatus represents the status of an invidvidual sector for a driver on a lap.
+type SectorStatus int
+
+// Driver domain model represent intrinsic data about a driver as well as updates to live-timing
+// data like grid position, gaps, etc.
+type Driver struct {
+	// Intrinsic Data
+	Number     string           `json:"number"`     // Number is the unique driver racing number present on their car
+	ShortName  string           `json:"short_name"` // Shortname is the name abbreviation used on the television broadcast
+	Name       string           `json:"name"`       // Name is the full name of the driver
+	TeamName   string           `json:"team_name"`  // TeamName is the short name of the team that the driver races for
+	TeamColor  string           `json:"team_color"` // TeamColor is the primary color of the team that the driver races for
+	TimingData DriverTimingData `json:"timing_data"`
+	Telemetry  Telemetry        `json:"telemetry"`
+}
+
+// Driver domain model represents intrinsic data about a driver as well as updates to live-timing
+// data like grid position, gaps, etc.
+type DriverTimingData struct {
+	// Timing data
+	Position    int      `json:"position"`     // Position is the driver's position on the timing board
+	IntervalGap string   `json:"interval_gap"` // IntervalGap is the time delta between the driver and the driver ahead
+	LeaderGap   string   `json:"leader_gap"`   // LeaderGap is the delta between the driver and the lead driver
+	LastLap     struct { // Data about the last completed lap
+		Time           string `json:"time"`             // Time is The lap time of the last lap
+		IsPersonalBest bool   `json:"is_personal_best"` // PersonalBest indicates if the last lap is a personal best for the driver
+	} `json:"last_lap"`
+	BestLapTime string `json:"best_lap_time"` // BestLapTime is the time of the best lap
+	// Stint Data
+	TireCompound TireCompound `json:"tire_compound"`  // The current tire compound that the driver is using
+	TireLapCount int          `json:"tire_lap_count"` // The current lap count that the driver is on
+	IsInPit      bool         `json:"is_in_pit"`      // InPit indicates if the driver is in the pit
+	ShowPosition bool         `json:"show_position"`  // The driver is out of the session due to crash, mechanical failure, etc.
+	IsPitOut     bool         `json:"is_pit_out"`     // PitOut indicates if the driver is on an outlap
+	// Sector times
+	Sectors map[string]Sector `json:"sectors"`
+	// Race-specific data
+	NumberOfLaps int  `json:"number_of_laps"`
+	IsRetired    bool `json:"is_retired"` // The driver is out of the session due to crash, mechanical failure, etc.
+	// Qualifying-specific data
+	BestLapTimes []string `json:"best_lap_times"` // Best times in each session part (applicable for Qualifying sessions only, e.g.: Q1, Q2, Q3)
+	IsKnockedOut bool     `json:"is_knocked_out"` // The driver did not qualify for the current session (only applicable during qualifiying session)
+	Cutoff       bool     `json:"cutoff"`         // The driver is in the cutoff zone (only applicable during qualifiying session)
+}
+
+// Sector represents timing data about individual sectors around the lap.
+type Sector struct {
+	Time     string             `json:"time"`
+	Status   SectorStatus       `json:"status"`
+	Segments map[string]Segment `json:"segments"`
+}
+
+// Segment represents timing information about the individual segments within a sector.
+type Segment struct {
+	Status SectorStatus `json:"status"`
+}
+
+// Telemetry represents the latest car data sample reported by the driver's car.
+type Telemetry struct {
+	RPM          int  `json:"rpm"`           // RPM is the engine speed in revolutions per minute
+	Speed        int  `json:"speed"`         // Speed is the car speed in km/h
+	Gear         int  `json:"gear"`          // Gear is the selected gear; 0 is neutral
+	Throttle     int  `json:"throttle"`      // Throttle is the throttle application in percent
+	Brake        bool `json:"brake"`         // Brake indicates that the brake pedal is pressed
+	DRSAvailable bool `json:"drs_available"` // DRSAvailable indicates that the driver is eligible to open DRS in the next activation zone
+	DRSOpen      bool `json:"drs_open"`      // DRSOpen indicates that the DRS flap is open
+}
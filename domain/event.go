@@ -0,0 +1,98 @@
+package domain
+
+import "time"
+
+// Event is something that happened during the session, e.g. a driver completing a lap or the track
+// status changing. Events are one of the types in this file; consumers switch on the type to handle
+// the events they are interested in.
+type Event interface {
+	// Timestamp returns when the event was published by the F1 LiveTiming API
+	Timestamp() time.Time
+}
+
+// EventMeta holds the fields shared by every event.
+type EventMeta struct {
+	At time.Time `json:"at"` // At is when the event was published by the F1 LiveTiming API
+}
+
+func (e EventMeta) Timestamp() time.Time {
+	return e.At
+}
+
+// LapCompleted is emitted when a driver completes a lap.
+type LapCompleted struct {
+	EventMeta
+	Driver         string `json:"driver"`           // Driver is the racing number of the driver
+	Lap            int    `json:"lap"`              // Lap is the number of laps the driver has completed
+	LapTime        string `json:"lap_time"`         // LapTime is the time of the completed lap
+	IsPersonalBest bool   `json:"is_personal_best"` // IsPersonalBest indicates the lap is the driver's best of the session
+}
+
+// PositionChanged is emitted when a driver moves up or down the timing board.
+type PositionChanged struct {
+	EventMeta
+	Driver string `json:"driver"` // Driver is the racing number of the driver
+	From   int    `json:"from"`
+	To     int    `json:"to"`
+}
+
+// PitEntry is emitted when a driver enters the pit lane.
+type PitEntry struct {
+	EventMeta
+	Driver string `json:"driver"` // Driver is the racing number of the driver
+}
+
+// PitExit is emitted when a driver leaves the pit lane.
+type PitExit struct {
+	EventMeta
+	Driver string `json:"driver"` // Driver is the racing number of the driver
+}
+
+// TyreChanged is emitted when a driver starts a stint on a different tire compound.
+type TyreChanged struct {
+	EventMeta
+	Driver string       `json:"driver"` // Driver is the racing number of the driver
+	From   TireCompound `json:"from"`
+	To     TireCompound `json:"to"`
+}
+
+// FastestLap is emitted when a driver sets the fastest lap of the session.
+type FastestLap struct {
+	EventMeta
+	Driver  string `json:"driver"`   // Driver is the racing number of the driver
+	LapTime string `json:"lap_time"` // LapTime is the time of the fastest lap
+}
+
+// DriverRetired is emitted when a driver retires from the session.
+type DriverRetired struct {
+	EventMeta
+	Driver string `json:"driver"` // Driver is the racing number of the driver
+}
+
+// KnockedOut is emitted when a driver is knocked out of a qualifying session.
+type KnockedOut struct {
+	EventMeta
+	Driver string `json:"driver"` // Driver is the racing number of the driver
+	Part   int    `json:"part"`   // Part is the qualifying part in which the driver was knocked out
+}
+
+// SessionStatusChanged is emitted when the session moves through its lifecycle, e.g. when it is
+// suspended by a red flag.
+type SessionStatusChanged struct {
+	EventMeta
+	From SessionStatus `json:"from"`
+	To   SessionStatus `json:"to"`
+}
+
+// TrackStatusChanged is emitted when the flag or safety car status of the track changes.
+type TrackStatusChanged struct {
+	EventMeta
+	From TrackStatus `json:"from"`
+	To   TrackStatus `json:"to"`
+}
+
+// RaceControlMessage is emitted when race control issues a message.
+type RaceControlMessage struct {
+	EventMeta
+	Msg RaceCtrlMsg `json:"msg"`
+}
@@ -0,0 +1,106 @@
+package domain
+
+import "time"
+
+// sessionLifecycle lists the statuses a session can move to from each status. A suspended session
+// is usually made pending again shortly before it is resumed, and a qualifying session is started
+// again after each part has finished.
+var sessionLifecycle = map[SessionStatus][]SessionStatus{
+	SessionStatusPending:   {SessionStatusStarted, SessionStatusFinished, SessionStatusEnded},
+	SessionStatusStarted:   {SessionStatusPending, SessionStatusSuspended, SessionStatusFinished, SessionStatusEnded},
+	SessionStatusSuspended: {SessionStatusPending, SessionStatusStarted, SessionStatusFinished, SessionStatusEnded},
+	SessionStatusFinished:  {SessionStatusPending, SessionStatusStarted, SessionStatusFinalised, SessionStatusEnded},
+	SessionStatusFinalised: {SessionStatusEnded},
+	SessionStatusEnded:     {},
+}
+
+// SessionTransition is the event of the session moving from one status to another.
+type SessionTransition struct {
+	From SessionStatus `json:"from"`
+	To   SessionStatus `json:"to"`
+	Time time.Time     `json:"time"` // Time is when the status changed
+}
+
+// ParseSessionStatus returns the session status corresponding to a status published by the F1
+// LiveTiming API, e.g. "Aborted" when the session is suspended by a red flag.
+func ParseSessionStatus(status string) (SessionStatus, bool) {
+	switch status {
+	case "Inactive":
+		return SessionStatusPending, true
+	case "Started":
+		return SessionStatusStarted, true
+	case "Aborted":
+		return SessionStatusSuspended, true
+	case "Finished":
+		return SessionStatusFinished, true
+	case "Finalised":
+		return SessionStatusFinalised, true
+	case "Ends":
+		return SessionStatusEnded, true
+	default:
+		return "", false
+	}
+}
+
+// CanTransition indicates that a session can move from the status to the given status.
+func (s SessionStatus) CanTransition(to SessionStatus) bool {
+	for _, next := range sessionLifecycle[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition moves the session to the given status at the given time, recording and returning the
+// transition. The session is unchanged if it already has the status or can't move to it, e.g. once
+// it has ended.
+func (s *Session) Transition(to SessionStatus, at time.Time) (SessionTransition, bool) {
+	if !s.Status.CanTransition(to) {
+		return SessionTransition{}, false
+	}
+	t := SessionTransition{From: s.Status, To: to, Time: at}
+	s.Status = to
+	s.Transitions = append(s.Transitions, t)
+	return t, true
+}
+
+// StatusSince returns when the session moved to its current status; the zero time is returned if
+// the status never changed.
+func (s Session) StatusSince() time.Time {
+	if len(s.Transitions) == 0 {
+		return time.Time{}
+	}
+	return s.Transitions[len(s.Transitions)-1].Time
+}
+
+// Suspensions returns the number of times the session was suspended by a red flag.
+func (s Session) Suspensions() int {
+	n := 0
+	for _, t := range s.Transitions {
+		if t.To == SessionStatusSuspended {
+			n++
+		}
+	}
+	return n
+}
+
+// HasFinished indicates that the chequered flag has been shown since the session was last started,
+// i.e. the session has a provisional or final classification.
+func (s Session) HasFinished() bool {
+	for i := len(s.Transitions) - 1; i >= 0; i-- {
+		switch s.Transitions[i].To {
+		case SessionStatusStarted:
+			return false
+		case SessionStatusFinished, SessionStatusFinalised:
+			return true
+		}
+	}
+	return false
+}
+
+// IsClassificationFinal indicates that the classification of the finished session is final rather
+// than provisional.
+func (s Session) IsClassificationFinal() bool {
+	return s.HasFinished() && (s.Status == SessionStatusFinalised || s.Status == SessionStatusEnded)
+}
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	SessionTypeTest        SessionType   = "TEST"
+	SessionTypePractice    SessionType   = "PRACTICE"
+	SessionTypeQualifying  SessionType   = "QUALIFYING"
+	SessionTypeRace        SessionType   = "RACE"
+	SessionTypeUnknown     SessionType   = "UNKNOWN"
+	SessionStatusPending   SessionStatus = "PENDING"   // the session hasn't started, or is about to be resumed
+	SessionStatusStarted   SessionStatus = "STARTED"   // the session is running
+	SessionStatusSuspended SessionStatus = "SUSPENDED" // the session is suspended by a red flag
+	SessionStatusFinished  SessionStatus = "FINISHED"  // the chequered flag has been shown; the classification is provisional
+	SessionStatusFinalised SessionStatus = "FINALISED" // the classification is final
+	SessionStatusEnded     SessionStatus = "ENDED"     // the session is over and no more data will be published
+)
+
+const (
+	TrackStatusClear     TrackStatus = "CLEAR"
+	TrackStatusYellow    TrackStatus = "YELLOW"
+	TrackStatusSC        TrackStatus = "SAFETY_CAR"
+	TrackStatusVSC       TrackStatus = "VIRTUAL_SAFETY_CAR"
+	TrackStatusVSCEnding TrackStatus = "VIRTUAL_SAFETY_CAR_ENDING"
+	TrackStatusRed       TrackStatus = "RED"
+	TrackStatusUnknown   TrackStatus = "UNKNOWN"
+)
+
+// NewMeeting returns a new instance of a meeting which represents data about a race weekend
+// holistically as well as session-specific data as modeled per the domain with fields initialized
+// to allow safe access (e.g. slices of appropriate length to prevent out of bounds indexing).
+func NewMeeting() Meeting {
+	return Meeting{
+		Session: Session{
+			Type:               SessionTypeUnknown,
+			Status:             SessionStatusPending,
+			TrackStatus:        TrackStatusUnknown,
+			GMTOffset:          "+0000",
+			FastestSectorOwner: make([]string, 3),
+			Transitions:        make([]SessionTransition, 0),
+		},
+	}
+}
+
+// The types of sessions of within a race weekend, e.g.: Practice, Qualifying, Race, etc.
+type SessionType string
+
+// The enumerated session statuses
+type SessionStatus string
+
+// The enumerated track statuses, e.g.: flags and (virtual) safety cars
+type TrackStatus string
+
+// IsNeutralised indicates that the race is neutralised by a (virtual) safety car or red flag.
+func (t TrackStatus) IsNeutralised() bool {
+	return t == TrackStatusSC || t == TrackStatusVSC || t == TrackStatusVSCEnding || t == TrackStatusRed
+}
+
+// Meeting represents data about the race weekend event. This data applies to all of the sessions
+// within a race weekend.
+type Meeting struct {
+	Name             string  `json:"name"`               // Name is the informal name of the race weekend event
+	FullName         string  `json:"full_name"`          // FullName is the full official name of the event including primary sponsor
+	Location         string  `json:"location"`           // Location is the locality in which the race weekend is taking place
+	RoundNumber      int     `json:"round_number"`       // The sequence number of the race weekend event within the season
+	CountryCode      string  `json:"country_code"`       // The 2-3 letter code indicating the country in which the event is taking place
+	CountryName      string  `json:"country_name"`       // The full name of the country in which the event is taking place
+	CircuitShortName string  `json:"circuit_short_name"` // The informal name of the circuit at which the event is taking place
+	Session          Session `json:"session"`            // A Race Weekend is composed of multiple sessions; only the active session is represented
+	Weather          Weather `json:"weather"`            // Weather is the latest weather reported at the circuit
+	Feed             Feed    `json:"feed"`               // Feed is the freshness of live data; zero when the data isn't live, e.g. replayed
+}
+
+// Session represents a specific session within a meeting, e.g.: Practice 1, Qualifying, Race
+type Session struct {
+	Type               SessionType         `json:"type"`
+	Name               string              `json:"name"`                 // The name of the session, e.g.: "Practice 1", "Race", etc.
+	Status             SessionStatus       `json:"status"`               // The pending, started, ended, etc. status of the session
+	Transitions        []SessionTransition `json:"transitions"`          // Transitions are the changes of the session status, oldest first
+	TrackStatus        TrackStatus         `json:"track_status"`         // The current flag or (virtual) safety car status of the track
+	StartDate          time.Time           `json:"start_date"`           // The start of the session
+	EndDate            time.Time           `json:"end_date"`             // The end time of the session - will be zerovalue until session has ended
+	GMTOffset          string              `json:"gmt_offset"`           // GMTOffset is the track-timezone delta with GMT/UTC
+	FastestLapOwner    string              `json:"fastest_lap_owner"`    // FastestLapOwner is the number of the driver that has the fastest lap in the session
+	FastestLapTime     string              `json:"fastest_lap_time"`     // FastestLapTime is the time of the fastest lap of the session
+	FastestSectorOwner []string            `json:"fastest_sector_owner"` // The owner of the fastest time in each sector
+	CurrentLap         int                 `json:"current_lap"`          // The current lead lap (only applicable for races)
+	TotalLaps          int                 `json:"total_laps"`           // The total number of planned laps (only applicable for races)
+	Part               int                 `json:"part"`                 // Part 0-based index, indicating the current part multi-part sessions, e.g.: Qualifying
+}
+
+// Feed describes the freshness of the data received live from the F1 LiveTiming API, tracked from
+// the heartbeats published by the API alongside the data.
+type Feed struct {
+	Heartbeat  time.Time     `json:"heartbeat"`   // Heartbeat is the server time of the latest heartbeat
+	ReceivedAt time.Time     `json:"received_at"` // ReceivedAt is the local time the latest heartbeat was received
+	Delay      time.Duration `json:"delay"`       // Delay is how long after its server time the latest heartbeat was received
+	Stale      bool          `json:"stale"`       // Stale indicates that no data was received for too long and the connection is being re-established
+}
+
+// IsLive indicates that the data is received live, i.e. a heartbeat has been received.
+func (f Feed) IsLive() bool {
+	return !f.ReceivedAt.IsZero()
+}
+
+// Weather represents the latest weather conditions reported by the weather station at the circuit.
+type Weather struct {
+	AirTemp       float64 `json:"air_temp"`       // AirTemp is the air temperature in degrees Celsius
+	TrackTemp     float64 `json:"track_temp"`     // TrackTemp is the track surface temperature in degrees Celsius
+	Humidity      float64 `json:"humidity"`       // Humidity is the relative humidity in percent
+	Pressure      float64 `json:"pressure"`       // Pressure is the air pressure in millibars
+	Rainfall      bool    `json:"rainfall"`       // Rainfall indicates that it is raining
+	WindSpeed     float64 `json:"wind_speed"`     // WindSpeed is the wind speed in metres per second
+	WindDirection int     `json:"wind_direction"` // WindDirection is the direction the wind blows from in degrees
+}
@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 const (
 	RaceCtrlMsgCategoryTrackStatus = "TRACK_STATUS"
 	RaceCtrlMsgCategoryFIA         = "FIA"
@@ -23,7 +25,9 @@ const (
 type RaceCtrlMsgCategory string
 
 type RaceCtrlMsg struct {
-	Category RaceCtrlMsgCategory
-	Title    string
-	Body     string
+	Category RaceCtrlMsgCategory `json:"category"`
+	Title    string              `json:"title"`
+	Body     string              `json:"body"`
+	Time     time.Time           `json:"time"` // Time is when the message was issued by race control
+	Lap      int                 `json:"lap"`  // Lap is the lead lap on which the message was issued (only applicable for races)
 }
@@ -0,0 +1,1573 @@
+package f1livetiming
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bcdxn/f1cli/domain"
+	"github.com/coder/websocket"
+)
+
+// New returns a new F1 LiveTiming API Client.
+func New(opts ...ClientOption) *Client {
+	// create a default instance of the client
+	c := &Client{
+		drivers:     make(map[string]domain.Driver),
+		meeting:     domain.NewMeeting(),
+		doneCh:      make(chan error, 1), // buffered so the client can exit before the error is read
+		subs:        make(map[*Subscription]struct{}),
+		bufferSize:  defaultBufferSize,
+		transport:   TransportSignalR,
+		staleAfter:  defaultStaleAfter,
+		retryFor:    defaultRetryFor,
+		logger:      slog.Default(),
+		httpBaseURL: "https://livetiming.formula1.com",
+		wsBaseURL:   "wss://livetiming.formula1.com",
+		minPoll:     5 * time.Second,
+		maxPoll:     5 * time.Minute,
+	}
+	// apply given options
+	for _, opt := range opts {
+		opt(c)
+	}
+	// the channels of the client are a subscription to every update, buffered as sized by the options
+	c.sub = c.Subscribe()
+	c.snapshot.Store(&Snapshot{Meeting: c.meeting, Drivers: map[string]domain.Driver{}})
+	// return new instance of the client
+	return c
+}
+
+type Client struct {
+	// Internal Session State
+	drivers      map[string]domain.Driver
+	meeting      domain.Meeting
+	raceCtrlMsg  domain.RaceCtrlMsg
+	raceCtrlMsgs []domain.RaceCtrlMsg // raceCtrlMsgs are every race control message of the session, oldest first
+	events       []domain.Event       // events are derived from the message being processed
+	msgTime      time.Time            // msgTime is when the message being processed was published
+	receivedAt   time.Time            // receivedAt is the local time the message being processed was received
+	// SignalR connection state
+	connectionToken   string
+	cookie            string
+	messageID         string        // messageID is the cursor of the latest message received
+	groupsToken       string        // groupsToken is the token of the groups the connection belongs to
+	keepAliveTimeout  time.Duration // keepAliveTimeout is how long the connection may be silent before it is considered lost
+	disconnectTimeout time.Duration // disconnectTimeout is how long a lost connection may be resumed for
+	connectedAt       time.Time     // connectedAt is the local time the current connection was established
+	retryFor          time.Duration // retryFor is how long a lost connection is retried for since data was last received
+	// freshness of the live data
+	live       bool          // live indicates that messages are received from the F1 LiveTiming API rather than replayed
+	lastData   time.Time     // lastData is the local time the latest heartbeat or change was received, or listening started
+	staleAfter time.Duration // staleAfter is how long the connection may go without data before it is considered dead
+	// broadcast delay
+	delay       *Delay       // delay is the delay applied to the live data; nil if the data isn't delayed
+	buffer      *delayBuffer // buffer holds the data received until it is due while listening with a delay
+	receivedLap int          // receivedLap is the lap counter of the latest data received, which may not be processed yet
+	// channels
+	sub    *Subscription // sub is the subscription exposed by the channel getters of the client
+	doneCh chan error
+	// snapshot of the session state for concurrent callers of Snapshot
+	snapshot atomic.Pointer[Snapshot]
+	// subscriptions to the updates
+	subsMu     sync.Mutex
+	subs       map[*Subscription]struct{}
+	bufferSize int
+	// F1 Live Timing API Configuration
+	httpBaseURL string
+	wsBaseURL   string
+	transport   Transport
+	// session info polling while waiting for a session
+	minPoll time.Duration
+	maxPoll time.Duration
+	// raw message handler
+	rawMessageHandler func(msg []byte)
+	// logger
+	logger *slog.Logger
+}
+
+/* Client Optional Functional Parameters
+------------------------------------------------------------------------------------------------- */
+
+type ClientOption = func(c *Client)
+
+// WithHTTPBaseURL configures the HTTP(S) URL of the F1 LiveTiming API; primarily used for testing.
+func WithHTTPBaseURL(baseUrl string) ClientOption {
+	return func(c *Client) { c.httpBaseURL = baseUrl }
+}
+
+// WithWSBaseURL configures the websocket URL of the F1 LiveTiming API; primarily used for
+// testing.
+func WithWSBaseURL(baseUrl string) ClientOption {
+	return func(c *Client) { c.wsBaseURL = baseUrl }
+}
+
+// WithTransport configures the protocol used to connect to the F1 LiveTiming API; the legacy
+// SignalR endpoint is used by default.
+func WithTransport(t Transport) ClientOption {
+	return func(c *Client) { c.transport = t }
+}
+
+// WithStaleAfter configures how long the connection may go without a heartbeat or change before the
+// data is marked stale and the connection is considered dead and re-established; 0 disables the
+// detection.
+func WithStaleAfter(d time.Duration) ClientOption {
+	return func(c *Client) { c.staleAfter = d }
+}
+
+// WithRetryFor configures how long a lost connection is retried for since data was last received
+// before the client gives up and exits with ErrUpstreamClosed.
+func WithRetryFor(d time.Duration) ClientOption {
+	return func(c *Client) { c.retryFor = d }
+}
+
+// WithDelay configures the broadcast delay applied to the live data, e.g. to sync it with a TV
+// broadcast; the data is held for the delay after it is received before it is processed.
+func WithDelay(d *Delay) ClientOption {
+	return func(c *Client) { c.delay = d }
+}
+
+// WithLogger configures the logger to use within the client.
+func WithLogger(l *slog.Logger) ClientOption {
+	return func(c *Client) { c.logger = l }
+}
+
+// WithRawMessageHandler configures a function that is called with every raw message received from
+// the F1 LiveTiming API before it is processed, e.g. to re-broadcast the feed.
+func WithRawMessageHandler(h func(msg []byte)) ClientOption {
+	return func(c *Client) { c.rawMessageHandler = h }
+}
+
+// WithPollInterval configures the shortest and longest delay between requests for the session info
+// while waiting for a session to start; failed requests are retried with a delay doubling from the
+// shortest to the longest.
+func WithPollInterval(minPoll, maxPoll time.Duration) ClientOption {
+	return func(c *Client) {
+		c.minPoll = minPoll
+		c.maxPoll = maxPoll
+	}
+}
+
+// WithBufferSize configures the number of race control messages and events buffered for consumers
+// that aren't keeping up; further messages and events are dropped until the buffer is read.
+func WithBufferSize(size int) ClientOption {
+	return func(c *Client) { c.bufferSize = size }
+}
+
+/* Client API
+------------------------------------------------------------------------------------------------- */
+
+// DriversCh exposes the drivers channel as read-only; a full snapshot of the drivers' intrinsic
+// data and timing data can be read from this channel on each update from the F1 LiveTiming API. Only
+// the latest snapshot is kept, so a consumer that isn't keeping up skips to the latest state. The
+// sectors and best lap times of unchanged drivers are shared between snapshots and must not be
+// modified.
+func (c *Client) Drivers() <-chan map[string]domain.Driver {
+	return c.sub.Drivers()
+}
+
+// MeetingCh exposes the meeting channel as read-only; a full snapshot of the meeting and current
+// session data can be read from this channel on each update from the F1 LiveTiming API. Only the
+// latest snapshot is kept, so a consumer that isn't keeping up skips to the latest state.
+func (c *Client) Meeting() <-chan domain.Meeting {
+	return c.sub.Meeting()
+}
+
+// RaceCtrlMsgsCh exposes the race control messages channel as read-only; a full list of all race
+// control messages can be read from this channel on each update from the F1 LiveTiming API. The
+// channel is buffered and messages are dropped when it is full; see WithBufferSize.
+func (c *Client) RaceCtrlMsgs() <-chan domain.RaceCtrlMsg {
+	return c.sub.RaceCtrlMsgs()
+}
+
+// Events exposes the events channel as read-only; every change of the session derived from the
+// updates of the F1 LiveTiming API, e.g. a driver completing a lap or the track status changing, can
+// be read from this channel as a typed domain.Event. The channel is buffered and events are dropped
+// when it is full, so it doesn't need to be read by consumers only interested in snapshots. No events
+// are derived from the initial state of the session received when connecting.
+func (c *Client) Events() <-chan domain.Event {
+	return c.sub.Events()
+}
+
+// DeliveryStats returns the number of updates that weren't delivered because consumers weren't
+// keeping up; it is safe to call while the client is running.
+func (c *Client) DeliveryStats() DeliveryStats {
+	return c.sub.DeliveryStats()
+}
+
+// DoneCh allows the client to signal to the caller that it has exited; this can happen if an error
+// occurs or if the websocket connection is closed by the server. The channel is closed once the
+// client exits, after the error wrapping one of the client errors, e.g. ErrDial, if any.
+func (c *Client) Done() <-chan error {
+	return c.doneCh
+}
+
+func (c *Client) Listen(ctx context.Context) {
+	defer close(c.doneCh)
+	c.live = true
+	stopDelay := func(drain bool) {}
+	if c.delay != nil {
+		stopDelay = c.delayFrames(ctx)
+	}
+	err := c.listen(ctx)
+	// the data held is still processed once the connection is closed normally
+	stopDelay(err == nil)
+	// cancelling the context isn't an error; the client exits cleanly
+	if err != nil && ctx.Err() == nil {
+		c.logger.Error("client exited with error", "err", err)
+		c.doneCh <- err
+	}
+}
+
+// listen connects to the F1 LiveTiming API using the configured transport and processes the
+// messages received until the context is cancelled or the connection is closed; a lost connection
+// to the legacy SignalR endpoint is resumed from the latest message received, or replaced by a new
+// connection once it can't be resumed anymore. The error returned wraps one of the client errors.
+func (c *Client) listen(ctx context.Context) error {
+	c.lastData = time.Now()
+	if c.transport == TransportSignalRCore {
+		return c.listenCore(ctx)
+	}
+	conn, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	for {
+		err := c.readMessages(ctx, conn)
+		if !errors.Is(err, errConnectionLost) {
+			return err
+		}
+		c.logger.Warn("connection lost; reconnecting", "err", err, "message_id", c.messageID)
+		c.process(time.Now(), c.markStale)
+		if conn, err = c.reconnect(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("%w: error reconnecting: %w", ErrUpstreamClosed, err)
+		}
+		c.logger.Info("reconnected", "message_id", c.messageID)
+	}
+}
+
+// Replay processes raw messages previously received from the F1 LiveTiming API, e.g. read from a
+// recording, as if they were received over the websocket connection. The client exits once the msgs
+// channel is closed or the context is cancelled.
+func (c *Client) Replay(ctx context.Context, msgs <-chan []byte) {
+	defer close(c.doneCh)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-msgs:
+			if !ok {
+				return
+			}
+			if c.rawMessageHandler != nil {
+				c.rawMessageHandler(msg)
+			}
+			// recordings are replayed as they are, e.g. including keep-alives and reconnect requests
+			if err := c.processMessage(msg); err != nil {
+				c.logger.Warn("error processing recorded message", "err", err)
+			}
+		}
+	}
+}
+
+/* Private Helper Functions
+------------------------------------------------------------------------------------------------- */
+
+// negotiate calls the F1 LiveTiming API, retreiving information required to start the websocket
+// connection required to receive real-time updates.
+func (c *Client) negotiate(ctx context.Context) error {
+	req, err := c.negotiateRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending f1 livetiming api negotiation request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		n, err := c.parseNegotiateResponse(resp.Body)
+		if err != nil {
+			return fmt.Errorf("%w: error parsing negotiate response: %w", ErrProtocol, err)
+		}
+		if n.ConnectionToken == "" {
+			return fmt.Errorf("%w: missing connection token", ErrProtocol)
+		}
+		c.connectionToken = n.ConnectionToken
+		c.cookie = resp.Header.Get("set-cookie")
+		c.keepAliveTimeout = seconds(n.KeepAliveTimeout)
+		c.disconnectTimeout = seconds(n.DisconnectTimeout)
+		c.logger.Debug("successfully negotiated connection; connection token len:", "token_length", len(n.ConnectionToken),
+			"keep_alive_timeout", c.keepAliveTimeout, "disconnect_timeout", c.disconnectTimeout)
+		return nil
+	default:
+		return fmt.Errorf("unexpected status: %w", errors.New(resp.Status))
+	}
+}
+
+// negotiateRequest creates the HTTP request object that is required to initiate the connection to
+// the F1 Live Timing Signalr API.
+func (c *Client) negotiateRequest(ctx context.Context) (*http.Request, error) {
+	u, err := url.Parse(c.httpBaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTPBaseURL: %w", err)
+	}
+
+	negotiateURL := &url.URL{
+		Scheme: u.Scheme,
+		Host:   u.Host,
+		Path:   "/signalr/negotiate",
+		RawQuery: url.Values{
+			"connectionData": {`[{"Name":"Streaming"}]`},
+			"clientProtocol": {"1.5"},
+		}.Encode(),
+	}
+
+	return http.NewRequestWithContext(ctx, http.MethodPost, negotiateURL.String(), nil)
+}
+
+// sendSubscribeMsg sends a message that tells the server which types of data messages we would like
+// to receive as required by the F1 Live Timing API. The reference message is the result of the
+// invocation, identified by subscribeInvocationID.
+func (*Client) sendSubscribeMsg(ctx context.Context, conn *websocket.Conn) error {
+	msg, err := json.Marshal(struct {
+		Hub       string     `json:"H"`
+		Method    string     `json:"M"`
+		Arguments [][]string `json:"A"`
+		ID        int        `json:"I"`
+	}{"Streaming", "Subscribe", [][]string{subscribeTopics}, 1})
+	if err != nil {
+		return err
+	}
+	return conn.Write(ctx, websocket.MessageText, msg)
+}
+
+// parseNegotiateResponse is a helper function that parses the negotiate response body. The
+// connectionToken is required in the subsequent connect request that creates the websocket
+// connection, and the timeouts configure how lost connections are detected and resumed.
+func (*Client) parseNegotiateResponse(body io.ReadCloser) (negotiateResponse, error) {
+	var n negotiateResponse
+
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return n, err
+	}
+
+	err = json.Unmarshal(b, &n)
+	return n, err
+}
+
+// websocketURL is a helper method that generates the URL with appropriate query parameters
+// required to start the websocket connection at the given path; resuming a connection requires the
+// cursor of the latest message and the groups token.
+func (c *Client) websocketURL(path string) (*url.URL, error) {
+	var u *url.URL
+	u, err := url.Parse(c.wsBaseURL)
+	if err != nil {
+		return u, fmt.Errorf("invalid HTTPBaseURL: %w", err)
+	}
+
+	query := url.Values{
+		"connectionData":  {`[{"Name":"Streaming"}]`},
+		"connectionToken": {c.connectionToken},
+		"clientProtocol":  {"1.5"},
+		"transport":       {"webSockets"},
+	}
+	if path == reconnectPath {
+		query.Set("messageId", c.messageID)
+		if c.groupsToken != "" {
+			query.Set("groupsToken", c.groupsToken)
+		}
+	}
+
+	u = &url.URL{
+		Scheme:   u.Scheme,
+		Host:     u.Host,
+		Path:     path,
+		RawQuery: query.Encode(),
+	}
+
+	return u, nil
+}
+
+// processMessage checks the message coming the F1 LiveTiming Client to see if it is a 'change'
+// message or a 'reference' message and handles them appropriately, transforming the message into
+// 1 to none or many messages that can be written to the client channels. The SignalR protocol
+// fields of the message are tracked to resume the connection; an error is returned for messages
+// that can't be parsed, failed invocations, and errConnectionLost when the server asks the client to
+// reconnect.
+func (c *Client) processMessage(msg []byte) error {
+	// Always try to parse a change message first since there is only 1 reference message and
+	// tens of thousands of change messages over the course of a session
+	var f1msg f1Message
+	err := json.Unmarshal(msg, &f1msg)
+	if err != nil {
+		return fmt.Errorf("%w: unknown message format: %w", ErrProtocol, err)
+	}
+
+	// the data of a message asking the client to reconnect is processed before reconnecting
+	protocolErr := c.processProtocolFields(f1msg)
+	if protocolErr != nil && !errors.Is(protocolErr, errConnectionLost) {
+		return protocolErr
+	}
+
+	if len(f1msg.Changes) > 0 || len(f1msg.Reference) > 0 {
+		receivedAt := time.Now()
+		c.lastData = receivedAt
+		c.receiveLap(f1msg, receivedAt)
+		c.process(receivedAt, func() { c.processData(f1msg, receivedAt) })
+	}
+
+	return protocolErr
+}
+
+// processData handles the change and reference data of a message received at the given time.
+func (c *Client) processData(f1msg f1Message, receivedAt time.Time) {
+	c.receivedAt = receivedAt
+	c.clearStale()
+
+	if len(f1msg.Changes) > 0 {
+		c.logger.Debug("received change data message")
+		c.processChangeMessage(f1msg.Changes)
+	}
+
+	if len(f1msg.Reference) > 0 {
+		c.logger.Debug("received reference data message")
+		c.processReferenceMessage(f1msg.Reference)
+	}
+}
+
+// processChangeMessage handles an incoming change message from the F1 Live Timing API; change
+// messages represent deltas to the original reference message and all preceeding change messages.
+// Once processed, a simplified event is emitted for use by the TUI.
+func (c *Client) processChangeMessage(changesRawMessage []byte) {
+	var changesMsg []f1ChangeMessage
+	err := json.Unmarshal(changesRawMessage, &changesMsg)
+	if err != nil {
+		c.logger.Warn("error unmarshalling change message", "msg", string(changesRawMessage), "err", err)
+		return
+	}
+	meetingUpdating := false
+	driversUpdated := false
+	raceCtrlMsgsUpdated := false
+	for _, m := range changesMsg {
+		if len(m.Arguments) == 3 {
+			var s, d, r bool
+			var msgType string
+			err := json.Unmarshal(m.Arguments[0], &msgType)
+			if err != nil {
+				c.logger.Warn("invalid message type argument", "arg", string(m.Arguments[0]))
+				continue
+			}
+			msgData := m.Arguments[1]
+			c.msgTime = c.unmarshalTimestamp(m.Arguments[2])
+
+			switch msgType {
+			case "Heartbeat":
+				s, d, r = c.updateHeartbeat(c.unmarshalHeartbeatMsg(msgData))
+			case "DriverList":
+				s, d, r = c.updateDriverList(c.unmarshalDriverListMsg(msgData))
+			case "TimingData":
+				s, d, r = c.updateTimingData(c.unmarshalTimingDataMsg(msgData))
+			case "SessionInfo":
+				s, d, r = c.updateSessionInfo(c.unmarshalSessionInfoMsg(msgData))
+			case "SessionData":
+				s, d, r = c.updateSessionData(c.unmarshalSessionDataMsg(msgData))
+			case "SessionStatus":
+				s, d, r = c.updateSessionStatus(c.unmarshalSessionStatusMsg(msgData))
+			case "LapCount":
+				s, d, r = c.updateLapCount(c.unmarshalLapCountMsg(msgData))
+			case "TimingAppData":
+				s, d, r = c.updateTimingAppData(c.unmarshalTimingAppDataMsg(msgData))
+			case "RaceControlMessages":
+				s, d, r = c.updateRaceCtrlMsg(c.unmarshalRaceCtrlMsg(msgData))
+			case "TrackStatus":
+				s, d, r = c.updateTrackStatus(c.unmarshalTrackStatusMsg(msgData))
+			case "WeatherData":
+				s, d, r = c.updateWeatherData(c.unmarshalWeatherDataMsg(msgData))
+			case "CarData.z":
+				s, d, r = c.updateCarData(c.unmarshalCarDataMsg(msgData))
+			default:
+				c.logger.Warn("unknown change message", "type", msgType, "msg", string(msgData))
+			}
+
+			if s {
+				meetingUpdating = true
+			}
+			if d {
+				driversUpdated = true
+			}
+			if r {
+				raceCtrlMsgsUpdated = true
+			}
+		} else {
+			c.logger.Warn("invalid length of change message arguments", "args", m.Arguments)
+		}
+	}
+
+	if meetingUpdating || driversUpdated || raceCtrlMsgsUpdated {
+		c.publishSnapshot(driversUpdated)
+	}
+	if meetingUpdating {
+		c.writeMeetingToChan()
+	}
+	if driversUpdated {
+		c.writeDriversToChan()
+	}
+	if raceCtrlMsgsUpdated {
+		c.writeRaceCtrlMsgsToChan()
+	}
+	c.writeEventsToChan()
+}
+
+func (c *Client) processReferenceMessage(referenceRawMsg []byte) {
+	var refMsg f1ReferenceMessage
+	err := json.Unmarshal(referenceRawMsg, &refMsg)
+	if err != nil {
+		c.logger.Warn("error unmarshalling reference message", "msg", string(referenceRawMsg), "err", err)
+		return
+	}
+
+	hb := c.unmarshalHeartbeatMsg(refMsg.Heartbeat)
+	c.msgTime = hb.ReceivedAt
+	// the reference message holds every status of the session, and is sent again on each new
+	// connection, so the lifecycle is replayed from the start rather than appended to
+	c.meeting.Session.Status = domain.SessionStatusPending
+	c.meeting.Session.Transitions = make([]domain.SessionTransition, 0)
+	c.updateHeartbeat(hb)
+	c.updateSessionInfo(c.unmarshalSessionInfoMsg(refMsg.SessionInfo))
+	c.updateSessionData(c.unmarshalSessionDataMsg(refMsg.SessionData))
+	c.updateSessionStatus(c.unmarshalSessionStatusMsg(refMsg.SessionStatus))
+	c.updateTrackStatus(c.unmarshalTrackStatusMsg(refMsg.TrackStatus))
+	c.updateDriverList(c.unmarshalDriverListMsg(refMsg.DriverList))
+	c.updateLapCount(c.unmarshalLapCountMsg(refMsg.LapCount))
+	c.updateTimingData(c.unmarshalTimingDataMsg(refMsg.TimingData))
+	c.updateTimingAppData(c.unmarshalTimingAppDataMsg(refMsg.TimingAppData))
+	// the reference message holds every race control message of the session
+	c.raceCtrlMsgs = nil
+	c.updateRaceCtrlMsg(c.unmarshalRaceCtrlMsg(refMsg.RaceCtrlMsgs))
+	c.updateWeatherData(c.unmarshalWeatherDataMsg(refMsg.WeatherData))
+	c.updateCarData(c.unmarshalCarDataMsg(refMsg.CarData))
+	// The reference message always updates all channels
+	// the reference message is the initial state of the session rather than a change
+	c.events = c.events[:0]
+	c.publishSnapshot(true)
+	c.writeMeetingToChan()
+	c.writeDriversToChan()
+	c.writeRaceCtrlMsgsToChan()
+}
+
+/* Message Unmarshalers
+------------------------------------------------------------------------------------------------- */
+
+const (
+	f1APIDateLayout = "2006-01-02T15:04:05-0700" // date format used by the F1 LiveTiming API
+	f1APIUTCLayout  = "2006-01-02T15:04:05"      // UTC timestamp format used by the F1 LiveTiming API
+)
+
+// unmarshalSessionInfo converts the websocket message to a strongly typed struct.
+func (c *Client) unmarshalSessionInfoMsg(msg []byte) sessionInfo {
+	var s sessionInfo
+	err := json.Unmarshal(msg, &s)
+	if err != nil {
+		c.logger.Warn("session info msg in unknown format", "msg", string(msg))
+	}
+	return s
+}
+
+// unmarshalSessionData converts the websocket message to a strongly typed struct.
+func (c *Client) unmarshalSessionDataMsg(msg []byte) sessionData {
+	var s sessionData
+	err := json.Unmarshal(msg, &s)
+	if err != nil {
+		c.logger.Warn("session data msg in unknown format", "msg", string(msg))
+	}
+	return s
+}
+
+// unmarshalSessionStatusMsg converts the websocket message to a strongly typed struct.
+func (c *Client) unmarshalSessionStatusMsg(msg []byte) sessionStatus {
+	var s sessionStatus
+	if len(msg) == 0 {
+		return s
+	}
+	err := json.Unmarshal(msg, &s)
+	if err != nil {
+		c.logger.Warn("session status msg in unknown format", "msg", string(msg))
+	}
+	return s
+}
+
+// unmarshalHeartbeatMsg converts the websocket message to a strongly typed struct.
+func (c *Client) unmarshalHeartbeatMsg(msg []byte) heartbeat {
+	var h heartbeat
+	if len(msg) == 0 {
+		return h
+	}
+	err := json.Unmarshal(msg, &h)
+	if err != nil {
+		c.logger.Warn("heartbeat msg in unknown format", "msg", string(msg))
+	}
+	return h
+}
+
+// unmarshalTimestamp converts the timestamp argument of a change message, i.e. when the change was
+// published, to a time; the zero time is returned if it is missing or invalid.
+func (c *Client) unmarshalTimestamp(arg []byte) time.Time {
+	var t time.Time
+	err := json.Unmarshal(arg, &t)
+	if err != nil {
+		c.logger.Warn("change message timestamp in unknown format", "arg", string(arg))
+	}
+	return t
+}
+
+// unmarshalTrackStatusMsg converts the websocket message to a strongly typed struct.
+func (c *Client) unmarshalTrackStatusMsg(msg []byte) trackStatus {
+	var ts trackStatus
+	err := json.Unmarshal(msg, &ts)
+	if err != nil {
+		c.logger.Warn("track status msg in unknown format", "msg", string(msg))
+	}
+	return ts
+}
+
+// ummarshalDriverListMsg converts the websocket message to a strongly typed map of structs.
+func (c *Client) unmarshalDriverListMsg(msg []byte) driverList {
+	var drivers driverList
+	err := json.Unmarshal(msg, &drivers)
+	if err != nil {
+		c.logger.Warn("driver data msg in unknown format", "msg", string(msg))
+	}
+
+	return drivers
+}
+
+// unmarshalLapCountMsg converts the websocket message to a strongly typed struct.
+func (c *Client) unmarshalLapCountMsg(msg []byte) lapCount {
+	var lc lapCount
+	err := json.Unmarshal(msg, &lc)
+	if err != nil {
+		c.logger.Warn("lap count msg in unknown format", "msg", string(msg))
+	}
+	return lc
+}
+
+// unmarshalTimingDataMsg converts the websocket message to a strongly typed struct.
+func (c *Client) unmarshalTimingDataMsg(msg []byte) timingDataMsg {
+	var timingDataMsg timingDataMsg
+	err := json.Unmarshal(msg, &timingDataMsg)
+	if err != nil {
+		c.logger.Warn("timing data msg in unknown format", "msg", string(msg))
+	}
+
+	return timingDataMsg
+}
+
+// unmarshalTimingAppDataMsg converts the websocket message to a strongly typed struct.
+func (c *Client) unmarshalTimingAppDataMsg(msg []byte) timingAppData {
+	var tad timingAppData
+	err := json.Unmarshal(msg, &tad)
+	if err != nil {
+		c.logger.Warn("timing app data msg in unknown format", "msg", string(msg))
+	}
+
+	return tad
+}
+func (c *Client) unmarshalRaceCtrlMsg(msg []byte) raceCtrlMsgs {
+	var rcm raceCtrlMsgs
+	err := json.Unmarshal(msg, &rcm)
+	if err != nil {
+		c.logger.Warn("race ctrl msg in unknown format", "msg", string(msg))
+	}
+
+	return rcm
+}
+
+// unmarshalWeatherDataMsg converts the websocket message to a strongly typed struct.
+func (c *Client) unmarshalWeatherDataMsg(msg []byte) weatherData {
+	var wd weatherData
+	if len(msg) == 0 {
+		return wd
+	}
+	err := json.Unmarshal(msg, &wd)
+	if err != nil {
+		c.logger.Warn("weather data msg in unknown format", "msg", string(msg))
+	}
+	return wd
+}
+
+// unmarshalCarDataMsg decodes, decompresses and converts the websocket message to a strongly typed
+// struct.
+func (c *Client) unmarshalCarDataMsg(msg []byte) carData {
+	var cd carData
+	if len(msg) == 0 {
+		return cd
+	}
+	var encoded string
+	if err := json.Unmarshal(msg, &encoded); err != nil {
+		c.logger.Warn("car data msg in unknown format", "msg", string(msg))
+		return cd
+	}
+	compressed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		c.logger.Warn("car data msg is not base64 encoded", "err", err)
+		return cd
+	}
+	r := flate.NewReader(bytes.NewReader(compressed))
+	defer r.Close()
+	if err := json.NewDecoder(r).Decode(&cd); err != nil {
+		c.logger.Warn("car data msg could not be decompressed", "err", err)
+	}
+	return cd
+}
+
+/* Channel Updaters
+------------------------------------------------------------------------------------------------- */
+
+// updateSessionInfo converts a SessionInfo msg from the F1 Live Timing API to the `Meeting` and
+// `Session` domain models  stored in the client's internal state store and writes the full state
+// of the meeting for consumers to read.
+func (c *Client) updateSessionInfo(session sessionInfo) (meetingUpdating, driversUpdated, raceCtrlMsgsUpdated bool) {
+	// this function always updates session
+	meetingUpdating = true
+	setMeetingName(&c.meeting, session.Meeting.Name)
+	setMeetingFullName(&c.meeting, session.Meeting.OfficialName)
+	setMeetingLocation(&c.meeting, session.Meeting.Location)
+	setMeetingRoundNumber(&c.meeting, session.Meeting.Number)
+	setMeetingCountryCode(&c.meeting, session.Meeting.Country.Code)
+	setMeetingCountryName(&c.meeting, session.Meeting.Country.Name)
+	setMeetingCurcuitShortName(&c.meeting, session.Meeting.Circuit.ShortName)
+	setSessionName(&c.meeting, session.Name)
+	setSessionGMTOffset(&c.meeting, session.GMTOffset)
+	setSessionStartDate(&c.meeting, session.StartDate)
+	setSessionEndDate(&c.meeting, session.EndDate)
+	setSessionType(&c.meeting, session.Type)
+	// once the session is archived no more data will be published for it
+	if session.ArchiveStatus.Status != nil && *session.ArchiveStatus.Status == archiveStatusComplete {
+		c.transitionSession(domain.SessionStatusEnded, c.meeting.Session.EndDate)
+	}
+	return meetingUpdating, driversUpdated, raceCtrlMsgsUpdated
+}
+
+// updateSessionData converts a SessionData msg from the F1 LiveTiming API to the `Session` domain
+// model and writes the full state of the meeting/session for consumers to read.
+func (c *Client) updateSessionData(session sessionData) (meetingUpdating, driversUpdated, raceCtrlMsgsUpdated bool) {
+	// this function always updates session
+	meetingUpdating = true
+	// update the session status to the latest/current status
+	statusKeys := make([]int, 0)
+	for key := range session.StatusSeries {
+		i, _ := strconv.Atoi(key)
+		statusKeys = append(statusKeys, i)
+	}
+	// Access the status messages in order so that we end up on the latest entry
+	sort.Ints(statusKeys)
+	for _, key := range statusKeys {
+		series := session.StatusSeries[strconv.Itoa(key)]
+		if series.SessionStatus != nil {
+			c.transitionSessionTo(*series.SessionStatus, series.UTC)
+		}
+		c.changeTrackStatus(series.TrackStatus, series.UTC)
+	}
+
+	// Update the session part to the latest/current session part
+	seriesKeys := make([]int, 0)
+	for key := range session.Series {
+		i, _ := strconv.Atoi(key)
+		seriesKeys = append(seriesKeys, i)
+	}
+	// Access the series messages in order so that we end up on the latest entry
+	sort.Ints(seriesKeys)
+	for _, key := range seriesKeys {
+		setSessionPart(&c.meeting, session.Series[strconv.Itoa(key)].QualifyingPart)
+	}
+
+	return meetingUpdating, driversUpdated, raceCtrlMsgsUpdated
+}
+
+// updateSessionStatus converts a SessionStatus msg from the F1 LiveTiming API to the `Session` domain
+// model; the meeting is only written if the session status changed.
+func (c *Client) updateSessionStatus(ss sessionStatus) (meetingUpdating, driversUpdated, raceCtrlMsgsUpdated bool) {
+	if ss.Status == nil {
+		return meetingUpdating, driversUpdated, raceCtrlMsgsUpdated
+	}
+	meetingUpdating = c.transitionSessionTo(*ss.Status, c.msgTime)
+	return meetingUpdating, driversUpdated, raceCtrlMsgsUpdated
+}
+
+// transitionSessionTo moves the session to the status published by the F1 LiveTiming API at the
+// given time, e.g. "Aborted", and indicates if the status changed.
+func (c *Client) transitionSessionTo(status string, at time.Time) bool {
+	to, ok := domain.ParseSessionStatus(status)
+	if !ok {
+		c.logger.Warn("unknown session status", "status", status)
+		return false
+	}
+	return c.transitionSession(to, at)
+}
+
+// transitionSession moves the session to the given status at the given time and indicates if the
+// status changed; transitions that aren't part of the session lifecycle are ignored.
+func (c *Client) transitionSession(to domain.SessionStatus, at time.Time) bool {
+	from := c.meeting.Session.Status
+	if from == to {
+		return false
+	}
+	if _, ok := c.meeting.Session.Transition(to, at); !ok {
+		c.logger.Warn("ignoring invalid session status transition", "from", from, "to", to)
+		return false
+	}
+	c.logger.Info("session status changed", "from", from, "to", to, "at", at)
+	c.emit(domain.SessionStatusChanged{EventMeta: domain.EventMeta{At: at}, From: from, To: to})
+	return true
+}
+
+// changeTrackStatus sets the track status published by the F1 LiveTiming API at the given time,
+// emitting an event if it changed.
+func (c *Client) changeTrackStatus(status *string, at time.Time) {
+	from := c.meeting.Session.TrackStatus
+	setTrackStatus(&c.meeting, status)
+	if to := c.meeting.Session.TrackStatus; to != from {
+		c.emit(domain.TrackStatusChanged{EventMeta: domain.EventMeta{At: at}, From: from, To: to})
+	}
+}
+
+// updateTrackStatus converts a TrackStatus msg from the F1 LiveTiming API to the `Session` domain
+// model and writes the full state of the meeting/session for consumers to read.
+func (c *Client) updateTrackStatus(ts trackStatus) (meetingUpdating, driversUpdated, raceCtrlMsgsUpdated bool) {
+	if ts.Message == "" {
+		return meetingUpdating, driversUpdated, raceCtrlMsgsUpdated
+	}
+	meetingUpdating = true
+	c.changeTrackStatus(&ts.Message, c.msgTime)
+	return meetingUpdating, driversUpdated, raceCtrlMsgsUpdated
+}
+
+// updateDriverIntrinsicData updates the intrinsic driver data (and occassionally position).
+func (c *Client) updateDriverList(driverDataMsg map[string]driverListItem) (meetingUpdating, driversUpdated, raceCtrlMsgsUpdated bool) {
+	// this function always updates drivers
+	driversUpdated = true
+	// update data for each driver to the drivers map
+	for number, data := range driverDataMsg {
+		// retrieve existing driver data from the map if it exists or create a new driver
+		driver, ok := c.drivers[number]
+		if !ok {
+			driver = domain.NewDriver(number)
+		}
+		// Overwrite fields
+		setShortName(&driver, data.ShortName)
+		setDriverName(&driver, data.FirstName, data.LastName, data.NameFormat)
+		setTeamName(&driver, data.TeamName)
+		setTeamColor(&driver, data.TeamColour)
+		setPosition(&driver, data.Line)
+		c.emitDriverEvents(c.drivers[number], driver)
+		// write the driver data back to the client state store
+		c.drivers[number] = driver
+	}
+	return meetingUpdating, driversUpdated, raceCtrlMsgsUpdated
+}
+
+// updateLapCount updates the current/total lap data (only applicable during races).
+func (c *Client) updateLapCount(lc lapCount) (meetingUpdating, driversUpdated, raceCtrlMsgsUpdated bool) {
+	// this function always update the session
+	meetingUpdating = true
+	if lc.CurrentLap != nil {
+		c.meeting.Session.CurrentLap = *lc.CurrentLap
+	}
+	if lc.TotalLaps != nil {
+		c.meeting.Session.TotalLaps = *lc.TotalLaps
+	}
+
+	return meetingUpdating, driversUpdated, raceCtrlMsgsUpdated
+}
+
+// updateDriverTimingData updates driver timing and position data.
+func (c *Client) updateTimingData(timingDataMsg timingDataMsg) (meetingUpdating, driversUpdated, raceCtrlMsgsUpdated bool) {
+	// this function always updates drivers
+	driversUpdated = true
+	// add data for each driver to the drivers map
+	for number, data := range timingDataMsg.Lines {
+		// retrieve existing driver data from the map if it exists or create a new driver
+		driver, ok := c.drivers[number]
+		if !ok {
+			driver = domain.NewDriver(number)
+		}
+		// Overwrite fields
+		setPosition(&driver, data.Line)
+		setGaps(&driver, c.meeting, data)
+		setLastLap(&driver, data.LastLapTime.Value, data.LastLapTime.PersonalFastest)
+		if data.LastLapTime.OverallFastest != nil && *data.LastLapTime.OverallFastest {
+			lapTime := driver.TimingData.LastLap.Time
+			if c.meeting.Session.FastestLapOwner != number || c.meeting.Session.FastestLapTime != lapTime {
+				c.emit(domain.FastestLap{EventMeta: domain.EventMeta{At: c.msgTime}, Driver: number, LapTime: lapTime})
+			}
+			c.meeting.Session.FastestLapOwner = number
+			c.meeting.Session.FastestLapTime = lapTime
+			meetingUpdating = true
+		}
+		setBestLap(&driver, data.BestLapTime.Value)
+		setIsKnockedOut(&driver, data.KnockedOut)
+		setIsRetired(&driver, data.Retired, data.Status)
+		setNumberOfLaps(&driver, data.NumberOfLaps)
+		if updated := setSectors(&driver, c.meeting, data.Sectors); updated {
+			meetingUpdating = true
+		}
+		// Set the Pit status _after_ setting sectors, because these functions may overwrite sector
+		// data to prevent weird scenarios of having sector times posted while in the PIT or Outlap
+		setIsInPit(&driver, data.InPit)
+		setIsPitOut(&driver, data.PitOut)
+		// keep track of best lap times in each qualifying part
+		setBestLapInPart(&driver, data)
+
+		c.emitDriverEvents(c.drivers[number], driver)
+		// update the driver data in the map
+		c.drivers[number] = driver
+	}
+
+	return meetingUpdating, driversUpdated, raceCtrlMsgsUpdated
+}
+
+// updateTimingAppData updates driver stint and position data.
+func (c *Client) updateTimingAppData(tad timingAppData) (meetingUpdating, driversUpdated, raceCtrlMsgsUpdated bool) {
+	// this function always updates drivers
+	driversUpdated = true
+	for driverNum, timingAppData := range tad.Lines {
+		// if multiple stints are given (e.g. in the reference message) we'll iterate through them,
+		// taking the stint with the largest key (which are numbers indicating the order)
+		stints := make([]string, 0)
+		for stintNum := range timingAppData.Stints {
+			stints = append(stints, stintNum)
+		}
+		if len(stints) == 0 {
+			continue
+		}
+		// sort the stints in descending order by key so we can take the largest key at index 0
+		sort.Slice(stints, func(i, j int) bool {
+			return stints[i] > stints[j]
+		})
+		currentStint := stints[0]
+
+		driver, ok := c.drivers[driverNum]
+		if !ok {
+			c.logger.Error("driver not found", "num", driverNum)
+			driver = domain.NewDriver(driverNum)
+		}
+		if len(timingAppData.Stints) > 0 {
+			setTireCompound(&driver, timingAppData.Stints[currentStint].Compound)
+			setTireLapCount(&driver, timingAppData.Stints[currentStint].TotalLaps)
+		}
+		// TimingAppData also contains driver position data sometimes
+		setPosition(&driver, timingAppData.Line)
+		c.emitDriverEvents(c.drivers[driverNum], driver)
+		// overwrite the driver state with the new stint information
+		c.drivers[driverNum] = driver
+	}
+
+	return meetingUpdating, driversUpdated, raceCtrlMsgsUpdated
+}
+
+// updateRaceCtrlMsg converts the RaceControlMessages msg from the F1 LiveTiming API to the
+// `RaceCtrlMsg` domain model, appending every message to the history of the session in the order they
+// were issued; the latest message is written to the race control channel.
+func (c *Client) updateRaceCtrlMsg(msgs raceCtrlMsgs) (meetingUpdating, driversUpdated, raceCtrlMsgsUpdated bool) {
+	// order the messages by sorting the keys
+	rcmKeys := make([]int, 0)
+	for key := range msgs.Messages {
+		i, _ := strconv.Atoi(key)
+		rcmKeys = append(rcmKeys, i)
+	}
+	sort.Ints(rcmKeys)
+	for _, key := range rcmKeys {
+		msg, ok := toRaceCtrlMsg(msgs.Messages[strconv.Itoa(key)])
+		if !ok {
+			continue
+		}
+		c.raceCtrlMsg = msg
+		c.raceCtrlMsgs = append(c.raceCtrlMsgs, msg)
+		c.emit(domain.RaceControlMessage{EventMeta: domain.EventMeta{At: c.msgTime}, Msg: msg})
+		raceCtrlMsgsUpdated = true
+	}
+
+	return meetingUpdating, driversUpdated, raceCtrlMsgsUpdated
+}
+
+// toRaceCtrlMsg converts a message issued by race control to the `RaceCtrlMsg` domain model; false
+// is returned for incomplete messages.
+func toRaceCtrlMsg(m raceCtrlMsg) (domain.RaceCtrlMsg, bool) {
+	if m.Category == nil || m.Message == nil {
+		return domain.RaceCtrlMsg{}, false
+	}
+
+	msg := domain.RaceCtrlMsg{
+		Body: *m.Message,
+	}
+	setRaceCtrlMsgTime(&msg, m.UTC)
+	setRaceCtrlMsgLap(&msg, m.Lap)
+
+	switch *m.Category {
+	case raceCtrlStatusFlag:
+		msg.Category = domain.RaceCtrlMsgCategoryTrackStatus
+		switch *m.Flag {
+		case raceCtrlFlagClear:
+			msg.Title = domain.RaceCtrlMsgTitleFlagGreen
+		case raceCtrlFlagGreen:
+			msg.Title = domain.RaceCtrlMsgTitleFlagGreen
+		case raceCtrlFlagBlue:
+			msg.Title = domain.RaceCtrlMsgTitleFlagBlue
+		case raceCtrlFlagYellow:
+			msg.Title = domain.RaceCtrlMsgTitleFlagYellow
+		case raceCtrlFlagDoubleYellow:
+			msg.Title = domain.RaceCtrlMsgTitleFlagDoubleYellow
+		case raceCtrlFlagRed:
+			msg.Title = domain.RaceCtrlMsgTitleFlagRed
+		case raceCtrlFlagBW:
+			msg.Title = domain.RaceCtrlMsgTitleFlagBW
+		default:
+			msg.Title = domain.RaceCtrlMsgTitleDefault
+		}
+	case raceCtrlStatusSC:
+		msg.Category = domain.RaceCtrlMsgCategoryTrackStatus
+		if *m.Mode == raceCtrlModeSC {
+			msg.Title = domain.RaceCtrlMsgTitleSC
+		} else if *m.Mode == raceCtrlModeVSC {
+			msg.Title = domain.RaceCtrlMsgTitleVSC
+		} else {
+			msg.Title = domain.RaceCtrlMsgTitleDefault
+		}
+	case raceCtrlStatusDRS:
+		msg.Category = domain.RaceCtrlMsgCategoryFIA
+		msg.Title = domain.RaceCtrlMsgTitleDefault
+	case raceCtrlStatusOther:
+		msg.Category = domain.RaceCtrlMsgCategoryFIA
+		msg.Title = domain.RaceCtrlMsgTitleFIA
+	default:
+		msg.Category = domain.RaceCtrlMsgCategoryOther
+		msg.Title = domain.RaceCtrlMsgTitleDefault
+	}
+	return msg, true
+}
+
+// updateWeatherData converts a WeatherData msg from the F1 LiveTiming API to the `Weather` domain
+// model of the meeting.
+func (c *Client) updateWeatherData(wd weatherData) (meetingUpdating, driversUpdated, raceCtrlMsgsUpdated bool) {
+	if wd == (weatherData{}) {
+		return meetingUpdating, driversUpdated, raceCtrlMsgsUpdated
+	}
+	meetingUpdating = true
+	setFloat(&c.meeting.Weather.AirTemp, wd.AirTemp)
+	setFloat(&c.meeting.Weather.TrackTemp, wd.TrackTemp)
+	setFloat(&c.meeting.Weather.Humidity, wd.Humidity)
+	setFloat(&c.meeting.Weather.Pressure, wd.Pressure)
+	setFloat(&c.meeting.Weather.WindSpeed, wd.WindSpeed)
+	if wd.WindDirection != nil {
+		c.meeting.Weather.WindDirection, _ = strconv.Atoi(*wd.WindDirection)
+	}
+	if wd.Rainfall != nil {
+		c.meeting.Weather.Rainfall = *wd.Rainfall != "0"
+	}
+	return meetingUpdating, driversUpdated, raceCtrlMsgsUpdated
+}
+
+// updateCarData updates the telemetry of each driver with the latest sample of the car data msg.
+func (c *Client) updateCarData(cd carData) (meetingUpdating, driversUpdated, raceCtrlMsgsUpdated bool) {
+	if len(cd.Entries) == 0 {
+		return meetingUpdating, driversUpdated, raceCtrlMsgsUpdated
+	}
+	driversUpdated = true
+	// entries are in chronological order so the latest sample of each car is the last one
+	for _, entry := range cd.Entries {
+		for number, car := range entry.Cars {
+			driver, ok := c.drivers[number]
+			if !ok {
+				driver = domain.NewDriver(number)
+			}
+			setTelemetry(&driver, car.Channels)
+			c.drivers[number] = driver
+		}
+	}
+	return meetingUpdating, driversUpdated, raceCtrlMsgsUpdated
+}
+
+// emitDriverEvents emits the events of the changes between the previous and updated state of a
+// driver; no events are emitted for a driver that wasn't known yet.
+func (c *Client) emitDriverEvents(before, after domain.Driver) {
+	if before.Number == "" {
+		return
+	}
+	meta := domain.EventMeta{At: c.msgTime}
+	number := after.Number
+	b, a := before.TimingData, after.TimingData
+	if b.Position != 0 && a.Position != b.Position {
+		c.emit(domain.PositionChanged{EventMeta: meta, Driver: number, From: b.Position, To: a.Position})
+	}
+	if a.NumberOfLaps > b.NumberOfLaps {
+		c.emit(domain.LapCompleted{
+			EventMeta:      meta,
+			Driver:         number,
+			Lap:            a.NumberOfLaps,
+			LapTime:        a.LastLap.Time,
+			IsPersonalBest: a.LastLap.IsPersonalBest,
+		})
+	}
+	if !b.IsInPit && a.IsInPit {
+		c.emit(domain.PitEntry{EventMeta: meta, Driver: number})
+	}
+	if !b.IsPitOut && a.IsPitOut {
+		c.emit(domain.PitExit{EventMeta: meta, Driver: number})
+	}
+	if b.TireCompound != domain.TireCompoundUnknown && a.TireCompound != b.TireCompound {
+		c.emit(domain.TyreChanged{EventMeta: meta, Driver: number, From: b.TireCompound, To: a.TireCompound})
+	}
+	if !b.IsRetired && a.IsRetired {
+		c.emit(domain.DriverRetired{EventMeta: meta, Driver: number})
+	}
+	if !b.IsKnockedOut && a.IsKnockedOut {
+		c.emit(domain.KnockedOut{EventMeta: meta, Driver: number, Part: c.meeting.Session.Part})
+	}
+}
+
+// emit queues the event to be written to the events channel once the message is processed.
+func (c *Client) emit(e domain.Event) {
+	c.events = append(c.events, e)
+}
+
+// writeEventsToChan writes the events derived from the message to every subscription without
+// blocking; events are dropped for subscriptions whose buffer is full.
+func (c *Client) writeEventsToChan() {
+	for _, e := range c.events {
+		c.publish(func(s *Subscription) { s.sendEvent(e) })
+	}
+	c.events = c.events[:0]
+}
+
+// writeMeetingToChan writes a copy of the meeting to every subscription. The session transitions
+// are only ever appended to, so the copy can share them with the client state. An unread meeting is
+// replaced by the copy.
+func (c *Client) writeMeetingToChan() {
+	c.publish(func(s *Subscription) { s.sendMeeting(c.meeting) })
+}
+
+// Because maps are not concurrency-safe, each subscription is written a copy of the map that can be
+// read by concurrent goroutines. An unread snapshot is replaced by the copy.
+func (c *Client) writeDriversToChan() {
+	c.publish(func(s *Subscription) { s.sendDrivers(c.drivers) })
+}
+
+// writeRaceCtrlMsgsToChan writes the latest race control message to every subscription. The message
+// is dropped for subscriptions whose buffer is full.
+func (c *Client) writeRaceCtrlMsgsToChan() {
+	c.publish(func(s *Subscription) { s.sendRaceCtrlMsg(c.raceCtrlMsg) })
+}
+
+// publish calls send for every subscription; subscriptions can't be closed meanwhile, so send can
+// write to their channels.
+func (c *Client) publish(send func(s *Subscription)) {
+	c.subsMu.Lock()
+	defer c.subsMu.Unlock()
+	for s := range c.subs {
+		send(s)
+	}
+}
+
+/* Message Transformers
+------------------------------------------------------------------------------------------------- */
+
+func setShortName(driver *domain.Driver, shortName *string) {
+	if shortName != nil {
+		driver.ShortName = *shortName
+	}
+}
+
+func setDriverName(driver *domain.Driver, firstName, lastName, nameFormat *string) {
+	if firstName != nil && lastName != nil {
+		if nameFormat != nil && *nameFormat == "LastNameIsPrimary" {
+			driver.Name = *lastName + " " + *firstName
+		} else {
+			driver.Name = *firstName + " " + *lastName
+		}
+	}
+}
+
+func setTeamName(driver *domain.Driver, name *string) {
+	if name != nil {
+		driver.TeamName = *name
+	}
+}
+
+func setTeamColor(driver *domain.Driver, color *string) {
+	if color != nil {
+		driver.TeamColor = "#" + *color
+	}
+}
+
+func setPosition(driver *domain.Driver, pos *int) {
+	if pos != nil {
+		driver.TimingData.Position = *pos
+	}
+}
+
+func setGaps(driver *domain.Driver, meeting domain.Meeting, data driverTimingData) {
+	if driver.TimingData.Position == 1 {
+		driver.TimingData.IntervalGap = ""
+		driver.TimingData.LeaderGap = ""
+	} else if meeting.Session.Type == domain.SessionTypeQualifying {
+		// In Qualifying Sessions the interval is stored separately for each qualifying part; we're only
+		// interested the most recent qualifying part, so we iterate through (the list is in order) and
+		// overwrite the gaps for each available qualifying part ending with the most recent. Note, we
+		// can leave the keys as strings for sorting since thre are only 3 parts (e.g. no issue with
+		// 10 < 2).
+		parts := make([]string, 0, 3)
+		for part := range data.QualifyingStats {
+			parts = append(parts, part)
+		}
+		sort.Strings(parts)
+		for _, part := range parts {
+			if data.QualifyingStats[part].TimeDiffToFastest != nil && *data.QualifyingStats[part].TimeDiffToFastest != "" {
+				driver.TimingData.LeaderGap = *data.QualifyingStats[part].TimeDiffToFastest
+			}
+			if data.QualifyingStats[part].TimeDiffToPositionAhead != nil && *data.QualifyingStats[part].TimeDiffToPositionAhead != "" {
+				driver.TimingData.IntervalGap = *data.QualifyingStats[part].TimeDiffToPositionAhead
+			}
+		}
+	} else {
+		if data.IntervalToPositionAhead.Value != nil && *data.IntervalToPositionAhead.Value != "" {
+			driver.TimingData.IntervalGap = *data.IntervalToPositionAhead.Value
+		}
+		if data.GapToLeader != nil && *data.GapToLeader != "" {
+			driver.TimingData.LeaderGap = *data.GapToLeader
+		}
+	}
+}
+
+func setLastLap(driver *domain.Driver, time *string, personalFastest *bool) {
+	if time != nil && *time != "" {
+		driver.TimingData.LastLap.Time = *time
+	}
+
+	if personalFastest != nil {
+		driver.TimingData.LastLap.IsPersonalBest = *personalFastest
+	}
+}
+
+func setBestLap(driver *domain.Driver, time *string) {
+	if time != nil && *time != "" {
+		driver.TimingData.BestLapTime = *time
+	}
+}
+
+func setIsKnockedOut(driver *domain.Driver, out *bool) {
+	if out != nil {
+		driver.TimingData.IsKnockedOut = *out
+	}
+}
+
+func setIsRetired(driver *domain.Driver, out *bool, status *int) {
+	if out != nil {
+		driver.TimingData.IsRetired = *out
+	}
+
+	if status != nil &&
+		(*status == statusCrashDamageRetiredInPit ||
+			*status == statusCrashDamageRetiredOnTrack) {
+		driver.TimingData.IsRetired = true
+	}
+}
+
+func setTireCompound(driver *domain.Driver, compound *string) {
+	if compound != nil {
+		switch *compound {
+		case "SOFT":
+			driver.TimingData.TireCompound = domain.TireCompoundSoft
+		case "MEDIUM":
+			driver.TimingData.TireCompound = domain.TireCompoundMedium
+		case "HARD":
+			driver.TimingData.TireCompound = domain.TireCompoundHard
+		case "INTERMEDIATE":
+			driver.TimingData.TireCompound = domain.TireCompoundIntermediate
+		case "WET":
+			driver.TimingData.TireCompound = domain.TireCompoundFullWet
+		case "TEST":
+			driver.TimingData.TireCompound = domain.TireCompoundTest
+		case "PROTOTYPE":
+			driver.TimingData.TireCompound = domain.TireCompoundTest
+		default:
+			driver.TimingData.TireCompound = domain.TireCompoundUnknown
+		}
+	}
+}
+
+func setTireLapCount(driver *domain.Driver, count *int) {
+	if count != nil {
+		driver.TimingData.TireLapCount = *count
+	}
+}
+
+func setNumberOfLaps(driver *domain.Driver, laps *int) {
+	if laps != nil {
+		driver.TimingData.NumberOfLaps = *laps
+	}
+}
+
+func setIsInPit(driver *domain.Driver, pit *bool) {
+	if pit != nil {
+		driver.TimingData.IsInPit = *pit
+	}
+}
+
+func setIsPitOut(driver *domain.Driver, out *bool) {
+	if out != nil {
+		driver.TimingData.IsPitOut = *out
+	}
+}
+
+// setSectors updates the sectors of the driver; the sectors are shared with the snapshots of the
+// driver written to the drivers channel, so the updated sectors are copied rather than modified.
+func setSectors(driver *domain.Driver, meeting domain.Meeting, sectors map[string]sectorTiming) bool {
+	if len(sectors) == 0 {
+		return false
+	}
+	driver.TimingData.Sectors = maps.Clone(driver.TimingData.Sectors)
+	for sectorNum, secData := range sectors {
+		sector, ok := driver.TimingData.Sectors[sectorNum]
+		if !ok {
+			sector = domain.NewSector()
+		} else if len(secData.Segments) > 0 {
+			sector.Segments = maps.Clone(sector.Segments)
+		}
+		for segmentNum, segData := range secData.Segments {
+			segment, ok := sector.Segments[segmentNum]
+			if !ok {
+				segment = domain.Segment{}
+			}
+			if meeting.Session.Status != domain.SessionStatusStarted {
+				segment.Status = domain.SectorStatusInactive
+				sector.Segments[segmentNum] = segment
+			} else if segData.Status != nil {
+				// convert f1 livetiming status to domain model status
+				switch *segData.Status {
+				case yellowSegment:
+					segment.Status = domain.SectorStatusNotPersonalBest
+				case greenSegment:
+					segment.Status = domain.SectorStatusPersonalBest
+				case purpleSegment:
+					segment.Status = domain.SectorStatusOverallBest
+				case pitSegment:
+					segment.Status = domain.SectorStatusInactive
+				default:
+					segment.Status = domain.SectorStatusInactive
+				}
+				sector.Segments[segmentNum] = segment
+			}
+		}
+		driver.TimingData.Sectors[sectorNum] = sector
+	}
+	return false
+}
+
+func setBestLapInPart(driver *domain.Driver, data driverTimingData) {
+	// Sort session parts before
+	partNums := make([]string, 0, 3)
+	for partNum := range data.QualifyingBestLapTimes {
+		partNums = append(partNums, partNum)
+	}
+	sort.Strings(partNums)
+	// the best lap times are shared with the snapshots of the driver written to the drivers channel
+	if len(partNums) > 0 {
+		driver.TimingData.BestLapTimes = slices.Clone(driver.TimingData.BestLapTimes)
+	}
+	for _, partNum := range partNums {
+		i, _ := strconv.Atoi(partNum)
+		if data.QualifyingBestLapTimes[partNum].Value != nil {
+			driver.TimingData.BestLapTimes[i] = *data.QualifyingBestLapTimes[partNum].Value
+		}
+	}
+}
+
+func setRaceCtrlMsgTime(msg *domain.RaceCtrlMsg, utc *string) {
+	if utc != nil {
+		msg.Time, _ = time.Parse(f1APIUTCLayout, *utc)
+	}
+}
+
+func setRaceCtrlMsgLap(msg *domain.RaceCtrlMsg, lap *int) {
+	if lap != nil {
+		msg.Lap = *lap
+	}
+}
+
+func setMeetingName(meeting *domain.Meeting, name *string) {
+	if name != nil {
+		meeting.Name = *name
+	}
+}
+
+func setMeetingFullName(meeting *domain.Meeting, name *string) {
+	if name != nil {
+		meeting.FullName = *name
+	}
+}
+
+func setMeetingLocation(meeting *domain.Meeting, loc *string) {
+	if loc != nil {
+		meeting.Location = *loc
+	}
+}
+
+func setMeetingRoundNumber(meeting *domain.Meeting, num *int) {
+	if num != nil {
+		meeting.RoundNumber = *num
+	}
+}
+
+func setMeetingCountryCode(meeting *domain.Meeting, cc *string) {
+	if cc != nil {
+		meeting.CountryCode = *cc
+	}
+}
+
+func setMeetingCountryName(meeting *domain.Meeting, name *string) {
+	if name != nil {
+		meeting.CountryName = *name
+	}
+}
+
+func setMeetingCurcuitShortName(meeting *domain.Meeting, name *string) {
+	if name != nil {
+		meeting.CircuitShortName = *name
+	}
+}
+
+func setSessionName(meeting *domain.Meeting, name *string) {
+	if name != nil {
+		meeting.Session.Name = *name
+	}
+}
+
+func setSessionGMTOffset(meeting *domain.Meeting, offset *string) {
+	if offset != nil {
+		o := strings.Join(strings.Split(*offset, ":")[:2], "")
+		// offsets ahead of GMT are sent without a sign, e.g.: "04:00:00"
+		if !strings.HasPrefix(o, "-") && !strings.HasPrefix(o, "+") {
+			o = "+" + o
+		}
+		meeting.Session.GMTOffset = o
+	}
+}
+
+func setSessionStartDate(meeting *domain.Meeting, start *string) {
+	if start != nil {
+		meeting.Session.StartDate, _ = time.Parse(f1APIDateLayout, *start+meeting.Session.GMTOffset)
+	}
+}
+
+func setSessionEndDate(meeting *domain.Meeting, end *string) {
+	if end != nil {
+		meeting.Session.EndDate, _ = time.Parse(f1APIDateLayout, *end+meeting.Session.GMTOffset)
+	}
+}
+
+func setSessionType(meeting *domain.Meeting, t *string) {
+	if t != nil {
+		switch *t {
+		case "Test":
+			meeting.Session.Type = domain.SessionTypeTest
+		case "Practice":
+			meeting.Session.Type = domain.SessionTypePractice
+		case "Qualifying":
+			meeting.Session.Type = domain.SessionTypeQualifying
+		case "Race":
+			meeting.Session.Type = domain.SessionTypeRace
+		case "Unknown":
+			meeting.Session.Type = domain.SessionTypeUnknown
+		}
+	}
+}
+
+func setTrackStatus(meeting *domain.Meeting, s *string) {
+	if s != nil {
+		switch *s {
+		case "AllClear":
+			meeting.Session.TrackStatus = domain.TrackStatusClear
+		case "Yellow":
+			meeting.Session.TrackStatus = domain.TrackStatusYellow
+		case "SCDeployed":
+			meeting.Session.TrackStatus = domain.TrackStatusSC
+		case "VSCDeployed":
+			meeting.Session.TrackStatus = domain.TrackStatusVSC
+		case "VSCEnding":
+			meeting.Session.TrackStatus = domain.TrackStatusVSCEnding
+		case "Red":
+			meeting.Session.TrackStatus = domain.TrackStatusRed
+		default:
+			meeting.Session.TrackStatus = domain.TrackStatusUnknown
+		}
+	}
+}
+
+func setSessionPart(meeting *domain.Meeting, part *int) {
+	if part != nil {
+		meeting.Session.Part = *part
+	}
+}
+
+func setFloat(f *float64, s *string) {
+	if s != nil {
+		if v, err := strconv.ParseFloat(*s, 64); err == nil {
+			*f = v
+		}
+	}
+}
+
+func setTelemetry(driver *domain.Driver, channels map[string]int) {
+	driver.Telemetry.RPM = channels[carDataChannelRPM]
+	driver.Telemetry.Speed = channels[carDataChannelSpeed]
+	driver.Telemetry.Gear = channels[carDataChannelGear]
+	driver.Telemetry.Throttle = min(100, channels[carDataChannelThrottle])
+	driver.Telemetry.Brake = channels[carDataChannelBrake] > 0
+	// DRS is reported as 8 when the driver is eligible and 10, 12 or 14 when the flap is open
+	drs := channels[carDataChannelDRS]
+	driver.Telemetry.DRSAvailable = drs == 8
+	driver.Telemetry.DRSOpen = drs >= 10
+}
+
+/* Private types
+------------------------------------------------------------------------------------------------- */
+
+// negotiateResponse represents the response body of the F1 Live Timing negotiate API.
+type negotiateResponse struct {
+	Url                     string  `json:"Url"`
+	ConnectionToken         string  `json:"ConnectionToken"`
+	ConnectionId            string  `json:"ConnectionId"`
+	KeepAliveTimeout        float64 `json:"KeepAliveTimeout"`
+	DisconnectTimeout       float64 `json:"DisconnectTimeout"`
+	ConnectionTimeout       float64 `json:"ConnectionTimeout"`
+	TryWebSockets           bool    `json:"TryWebSockets"`
+	ProtocolVersion         string  `json:"ProtocolVersion"`
+	TransportConnectTimeout float64 `json:"TransportConnectTimeout"`
+	LongPollDelay           float64 `json:"LongPollDelay"`
+}
@@ -0,0 +1,549 @@
+package f1livetiming
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/bcdxn/f1cli/domain"
+)
+
+func TestProcessReferenceMessage(t *testing.T) {
+
+	t.Run("Practice", func(t *testing.T) {
+		t.Parallel()
+		td := testdataDir()
+		ref, _ := os.ReadFile(path.Join(td, "ref-msg-practice.json"))
+
+		c := New(WithLogger(testLogger(t)))
+		go c.processMessage(ref)
+
+		wait := 3
+		for wait > 0 {
+			select {
+			case meeting := <-c.Meeting():
+				wait--
+				if meeting.Session.Type != domain.SessionTypePractice {
+					t.Errorf("expected session type '%s' but found '%s'", domain.SessionTypePractice, meeting.Session.Type)
+				}
+				if meeting.Session.Part != 0 {
+					t.Errorf("expected session part %d but found %d", 0, meeting.Session.Part)
+				}
+				if meeting.Session.CurrentLap != 0 {
+					t.Errorf("expected lap count %d but found %d", 0, meeting.Session.CurrentLap)
+				}
+				if meeting.Session.TotalLaps != 0 {
+					t.Errorf("expected lap count %d but found %d", 0, meeting.Session.TotalLaps)
+				}
+				if meeting.Session.TrackStatus != domain.TrackStatusRed {
+					t.Errorf("expected track status '%s' but found '%s'", domain.TrackStatusRed, meeting.Session.TrackStatus)
+				}
+				if meeting.Session.Status != domain.SessionStatusSuspended {
+					t.Errorf("expected status '%s' but found '%s'", domain.SessionStatusSuspended, meeting.Session.Status)
+				}
+				if meeting.Session.Suspensions() != 2 {
+					t.Errorf("expected %d suspensions but found %d", 2, meeting.Session.Suspensions())
+				}
+				if since := meeting.Session.StatusSince().Format(time.RFC3339Nano); since != "2024-10-25T18:52:11.979Z" {
+					t.Errorf("expected status since '%s' but found '%s'", "2024-10-25T18:52:11.979Z", since)
+				}
+			case drivers := <-c.Drivers():
+				wait--
+				if len(drivers) != 20 {
+					t.Errorf("expected %d drivers but found %d", 20, len(drivers))
+				}
+				if drivers["1"].Name != "Max Verstappen" {
+					t.Errorf("expected name '%s' but found '%s'", "Max Verstappen", drivers["1"].Name)
+				}
+				if drivers["97"].Name != "Robert Shwartzman" {
+					t.Errorf("expected name '%s' but found '%s'", "Robert Shwartzman", drivers["97"].Name)
+				}
+				if drivers["81"].TimingData.BestLapTime != "1:20.515" {
+					t.Errorf("expected best lap time '%s' but found '%s'", "1:20.515", drivers["81"].TimingData.BestLapTime)
+				}
+				if drivers["81"].TimingData.Position != 9 {
+					t.Errorf("expected position %d but found %d", 9, drivers["81"].TimingData.Position)
+				}
+				if drivers["1"].TimingData.TireCompound != domain.TireCompoundHard {
+					t.Errorf("expected tire compound '%s' but found '%s'", domain.TireCompoundHard, drivers["1"].TimingData.TireCompound)
+				}
+				if drivers["1"].TimingData.NumberOfLaps != 6 {
+					t.Errorf("expected stint laps %d but found %d", 6, drivers["1"].TimingData.NumberOfLaps)
+				}
+			case raceCtrlMsg := <-c.RaceCtrlMsgs():
+				wait--
+				if raceCtrlMsg.Body != "FIA STEWARDS: TURN 11 INCIDENT INVOLVING CARS 97 (SHW) WILL BE INVESTIGATED AFTER THE SESSION - OVERTAKING UNDER YELLOW FLAGS" {
+					t.Errorf("incorrect message body, found '%s'", raceCtrlMsg.Body)
+				}
+			}
+		}
+	})
+	t.Run("Qualifying", func(t *testing.T) {
+		t.Parallel()
+		td := testdataDir()
+		ref, _ := os.ReadFile(path.Join(td, "ref-msg-qualifying.json"))
+
+		c := New(WithLogger(testLogger(t)))
+		go c.processMessage(ref)
+
+		wait := 2
+		fmt.Println("waiting for channel messages")
+
+		for wait > 0 {
+			select {
+			case meeting := <-c.Meeting():
+				wait--
+				if meeting.Session.Type != domain.SessionTypeQualifying {
+					t.Errorf("expected session type '%s' but found '%s'", domain.SessionTypeQualifying, meeting.Session.Type)
+				}
+				if meeting.Session.Part != 1 {
+					t.Errorf("expected session part %d but found %d", 1, meeting.Session.Part)
+				}
+				if meeting.Session.CurrentLap != 0 {
+					t.Errorf("expected lap count %d but found %d", 0, meeting.Session.CurrentLap)
+				}
+				if meeting.Session.TotalLaps != 0 {
+					t.Errorf("expected lap count %d but found %d", 0, meeting.Session.TotalLaps)
+				}
+			case drivers := <-c.Drivers():
+				wait--
+				if len(drivers) != 20 {
+					t.Errorf("expected %d drivers but found %d", 20, len(drivers))
+				}
+				if drivers["1"].Name != "Max Verstappen" {
+					t.Errorf("expected name '%s' but found '%s'", "Max Verstappen", drivers["1"].Name)
+				}
+				if drivers["24"].Name != "Zhou Guanyu" {
+					t.Errorf("expected name '%s' but found '%s'", "Zhou Guanyu", drivers["1"].Name)
+				}
+				if drivers["81"].TimingData.BestLapTimes[0] != "1:23.640" {
+					t.Errorf("expected best lap time '%s' but found '%s'", "1:23.640", drivers["81"].TimingData.BestLapTimes[0])
+				}
+				if drivers["81"].TimingData.Position != 4 {
+					t.Errorf("expected position %d but found %d", 4, drivers["81"].TimingData.Position)
+				}
+				if drivers["1"].TimingData.TireCompound != domain.TireCompoundSoft {
+					t.Errorf("expected tire compound '%s' but found '%s'", domain.TireCompoundSoft, drivers["1"].TimingData.TireCompound)
+				}
+				if drivers["1"].TimingData.NumberOfLaps != 3 {
+					t.Errorf("expected stint laps %d but found %d", 3, drivers["1"].TimingData.NumberOfLaps)
+				}
+				// case <-c.RaceCtrlMsgs():
+				// 	wait--
+			}
+		}
+	})
+
+	t.Run("Race", func(t *testing.T) {
+		t.Parallel()
+		td := testdataDir()
+		ref, _ := os.ReadFile(path.Join(td, "ref-msg-race.json"))
+
+		c := New(WithLogger(testLogger(t)))
+		go c.processMessage(ref)
+
+		wait := 2
+		fmt.Println("waiting for channel messages")
+
+		for wait > 0 {
+			select {
+			case meeting := <-c.Meeting():
+				wait--
+				if meeting.Session.Type != domain.SessionTypeRace {
+					t.Errorf("expected session type '%s' but found '%s'", domain.SessionTypeRace, meeting.Session.Type)
+				}
+				if meeting.Session.Part != 0 {
+					t.Errorf("expected session part %d but found %d", 0, meeting.Session.Part)
+				}
+				if meeting.Session.CurrentLap != 1 {
+					t.Errorf("expected lap count %d but found %d", 1, meeting.Session.CurrentLap)
+				}
+				if meeting.Session.TotalLaps != 58 {
+					t.Errorf("expected total laps %d but found %d", 58, meeting.Session.TotalLaps)
+				}
+				if meeting.Session.Status != domain.SessionStatusPending {
+					t.Errorf("expected status '%s' but found '%s'", domain.SessionStatusPending, meeting.Session.Status)
+				}
+				if meeting.Session.TrackStatus != domain.TrackStatusClear {
+					t.Errorf("expected track status '%s' but found '%s'", domain.TrackStatusClear, meeting.Session.TrackStatus)
+				}
+				if meeting.Weather.AirTemp != 27.3 {
+					t.Errorf("expected air temperature %.1f but found %.1f", 27.3, meeting.Weather.AirTemp)
+				}
+				if meeting.Weather.WindDirection != 30 {
+					t.Errorf("expected wind direction %d but found %d", 30, meeting.Weather.WindDirection)
+				}
+			case drivers := <-c.Drivers():
+				wait--
+				if len(drivers) != 20 {
+					t.Errorf("expected %d drivers but found %d", 20, len(drivers))
+				}
+				if drivers["1"].Name != "Max Verstappen" {
+					t.Errorf("expected name '%s' but found '%s'", "Max Verstappen", drivers["1"].Name)
+				}
+				if drivers["24"].Name != "Zhou Guanyu" {
+					t.Errorf("expected name '%s' but found '%s'", "Zhou Guanyu", drivers["1"].Name)
+				}
+				if drivers["81"].TimingData.BestLapTime != "" {
+					t.Errorf("expected best lap time '%s' but found '%s'", "", drivers["81"].TimingData.BestLapTime)
+				}
+				if drivers["81"].TimingData.Position != 2 {
+					t.Errorf("expected position %d but found %d", 2, drivers["81"].TimingData.Position)
+				}
+				if drivers["1"].TimingData.TireCompound != domain.TireCompoundUnknown {
+					t.Errorf("expected tire compound '%s' but found '%s'", domain.TireCompoundUnknown, drivers["1"].TimingData.TireCompound)
+				}
+				if drivers["1"].TimingData.NumberOfLaps != 0 {
+					t.Errorf("expected stint laps %d but found %d", 0, drivers["1"].TimingData.NumberOfLaps)
+				}
+				// case <-c.RaceCtrlMsgs():
+				// 	wait--
+			}
+		}
+	})
+
+	t.Run("Again", func(t *testing.T) {
+		t.Parallel()
+		td := testdataDir()
+		ref, _ := os.ReadFile(path.Join(td, "ref-msg-practice.json"))
+
+		// the reference message is sent again on each new connection, e.g. after a reconnect
+		c := newReferenecedClient(t, path.Join(td, "ref-msg-practice.json"))
+		c.processMessage(ref)
+		// only the latest snapshot is kept, so the other channels don't need to be drained
+		meeting := <-c.Meeting()
+
+		if meeting.Session.Status != domain.SessionStatusSuspended {
+			t.Errorf("expected status '%s' but found '%s'", domain.SessionStatusSuspended, meeting.Session.Status)
+		}
+		if len(meeting.Session.Transitions) != 5 {
+			t.Errorf("expected %d transitions but found %d", 5, len(meeting.Session.Transitions))
+		}
+		if meeting.Session.Suspensions() != 2 {
+			t.Errorf("expected %d suspensions but found %d", 2, meeting.Session.Suspensions())
+		}
+		if since := meeting.Session.StatusSince().Format(time.RFC3339Nano); since != "2024-10-25T18:52:11.979Z" {
+			t.Errorf("expected status since '%s' but found '%s'", "2024-10-25T18:52:11.979Z", since)
+		}
+	})
+}
+
+func TestProcessChangeMessage(t *testing.T) {
+
+	t.Run("Qualifying", func(t *testing.T) {
+		td := testdataDir()
+		t.Run("TimingData", func(t *testing.T) {
+			c := newReferenecedClient(t, path.Join(td, "ref-msg-qualifying.json"))
+			change, _ := os.ReadFile(path.Join(td, "ch-msg-qual-timingdata.json"))
+			c.processMessage(change)
+			// only the latest snapshot is kept, so the other channels don't need to be drained
+			drivers := <-c.Drivers()
+
+			if drivers["81"].TimingData.Position != 8 {
+				t.Errorf("expected position %d but found %d", 8, drivers["81"].TimingData.Position)
+			}
+			if drivers["27"].TimingData.LeaderGap != "+0.420" {
+				t.Errorf("expected leader gap '%s' but found '%s'", "+0.420", drivers["27"].TimingData.LeaderGap)
+			}
+			if drivers["27"].TimingData.IntervalGap != "+0.040" {
+				t.Errorf("expected interval gap '%s' but found '%s'", "+0.040", drivers["27"].TimingData.IntervalGap)
+			}
+		})
+	})
+	t.Run("Snapshots", func(t *testing.T) {
+		td := testdataDir()
+		ref, _ := os.ReadFile(path.Join(td, "ref-msg-qualifying.json"))
+		change, _ := os.ReadFile(path.Join(td, "ch-msg-qual-timingdata.json"))
+		c := New(WithLogger(testLogger(t)))
+		c.processMessage(ref)
+		before := <-c.Drivers()
+		bestLap := before["16"].TimingData.BestLapTimes[0]
+
+		c.processMessage(change)
+		after := <-c.Drivers()
+
+		// the drivers are shared between snapshots, so updates must not modify earlier snapshots
+		if before["16"].TimingData.BestLapTimes[0] != bestLap {
+			t.Errorf("expected best lap time '%s' in the earlier snapshot but found '%s'", bestLap, before["16"].TimingData.BestLapTimes[0])
+		}
+		if after["16"].TimingData.BestLapTimes[0] != "1:23.302" {
+			t.Errorf("expected best lap time '%s' but found '%s'", "1:23.302", after["16"].TimingData.BestLapTimes[0])
+		}
+	})
+	t.Run("Race", func(t *testing.T) {
+		td := testdataDir()
+		t.Run("TimingData", func(t *testing.T) {
+			c := newReferenecedClient(t, path.Join(td, "ref-msg-race.json"))
+			change, _ := os.ReadFile(path.Join(td, "ch-msg-race-timingdata.json"))
+			c.processMessage(change)
+			// only the latest snapshot is kept, so the other channels don't need to be drained
+			drivers := <-c.Drivers()
+
+			if drivers["61"].TimingData.Position != 18 {
+				t.Errorf("expected position %d but found %d", 18, drivers["61"].TimingData.Position)
+			}
+			if drivers["23"].TimingData.Position != 16 {
+				t.Errorf("expected position %d but found %d", 16, drivers["23"].TimingData.Position)
+			}
+			if drivers["23"].TimingData.LeaderGap != "+4.625" {
+				t.Errorf("expected position %s but found %s", "+4.625", drivers["23"].TimingData.LeaderGap)
+			}
+			if drivers["23"].TimingData.IntervalGap != "+0.133" {
+				t.Errorf("expected position %s but found %s", "+0.133", drivers["23"].TimingData.IntervalGap)
+			}
+		})
+
+		t.Run("SessionData", func(t *testing.T) {
+			c := newReferenecedClient(t, path.Join(td, "ref-msg-race.json"))
+			change, _ := os.ReadFile(path.Join(td, "ch-msg-race-sessiondata.json"))
+			c.processMessage(change)
+			// only the latest snapshot is kept, so the other channels don't need to be drained
+			meeting := <-c.Meeting()
+
+			if meeting.Session.Status != domain.SessionStatusStarted {
+				t.Errorf("expected status '%s' but found '%s'", domain.SessionStatusStarted, meeting.Session.Status)
+			}
+		})
+
+		t.Run("SessionStatus", func(t *testing.T) {
+			c := newReferenecedClient(t, path.Join(td, "ref-msg-race.json"))
+			change, _ := os.ReadFile(path.Join(td, "ch-msg-race-sessionstatus.json"))
+			c.processMessage(change)
+			// only the latest snapshot is kept, so the other channels don't need to be drained
+			meeting := <-c.Meeting()
+
+			// the session can't be started again once it has ended
+			if meeting.Session.Status != domain.SessionStatusEnded {
+				t.Errorf("expected status '%s' but found '%s'", domain.SessionStatusEnded, meeting.Session.Status)
+			}
+			expected := []domain.SessionStatus{
+				domain.SessionStatusStarted,
+				domain.SessionStatusSuspended,
+				domain.SessionStatusPending,
+				domain.SessionStatusStarted,
+				domain.SessionStatusFinished,
+				domain.SessionStatusFinalised,
+				domain.SessionStatusEnded,
+			}
+			if len(meeting.Session.Transitions) != len(expected) {
+				t.Fatalf("expected %d transitions but found %d", len(expected), len(meeting.Session.Transitions))
+			}
+			for i, tr := range meeting.Session.Transitions {
+				if tr.To != expected[i] {
+					t.Errorf("expected transition %d to '%s' but found '%s'", i, expected[i], tr.To)
+				}
+			}
+			if meeting.Session.Transitions[1].Time.Format(time.RFC3339) != "2024-12-08T13:20:00Z" {
+				t.Errorf("expected suspension at '%s' but found '%s'", "2024-12-08T13:20:00Z", meeting.Session.Transitions[1].Time.Format(time.RFC3339))
+			}
+			if !meeting.Session.IsClassificationFinal() {
+				t.Errorf("expected the classification to be final")
+			}
+		})
+
+		t.Run("WeatherData", func(t *testing.T) {
+			c := newReferenecedClient(t, path.Join(td, "ref-msg-race.json"))
+			change, _ := os.ReadFile(path.Join(td, "ch-msg-race-weatherdata.json"))
+			c.processMessage(change)
+			// only the latest snapshot is kept, so the other channels don't need to be drained
+			meeting := <-c.Meeting()
+
+			if meeting.Weather.TrackTemp != 30.4 {
+				t.Errorf("expected track temperature %.1f but found %.1f", 30.4, meeting.Weather.TrackTemp)
+			}
+			if !meeting.Weather.Rainfall {
+				t.Errorf("expected rainfall")
+			}
+			// values missing from the change are kept from the reference
+			if meeting.Weather.Pressure != 1017.0 {
+				t.Errorf("expected pressure %.1f but found %.1f", 1017.0, meeting.Weather.Pressure)
+			}
+		})
+
+		t.Run("CarData", func(t *testing.T) {
+			c := newReferenecedClient(t, path.Join(td, "ref-msg-race.json"))
+			change, _ := os.ReadFile(path.Join(td, "ch-msg-race-cardata.json"))
+			c.processMessage(change)
+			// only the latest snapshot is kept, so the other channels don't need to be drained
+			drivers := <-c.Drivers()
+
+			tel := drivers["23"].Telemetry
+			if tel.Speed != 291 || tel.RPM != 11450 || tel.Gear != 7 || tel.Throttle != 100 {
+				t.Errorf("expected the latest sample (291 km/h, 11450 rpm, gear 7, 100%%) but found %+v", tel)
+			}
+			if !tel.DRSOpen {
+				t.Errorf("expected DRS to be open")
+			}
+			tel = drivers["1"].Telemetry
+			if !tel.Brake || tel.Throttle != 0 || tel.DRSOpen || !tel.DRSAvailable {
+				t.Errorf("expected braking with DRS available but found %+v", tel)
+			}
+			if drivers["1"].Name != "Max Verstappen" {
+				t.Errorf("expected name '%s' but found '%s'", "Max Verstappen", drivers["1"].Name)
+			}
+		})
+	})
+}
+
+func TestEvents(t *testing.T) {
+	td := testdataDir()
+
+	t.Run("Reference", func(t *testing.T) {
+		c := newReferenecedClient(t, path.Join(td, "ref-msg-race.json"))
+		if events := collectEvents(c); len(events) != 0 {
+			t.Errorf("expected no events but found %d", len(events))
+		}
+	})
+
+	t.Run("PositionChanged", func(t *testing.T) {
+		c := newReferenecedClient(t, path.Join(td, "ref-msg-race.json"))
+		change, _ := os.ReadFile(path.Join(td, "ch-msg-race-timingdata.json"))
+		c.processMessage(change)
+
+		expected := map[string]domain.PositionChanged{
+			"61": {Driver: "61", From: 17, To: 18},
+			"23": {Driver: "23", From: 18, To: 16},
+		}
+		found := make(map[string]domain.PositionChanged)
+		for _, e := range collectEvents(c) {
+			if pc, ok := e.(domain.PositionChanged); ok {
+				found[pc.Driver] = pc
+			}
+		}
+		if len(found) != len(expected) {
+			t.Fatalf("expected %d position changes but found %d", len(expected), len(found))
+		}
+		for number, pc := range found {
+			if pc.From != expected[number].From || pc.To != expected[number].To {
+				t.Errorf("expected position change from %d to %d but found from %d to %d", expected[number].From, expected[number].To, pc.From, pc.To)
+			}
+			if pc.Timestamp().Format(time.RFC3339Nano) != "2024-12-08T13:03:55.09Z" {
+				t.Errorf("expected timestamp '%s' but found '%s'", "2024-12-08T13:03:55.09Z", pc.Timestamp().Format(time.RFC3339Nano))
+			}
+		}
+	})
+
+	t.Run("SessionStatusChanged", func(t *testing.T) {
+		c := newReferenecedClient(t, path.Join(td, "ref-msg-race.json"))
+		change, _ := os.ReadFile(path.Join(td, "ch-msg-race-sessionstatus.json"))
+		c.processMessage(change)
+
+		var found []domain.SessionStatusChanged
+		for _, e := range collectEvents(c) {
+			if sc, ok := e.(domain.SessionStatusChanged); ok {
+				found = append(found, sc)
+			}
+		}
+		if len(found) != 7 {
+			t.Fatalf("expected %d session status changes but found %d", 7, len(found))
+		}
+		if found[1].To != domain.SessionStatusSuspended {
+			t.Errorf("expected status '%s' but found '%s'", domain.SessionStatusSuspended, found[1].To)
+		}
+		if found[1].Timestamp().Format(time.RFC3339) != "2024-12-08T13:20:00Z" {
+			t.Errorf("expected timestamp '%s' but found '%s'", "2024-12-08T13:20:00Z", found[1].Timestamp().Format(time.RFC3339))
+		}
+	})
+}
+
+// getTestdataDir gets the testdata directory path relative to the invocation of the tests.
+func TestReplay(t *testing.T) {
+	td := testdataDir()
+	ref, _ := os.ReadFile(path.Join(td, "ref-msg-race.json"))
+	ch, _ := os.ReadFile(path.Join(td, "ch-msg-race-weatherdata.json"))
+
+	c := New(WithLogger(testLogger(t)))
+	msgs := make(chan []byte, 2)
+	msgs <- ref
+	msgs <- ch
+	close(msgs)
+	go c.Replay(context.Background(), msgs)
+
+	<-c.Done()
+	// only the latest snapshot is kept, so it is still buffered once the client is done
+	meeting := <-c.Meeting()
+	if meeting.Weather.TrackTemp != 30.4 {
+		t.Errorf("expected track temp %.1f after replaying every message but found %.1f", 30.4, meeting.Weather.TrackTemp)
+	}
+}
+
+func testdataDir() string {
+	_, p, _, _ := runtime.Caller(0)
+	return path.Join(filepath.Dir(p), "testdata")
+}
+
+// testLogger creates a new logger to be used in tests that writes all logs to /dev/null so they
+// don't uglify the test output.
+func testLogger(t *testing.T) *slog.Logger {
+	t.Helper()
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func newReferenecedClient(t *testing.T, refpath string) *Client {
+	t.Helper()
+	ref, _ := os.ReadFile(refpath)
+	c := New(WithLogger(testLogger(t)))
+	c.processMessage(ref)
+	// read the initial state so only updates from later messages are left in the channels
+	<-c.Meeting()
+	<-c.Drivers()
+	<-c.RaceCtrlMsgs()
+
+	return c
+}
+
+// collectEvents reads the events buffered in the events channel of the client.
+func collectEvents(c *Client) []domain.Event {
+	var events []domain.Event
+	for {
+		select {
+		case e := <-c.Events():
+			events = append(events, e)
+		default:
+			return events
+		}
+	}
+}
+
+// BenchmarkProcessMessage measures processing the change messages of the race fixtures, including
+// writing the snapshots of the updated state to the client channels.
+func BenchmarkProcessMessage(b *testing.B) {
+	td := testdataDir()
+	ref, _ := os.ReadFile(path.Join(td, "ref-msg-race.json"))
+	var changes [][]byte
+	for _, name := range []string{"ch-msg-race-timingdata.json", "ch-msg-race-cardata.json", "ch-msg-race-weatherdata.json"} {
+		change, _ := os.ReadFile(path.Join(td, name))
+		changes = append(changes, change)
+	}
+
+	c := New(WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	c.processMessage(ref)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, change := range changes {
+			c.processMessage(change)
+		}
+	}
+}
+
+// BenchmarkWriteDriversToChan measures writing a snapshot of the drivers of the race fixture.
+func BenchmarkWriteDriversToChan(b *testing.B) {
+	ref, _ := os.ReadFile(path.Join(testdataDir(), "ref-msg-race.json"))
+	c := New(WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	c.processMessage(ref)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.writeDriversToChan()
+	}
+}
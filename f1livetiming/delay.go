@@ -0,0 +1,246 @@
+package f1livetiming
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// MaxDelay is the longest broadcast delay that can be set.
+const MaxDelay = 5 * time.Minute
+
+// errNoSyncEvent is returned when syncing the delay before a lap counter change was received.
+var errNoSyncEvent = errors.New("no lap counter change received yet")
+
+// Delay is the broadcast delay applied to the live data, e.g. so the timing board is in sync with a
+// TV broadcast that runs behind the F1 LiveTiming API rather than spoiling it. It is safe for
+// concurrent use, e.g. adjusted from the TUI while the client processes messages, and may be shared
+// by successive clients so the delay is kept when reconnecting.
+type Delay struct {
+	mu       sync.Mutex
+	d        time.Duration
+	lapTick  time.Time     // lapTick is the local time the latest lap counter change was received
+	adjusted chan struct{} // adjusted wakes the client waiting for the next frame to be due
+}
+
+// NewDelay returns a broadcast delay of the given duration.
+func NewDelay(d time.Duration) *Delay {
+	return &Delay{d: clampDelay(d), adjusted: make(chan struct{}, 1)}
+}
+
+// Duration returns the current delay.
+func (d *Delay) Duration() time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.d
+}
+
+// Adjust lengthens the delay by delta, or shortens it if delta is negative, returning the new delay;
+// the delay is kept between 0 and MaxDelay.
+func (d *Delay) Adjust(delta time.Duration) time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.set(d.d + delta)
+}
+
+// Sync sets the delay so that the latest lap counter change received is shown now, returning the
+// new delay; it is meant to be called as the lap counter ticks over on the broadcast. An error is
+// returned if no lap counter change has been received yet.
+func (d *Delay) Sync() (time.Duration, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.lapTick.IsZero() {
+		return d.d, errNoSyncEvent
+	}
+	return d.set(time.Since(d.lapTick)), nil
+}
+
+// set replaces the delay, waking the client waiting for the next frame; the lock must be held.
+func (d *Delay) set(v time.Duration) time.Duration {
+	d.d = clampDelay(v)
+	select {
+	case d.adjusted <- struct{}{}:
+	default:
+	}
+	return d.d
+}
+
+// tickLap records that a lap counter change was received at the given time.
+func (d *Delay) tickLap(at time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.lapTick = at
+}
+
+// clampDelay returns the delay kept between 0 and MaxDelay.
+func clampDelay(d time.Duration) time.Duration {
+	return min(max(d, 0), MaxDelay)
+}
+
+// receiveLap tracks the lap counter of the data received at the given time, recording a lap counter
+// change with the delay. It runs as the data is received rather than once it is processed, so the
+// delay is synced with when the lap counter ticked over even while the data is still held.
+func (c *Client) receiveLap(f1msg f1Message, receivedAt time.Time) {
+	if c.delay == nil {
+		return
+	}
+	// the lap counter is rarely part of the data, so the changes are only parsed if it might be
+	if bytes.Contains(f1msg.Changes, []byte(`"LapCount"`)) {
+		var changesMsg []f1ChangeMessage
+		_ = json.Unmarshal(f1msg.Changes, &changesMsg)
+		for _, m := range changesMsg {
+			var msgType string
+			if len(m.Arguments) != 3 || json.Unmarshal(m.Arguments[0], &msgType) != nil || msgType != "LapCount" {
+				continue
+			}
+			var lc lapCount
+			// the lap counter ticking over is the event the delay is synced with
+			if json.Unmarshal(m.Arguments[1], &lc) == nil && lc.CurrentLap != nil && *lc.CurrentLap != c.receivedLap {
+				c.receivedLap = *lc.CurrentLap
+				c.delay.tickLap(receivedAt)
+			}
+		}
+	}
+	// the reference message is the initial state of the session rather than a change
+	if len(f1msg.Reference) > 0 {
+		var refMsg f1ReferenceMessage
+		var lc lapCount
+		if json.Unmarshal(f1msg.Reference, &refMsg) == nil && json.Unmarshal(refMsg.LapCount, &lc) == nil && lc.CurrentLap != nil {
+			c.receivedLap = *lc.CurrentLap
+		}
+	}
+}
+
+/* Delay buffer
+------------------------------------------------------------------------------------------------- */
+
+// delayedFrame is the processing of a frame held by the delay buffer until it is due.
+type delayedFrame struct {
+	receivedAt time.Time
+	process    func()
+}
+
+// delayBuffer holds the frames received while listening until the delay after they were received
+// has passed; it is unbounded as the number of frames held depends on the delay set at the time.
+type delayBuffer struct {
+	mu     sync.Mutex
+	frames []delayedFrame
+	closed bool
+	added  chan struct{} // added wakes the client waiting for a frame to be held
+}
+
+// newDelayBuffer returns an empty delay buffer.
+func newDelayBuffer() *delayBuffer {
+	return &delayBuffer{added: make(chan struct{}, 1)}
+}
+
+// hold adds the processing of the frame received at the given time to the buffer.
+func (b *delayBuffer) hold(receivedAt time.Time, process func()) {
+	b.mu.Lock()
+	b.frames = append(b.frames, delayedFrame{receivedAt, process})
+	b.mu.Unlock()
+	select {
+	case b.added <- struct{}{}:
+	default:
+	}
+}
+
+// close marks that no more frames will be held, so the buffer is drained once the frames held are
+// due.
+func (b *delayBuffer) close() {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+	select {
+	case b.added <- struct{}{}:
+	default:
+	}
+}
+
+// next returns the oldest frame held without removing it; false is returned if there is none, and
+// closed indicates that none will be held anymore.
+func (b *delayBuffer) next() (f delayedFrame, ok, closed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.frames) == 0 {
+		return f, false, b.closed
+	}
+	return b.frames[0], true, b.closed
+}
+
+// pop removes the oldest frame held.
+func (b *delayBuffer) pop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.frames[0] = delayedFrame{}
+	b.frames = b.frames[1:]
+}
+
+// processDelayed processes the frames held by the buffer in the order they were received once the
+// delay after they were received has passed, until the buffer is drained once closed or the context
+// is cancelled. Frames held are processed sooner, or later, as soon as the delay is adjusted.
+func (c *Client) processDelayed(ctx context.Context, b *delayBuffer) {
+	for {
+		f, ok, closed := b.next()
+		if !ok {
+			if closed {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-b.added:
+			}
+			continue
+		}
+		wait := time.Until(f.receivedAt.Add(c.delay.Duration()))
+		if wait <= 0 {
+			b.pop()
+			f.process()
+			continue
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-c.delay.adjusted:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// delayFrames starts processing the frames held by the delay buffer while listening; the returned
+// function stops once the frames held are processed if drain is true, or drops them otherwise.
+func (c *Client) delayFrames(ctx context.Context) func(drain bool) {
+	ctx, cancel := context.WithCancel(ctx)
+	c.buffer = newDelayBuffer()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.processDelayed(ctx, c.buffer)
+	}()
+	return func(drain bool) {
+		if drain {
+			c.buffer.close()
+		} else {
+			cancel()
+		}
+		<-done
+		cancel()
+	}
+}
+
+// process runs the processing of the data received at the given time, once it is due if listening
+// with a delay.
+func (c *Client) process(receivedAt time.Time, fn func()) {
+	if c.buffer == nil {
+		fn()
+		return
+	}
+	c.buffer.hold(receivedAt, fn)
+}
@@ -0,0 +1,159 @@
+package f1livetiming
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+func TestDelay(t *testing.T) {
+	td := testdataDir()
+	ref, _ := os.ReadFile(path.Join(td, "ref-msg-race.json"))
+	lap := `{"M":[{"H":"Streaming","M":"feed","A":["LapCount",{"CurrentLap":12},"2024-12-08T13:43:21.123Z"]}]}`
+
+	t.Run("Adjust", func(t *testing.T) {
+		d := NewDelay(30 * time.Second)
+		if v := d.Adjust(time.Second); v != 31*time.Second {
+			t.Errorf("expected delay %s but found %s", 31*time.Second, v)
+		}
+		if v := d.Adjust(-time.Minute); v != 0 {
+			t.Errorf("expected delay %s but found %s", time.Duration(0), v)
+		}
+		if v := d.Adjust(time.Hour); v != MaxDelay {
+			t.Errorf("expected delay %s but found %s", MaxDelay, v)
+		}
+	})
+
+	t.Run("Sync", func(t *testing.T) {
+		d := NewDelay(0)
+		if _, err := d.Sync(); !errors.Is(err, errNoSyncEvent) {
+			t.Errorf("expected error '%s' but found '%v'", errNoSyncEvent, err)
+		}
+		d.tickLap(time.Now().Add(-42 * time.Second))
+		v, err := d.Sync()
+		if err != nil {
+			t.Fatalf("expected no error but found '%s'", err)
+		}
+		if v < 42*time.Second || v > 43*time.Second {
+			t.Errorf("expected a delay of about %s but found %s", 42*time.Second, v)
+		}
+	})
+
+	t.Run("LapTick", func(t *testing.T) {
+		d := NewDelay(0)
+		c := New(WithLogger(testLogger(t)), WithDelay(d))
+		c.processMessage(ref)
+		// only the lap counter ticking over is synced with, not every lap count change
+		c.processMessage([]byte(`{"M":[{"H":"Streaming","M":"feed","A":["LapCount",{"TotalLaps":58},"2024-12-08T13:43:21.123Z"]}]}`))
+		if _, err := d.Sync(); !errors.Is(err, errNoSyncEvent) {
+			t.Errorf("expected error '%s' but found '%v'", errNoSyncEvent, err)
+		}
+		c.processMessage([]byte(lap))
+		if _, err := d.Sync(); err != nil {
+			t.Errorf("expected no error but found '%s'", err)
+		}
+	})
+
+	t.Run("Hold", func(t *testing.T) {
+		srv := newFakeSignalR(t, 0,
+			func(ctx context.Context, conn *websocket.Conn) {
+				readSubscribe(ctx, t, conn)
+				sendFrames(ctx, t, conn, string(ref), lap)
+				conn.Close(websocket.StatusNormalClosure, "session ended")
+			},
+			nil,
+		)
+		d := NewDelay(200 * time.Millisecond)
+		start := time.Now()
+		c := listenToFake(t, srv, WithDelay(d))
+
+		// the data held is processed once the connection is closed normally
+		if err := <-c.Done(); err != nil {
+			t.Fatalf("expected no error but found '%s'", err)
+		}
+		if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+			t.Errorf("expected the data to be held for %s but found %s", 200*time.Millisecond, elapsed)
+		}
+		snapshot := c.Snapshot()
+		if len(snapshot.Drivers) != 20 {
+			t.Errorf("expected %d drivers but found %d", 20, len(snapshot.Drivers))
+		}
+		if l := snapshot.Meeting.Session.CurrentLap; l != 12 {
+			t.Errorf("expected lap %d but found %d", 12, l)
+		}
+		// the lap counter change is received before it is shown
+		if v, err := d.Sync(); err != nil || v < 200*time.Millisecond {
+			t.Errorf("expected a delay of at least %s but found %s (%v)", 200*time.Millisecond, v, err)
+		}
+	})
+
+	t.Run("SyncWhileHeld", func(t *testing.T) {
+		srv := newFakeSignalR(t, 0,
+			func(ctx context.Context, conn *websocket.Conn) {
+				readSubscribe(ctx, t, conn)
+				sendFrames(ctx, t, conn, string(ref), lap)
+				conn.Read(ctx)
+			},
+			nil,
+		)
+		// the delay is longer than the broadcast runs behind, so the lap counter ticks over on the
+		// broadcast while the change is still held
+		d := NewDelay(time.Minute)
+		c := listenToFake(t, srv, WithDelay(d))
+
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			if _, err := d.Sync(); err == nil {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		v, err := d.Sync()
+		if err != nil {
+			t.Fatalf("expected the lap counter change to be recorded as it is received but found '%s'", err)
+		}
+		if v >= time.Second {
+			t.Errorf("expected a delay of less than %s but found %s", time.Second, v)
+		}
+		// the change held is shown once it is due with the synced delay
+		deadline = time.Now().Add(2 * time.Second)
+		for c.Snapshot().Meeting.Session.CurrentLap != 12 && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if l := c.Snapshot().Meeting.Session.CurrentLap; l != 12 {
+			t.Errorf("expected lap %d but found %d", 12, l)
+		}
+	})
+
+	t.Run("Shorten", func(t *testing.T) {
+		srv := newFakeSignalR(t, 0,
+			func(ctx context.Context, conn *websocket.Conn) {
+				readSubscribe(ctx, t, conn)
+				sendFrames(ctx, t, conn, string(ref))
+				conn.Read(ctx)
+			},
+			nil,
+		)
+		d := NewDelay(time.Minute)
+		c := listenToFake(t, srv, WithDelay(d))
+
+		time.Sleep(100 * time.Millisecond)
+		if n := len(c.Snapshot().Drivers); n != 0 {
+			t.Fatalf("expected the data to be held but found %d drivers", n)
+		}
+		// the data held is processed as soon as it is due with the shorter delay
+		d.Adjust(-time.Minute)
+		deadline := time.Now().Add(2 * time.Second)
+		for len(c.Snapshot().Drivers) == 0 && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if n := len(c.Snapshot().Drivers); n != 20 {
+			t.Errorf("expected %d drivers but found %d", 20, n)
+		}
+	})
+}
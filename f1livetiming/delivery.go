@@ -0,0 +1,61 @@
+package f1livetiming
+
+import "sync/atomic"
+
+// defaultBufferSize is the number of race control messages and events buffered for consumers
+const defaultBufferSize = 256
+
+// DeliveryStats counts the updates that weren't delivered to consumers because they weren't keeping
+// up with the F1 LiveTiming API; the client never waits for consumers to read its channels.
+type DeliveryStats struct {
+	SnapshotsReplaced   uint64 `json:"snapshots_replaced"`     // SnapshotsReplaced counts unread snapshots replaced by a newer snapshot
+	RaceCtrlMsgsDropped uint64 `json:"race_ctrl_msgs_dropped"` // RaceCtrlMsgsDropped counts race control messages dropped from a full buffer
+	EventsDropped       uint64 `json:"events_dropped"`         // EventsDropped counts events dropped from a full buffer
+}
+
+// deliveryCounters are shared by copies of the client so the stats can be read while the client is
+// writing to its channels.
+type deliveryCounters struct {
+	snapshotsReplaced   atomic.Uint64
+	raceCtrlMsgsDropped atomic.Uint64
+	eventsDropped       atomic.Uint64
+}
+
+// stats returns the current value of the counters.
+func (d *deliveryCounters) stats() DeliveryStats {
+	return DeliveryStats{
+		SnapshotsReplaced:   d.snapshotsReplaced.Load(),
+		RaceCtrlMsgsDropped: d.raceCtrlMsgsDropped.Load(),
+		EventsDropped:       d.eventsDropped.Load(),
+	}
+}
+
+// sendLatest writes the value to a channel buffered for a single value without blocking; a value
+// that hasn't been read yet is replaced so consumers always read the latest snapshot. The client is
+// the only writer to the channel, so the loop ends once the unread value is discarded.
+func sendLatest[T any](ch chan T, v T) (replaced bool) {
+	for {
+		select {
+		case ch <- v:
+			return replaced
+		default:
+		}
+		// the consumer may have read the unread value in the meantime
+		select {
+		case <-ch:
+			replaced = true
+		default:
+		}
+	}
+}
+
+// sendOrDrop writes the value to a buffered channel without blocking; the value is dropped if the
+// buffer is full.
+func sendOrDrop[T any](ch chan T, v T) (sent bool) {
+	select {
+	case ch <- v:
+		return true
+	default:
+		return false
+	}
+}
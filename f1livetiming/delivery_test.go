@@ -0,0 +1,52 @@
+package f1livetiming
+
+import (
+	"os"
+	"path"
+	"testing"
+)
+
+func TestDelivery(t *testing.T) {
+	td := testdataDir()
+
+	t.Run("LatestSnapshot", func(t *testing.T) {
+		ch := make(chan int, 1)
+		if sendLatest(ch, 1) {
+			t.Errorf("expected the first value not to replace another")
+		}
+		if !sendLatest(ch, 2) {
+			t.Errorf("expected the unread value to be replaced")
+		}
+		if v := <-ch; v != 2 {
+			t.Errorf("expected the latest value %d but found %d", 2, v)
+		}
+	})
+
+	t.Run("SlowConsumer", func(t *testing.T) {
+		c := New(WithLogger(testLogger(t)), WithBufferSize(1))
+		ref, _ := os.ReadFile(path.Join(td, "ref-msg-race.json"))
+		change, _ := os.ReadFile(path.Join(td, "ch-msg-race-timingdata.json"))
+		status, _ := os.ReadFile(path.Join(td, "ch-msg-race-sessionstatus.json"))
+		// none of the channels are read while the messages are processed
+		c.processMessage(ref)
+		c.processMessage(ref)
+		c.processMessage(change)
+		c.processMessage(status)
+
+		drivers := <-c.Drivers()
+		if drivers["61"].TimingData.Position != 18 {
+			t.Errorf("expected the latest position %d but found %d", 18, drivers["61"].TimingData.Position)
+		}
+		stats := c.DeliveryStats()
+		if stats.SnapshotsReplaced == 0 {
+			t.Errorf("expected unread snapshots to be replaced")
+		}
+		if stats.RaceCtrlMsgsDropped != 1 {
+			t.Errorf("expected %d race control message dropped but found %d", 1, stats.RaceCtrlMsgsDropped)
+		}
+		// the position changes fill the buffer before the session status changes
+		if stats.EventsDropped != 8 {
+			t.Errorf("expected %d events dropped but found %d", 8, stats.EventsDropped)
+		}
+	})
+}
@@ -0,0 +1,19 @@
+package f1livetiming
+
+import "errors"
+
+// The errors the client exits with are one of the following, wrapping the underlying cause; use
+// errors.Is to tell them apart, e.g. to decide whether to retry.
+var (
+	// ErrNegotiate is the error of the negotiation request preceding the websocket connection.
+	ErrNegotiate = errors.New("error negotiating f1 livetiming api connection")
+	// ErrDial is the error of opening the websocket connection.
+	ErrDial = errors.New("error dialing f1 livetiming api websocket")
+	// ErrSubscribe is the error of subscribing to the data feeds once connected.
+	ErrSubscribe = errors.New("error subscribing to f1 livetiming api feeds")
+	// ErrProtocol is the error of the F1 LiveTiming API responding in a way the client doesn't
+	// understand, e.g. a negotiation response without a connection token.
+	ErrProtocol = errors.New("unexpected f1 livetiming api response")
+	// ErrUpstreamClosed is the error of the F1 LiveTiming API closing the connection unexpectedly.
+	ErrUpstreamClosed = errors.New("f1 livetiming api closed the connection")
+)
@@ -0,0 +1,126 @@
+package f1livetiming
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+func TestListenErrors(t *testing.T) {
+	negotiated := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ConnectionToken":"token"}`))
+	}
+	tests := []struct {
+		name      string
+		negotiate http.HandlerFunc
+		connect   http.HandlerFunc
+		opts      []ClientOption
+		expected  []error
+	}{
+		{
+			name:      "Negotiate",
+			negotiate: func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusForbidden) },
+			expected:  []error{ErrNegotiate},
+		},
+		{
+			name:      "Protocol",
+			negotiate: func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(`{}`)) },
+			expected:  []error{ErrNegotiate, ErrProtocol},
+		},
+		{
+			name:      "Dial",
+			negotiate: negotiated,
+			connect:   func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNotFound) },
+			expected:  []error{ErrDial},
+		},
+		{
+			name:      "UpstreamClosed",
+			negotiate: negotiated,
+			connect: func(w http.ResponseWriter, r *http.Request) {
+				conn, err := websocket.Accept(w, r, nil)
+				if err != nil {
+					return
+				}
+				// read the subscribe message before going away
+				conn.Read(r.Context())
+				conn.Close(websocket.StatusGoingAway, "restarting")
+			},
+			// no data is ever received, so the client gives up once the connection was retried for long enough
+			opts:     []ClientOption{WithRetryFor(200 * time.Millisecond)},
+			expected: []error{ErrUpstreamClosed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/signalr/negotiate", tt.negotiate)
+			if tt.connect != nil {
+				mux.HandleFunc("/signalr/connect", tt.connect)
+			}
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			err := listenUntilDone(t, context.Background(), srv, tt.opts...)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			for _, expected := range tt.expected {
+				if !errors.Is(err, expected) {
+					t.Errorf("expected error '%s' but found '%s'", expected, err)
+				}
+			}
+		})
+	}
+
+	t.Run("Cancel", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/signalr/negotiate", negotiated)
+		mux.HandleFunc("/signalr/connect", func(w http.ResponseWriter, r *http.Request) {
+			conn, err := websocket.Accept(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer conn.CloseNow()
+			// keep the connection open until the client closes it
+			for {
+				if _, _, err := conn.Read(r.Context()); err != nil {
+					return
+				}
+			}
+		})
+		srv := httptest.NewServer(mux)
+		defer srv.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		if err := listenUntilDone(t, ctx, srv); err != nil {
+			t.Errorf("expected no error once the context is cancelled but found '%s'", err)
+		}
+	})
+}
+
+// listenUntilDone listens to the server until the client exits, returning the error it exited with.
+func listenUntilDone(t *testing.T, ctx context.Context, srv *httptest.Server, opts ...ClientOption) error {
+	t.Helper()
+	c := New(append([]ClientOption{
+		WithLogger(testLogger(t)),
+		WithHTTPBaseURL(srv.URL),
+		WithWSBaseURL(strings.Replace(srv.URL, "http://", "ws://", 1)),
+	}, opts...)...)
+	go c.Listen(ctx)
+
+	select {
+	case err := <-c.Done():
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the client to exit")
+		return nil
+	}
+}
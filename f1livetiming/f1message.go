@@ -36,12 +36,28 @@ const (
 	raceCtrlStatusOther      = "Other"
 )
 
+const (
+	archiveStatusComplete = "Complete" // archive status of a session that has ended and been archived
+)
+
 // f1Message represents a websocket message from the F1 Live Timing API. It comes in two primary
 // varieties: Change messages and Reference messages. There is a single Reference message sent at
 // the beginning of the websocket connection, followed by updates via Change maessages.
+//
+// Messages are SignalR frames, either persistent connection responses carrying the changes, or hub
+// responses answering an invocation, e.g. the reference message answering Subscribe; empty frames are
+// keep-alives. Fields that differ between the two are only interpreted once the kind is known.
 type f1Message struct {
 	Changes   json.RawMessage `json:"M"`
 	Reference json.RawMessage `json:"R"`
+	// persistent connection responses
+	MessageID   string          `json:"C"` // MessageID is the cursor sent back to resume a dropped connection
+	GroupsToken string          `json:"G"` // GroupsToken is the token of the groups sent back to resume a dropped connection
+	Initialized json.RawMessage `json:"S"` // Initialized is set on the first frame of a connection
+	Reconnect   json.RawMessage `json:"T"` // Reconnect is set when the server asks the client to reconnect
+	// hub responses
+	InvocationID json.RawMessage `json:"I"` // InvocationID is the id of the invocation answered, a string or number
+	Error        string          `json:"E"` // Error is the error of a failed invocation
 }
 
 // f1ChangeMessage represents a 'change' message sent on the websocket connection from the server.
@@ -61,8 +77,12 @@ type f1ReferenceMessage struct {
 	RaceCtrlMsgs  json.RawMessage `json:"RaceControlMessages"` // RaceCtrlMsgs contains all emitted race control messages
 	SessionInfo   json.RawMessage `json:"SessionInfo"`         // SessionInfo contains intrinsic data about the event and session
 	SessionData   json.RawMessage `json:"SessionData"`         // SesionData contains all emitted session and track status changes
+	SessionStatus json.RawMessage `json:"SessionStatus"`       // SessionStatus contains the current session status
+	TrackStatus   json.RawMessage `json:"TrackStatus"`         // TrackStatus contains the current track status
 	TimingData    json.RawMessage `json:"TimingData"`          // TimingData represents driver-specific lap times, intervals, etc.
 	LapCount      json.RawMessage `json:"LapCount"`            // LapCount contains the latest lap (current/total) data
+	WeatherData   json.RawMessage `json:"WeatherData"`         // WeatherData contains the latest weather reported at the circuit
+	CarData       json.RawMessage `json:"CarData.z"`           // CarData contains compressed per-driver telemetry samples
 }
 
 // The heartbeat message indicates the client connection to the server is working even if there are
@@ -143,6 +163,12 @@ type stint struct {
 	LapNumber       *int    `json:"LapNumber"`
 }
 
+// sessionStatus contains the current status of the session, e.g. "Started" or "Aborted".
+type sessionStatus struct {
+	Status *string `json:"Status"`
+}
+
+// trackStatus contains the current flag or (virtual) safety car status of the track.
 type trackStatus struct {
 	Status  string `json:"Status"`
 	Message string `json:"Message"`
@@ -547,3 +573,39 @@ type lapCount struct {
 	CurrentLap *int `json:"CurrentLap"`
 	TotalLaps  *int `json:"TotalLaps"`
 }
+
+// weatherData contains the latest readings of the weather station at the circuit; every value is
+// sent as a string.
+type weatherData struct {
+	AirTemp       *string `json:"AirTemp"`
+	Humidity      *string `json:"Humidity"`
+	Pressure      *string `json:"Pressure"`
+	Rainfall      *string `json:"Rainfall"`
+	TrackTemp     *string `json:"TrackTemp"`
+	WindDirection *string `json:"WindDirection"`
+	WindSpeed     *string `json:"WindSpeed"`
+}
+
+const (
+	carDataChannelRPM      = "0"
+	carDataChannelSpeed    = "2"
+	carDataChannelGear     = "3"
+	carDataChannelThrottle = "4"
+	carDataChannelBrake    = "5"
+	carDataChannelDRS      = "45"
+)
+
+// carData contains batches of telemetry samples of each car. The message is sent as a base64
+// encoded, deflate compressed JSON document (hence the `.z` suffix of the topic).
+type carData struct {
+	Entries []carDataEntry `json:"Entries"`
+}
+
+// carDataEntry contains the telemetry channels of each car, keyed by racing number, sampled at the
+// same time.
+type carDataEntry struct {
+	UTC  time.Time `json:"Utc"`
+	Cars map[string]struct {
+		Channels map[string]int `json:"Channels"`
+	} `json:"Cars"`
+}
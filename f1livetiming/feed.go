@@ -0,0 +1,54 @@
+package f1livetiming
+
+import "time"
+
+// defaultStaleAfter is how long the connection may go without a heartbeat or change by default; the
+// F1 LiveTiming API publishes a heartbeat every few seconds throughout a session.
+const defaultStaleAfter = 30 * time.Second
+
+// updateHeartbeat converts a Heartbeat msg from the F1 LiveTiming API to the `Feed` domain model of
+// the meeting, measuring how far behind the server the data is received. Replayed heartbeats are
+// ignored as the time they are processed has no relation to when they were published.
+func (c *Client) updateHeartbeat(hb heartbeat) (meetingUpdating, driversUpdated, raceCtrlMsgsUpdated bool) {
+	if !c.live || hb.ReceivedAt.IsZero() {
+		return meetingUpdating, driversUpdated, raceCtrlMsgsUpdated
+	}
+	// the heartbeat may be processed later than it was received when the data is delayed
+	c.meeting.Feed.Heartbeat = hb.ReceivedAt
+	c.meeting.Feed.ReceivedAt = c.receivedAt
+	c.meeting.Feed.Delay = c.receivedAt.Sub(hb.ReceivedAt)
+	return true, driversUpdated, raceCtrlMsgsUpdated
+}
+
+// clearStale clears the staleness of the data once a heartbeat or change is processed.
+func (c *Client) clearStale() {
+	if c.meeting.Feed.Stale {
+		c.meeting.Feed.Stale = false
+		c.publishSnapshot(false)
+		c.writeMeetingToChan()
+	}
+}
+
+// markStale marks the data as stale while the connection is being re-established.
+func (c *Client) markStale() {
+	if c.meeting.Feed.Stale {
+		return
+	}
+	c.meeting.Feed.Stale = true
+	c.publishSnapshot(false)
+	c.writeMeetingToChan()
+}
+
+// stallDeadline returns the time by which data must be received for the connection not to be
+// considered stalled; a new connection is given as long as the previous one to receive data. False
+// is returned if stall detection is disabled.
+func (c *Client) stallDeadline() (time.Time, bool) {
+	if c.staleAfter <= 0 || c.lastData.IsZero() {
+		return time.Time{}, false
+	}
+	since := c.lastData
+	if c.connectedAt.After(since) {
+		since = c.connectedAt
+	}
+	return since.Add(c.staleAfter), true
+}
@@ -0,0 +1,82 @@
+package f1livetiming
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+func TestFeed(t *testing.T) {
+	td := testdataDir()
+	ref, _ := os.ReadFile(path.Join(td, "ref-msg-race.json"))
+
+	t.Run("Heartbeat", func(t *testing.T) {
+		c := New(WithLogger(testLogger(t)))
+		c.live = true
+		c.processMessage(ref)
+
+		sent := time.Now().Add(-2 * time.Second).UTC()
+		c.processMessage(heartbeatMsg(sent))
+
+		feed := c.Snapshot().Meeting.Feed
+		if !feed.Heartbeat.Equal(sent) {
+			t.Errorf("expected heartbeat '%s' but found '%s'", sent, feed.Heartbeat)
+		}
+		if feed.Delay < 2*time.Second || feed.Delay > 3*time.Second {
+			t.Errorf("expected a delay of about %s but found %s", 2*time.Second, feed.Delay)
+		}
+		if feed.Stale {
+			t.Errorf("expected the feed not to be stale")
+		}
+	})
+
+	t.Run("Replayed", func(t *testing.T) {
+		c := New(WithLogger(testLogger(t)))
+		c.processMessage(ref)
+		c.processMessage(heartbeatMsg(time.Now()))
+
+		if feed := c.Snapshot().Meeting.Feed; feed.IsLive() {
+			t.Errorf("expected replayed heartbeats to be ignored but found %+v", feed)
+		}
+	})
+
+	t.Run("Stall", func(t *testing.T) {
+		resumed := make(chan struct{})
+		srv := newFakeSignalR(t, 0,
+			func(ctx context.Context, conn *websocket.Conn) {
+				readSubscribe(ctx, t, conn)
+				sendFrames(ctx, t, conn, string(ref))
+				// keep the connection alive without ever sending data until the client gives up on it
+				for conn.Write(ctx, websocket.MessageText, []byte(`{}`)) == nil {
+					time.Sleep(10 * time.Millisecond)
+				}
+			},
+			func(ctx context.Context, conn *websocket.Conn, _ url.Values) {
+				close(resumed)
+				conn.Read(ctx)
+			},
+		)
+		c := listenToFake(t, srv, WithStaleAfter(100*time.Millisecond))
+
+		select {
+		case <-resumed:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected the stalled connection to be resumed")
+		}
+		if !c.Snapshot().Meeting.Feed.Stale {
+			t.Errorf("expected the feed to be stale while the connection is resumed")
+		}
+	})
+}
+
+// heartbeatMsg returns a change message of a heartbeat sent by the server at the given time.
+func heartbeatMsg(sent time.Time) []byte {
+	utc := sent.UTC().Format(time.RFC3339Nano)
+	return []byte(fmt.Sprintf(`{"M":[{"H":"Streaming","M":"feed","A":["Heartbeat",{"Utc":"%s"},"%s"]}]}`, utc, utc))
+}
@@ -0,0 +1,284 @@
+package f1livetiming
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+const (
+	connectPath   = "/signalr/connect"   // connectPath is the path of the websocket endpoint starting a connection
+	reconnectPath = "/signalr/reconnect" // reconnectPath is the path of the websocket endpoint resuming a connection
+	// subscribeInvocationID is the id of the Subscribe invocation answered by the reference message
+	subscribeInvocationID = "1"
+	// the delay between attempts to re-establish a lost connection doubles from the shortest to the
+	// longest
+	minReconnectDelay = 500 * time.Millisecond
+	maxReconnectDelay = 5 * time.Second
+	// defaultRetryFor is how long a lost connection is retried for since data was last received by
+	// default
+	defaultRetryFor = 2 * time.Minute
+)
+
+// subscribeTopics are the data feeds subscribed to; each is a topic of the change messages.
+var subscribeTopics = []string{
+	"Heartbeat",
+	"TimingStats",
+	"TimingAppData",
+	"TrackStatus",
+	"DriverList",
+	"RaceControlMessages",
+	"SessionInfo",
+	"SessionData",
+	"SessionStatus",
+	"LapCount",
+	"TimingData",
+	"WeatherData",
+	"CarData.z",
+}
+
+// errConnectionLost is the error of the connection being lost in a way that it can be resumed, e.g.
+// no keep-alive being received or the server asking the client to reconnect.
+var errConnectionLost = errors.New("connection lost")
+
+// dial opens the websocket connection with the F1 LiveTiming API at the given URL.
+func (c *Client) dial(ctx context.Context, u *url.URL) (*websocket.Conn, error) {
+	// Add required headers
+	headers := make(http.Header)
+	headers.Add("User-Agent", "BestHTTP")
+	headers.Add("Accept-Encoding", "gzip,identity")
+	headers.Add("Cookie", c.cookie)
+	conn, _, err := websocket.Dial(ctx, u.String(), &websocket.DialOptions{HTTPHeader: headers})
+	if err != nil {
+		return nil, err
+	}
+	// disable size limitats as the F1 LiveTiming API sends some big messages
+	conn.SetReadLimit(-1)
+	return conn, nil
+}
+
+// connect starts a new connection to the legacy SignalR endpoint of the F1 LiveTiming API and
+// subscribes to the data feeds; the reference message answering the subscription is received on the
+// connection. The error returned wraps one of the client errors.
+func (c *Client) connect(ctx context.Context) (*websocket.Conn, error) {
+	// a new connection doesn't continue from the messages of a previous one
+	c.messageID, c.groupsToken = "", ""
+	// Call negotiate to get required token/cookie values
+	if err := c.negotiate(ctx); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNegotiate, err)
+	}
+	// Create the websocket connection with the F1 livetiming API server
+	u, err := c.websocketURL(connectPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDial, err)
+	}
+	conn, err := c.dial(ctx, u)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDial, err)
+	}
+	// send subscribe message to start receiving messages from the F1 LiveTiming API
+	if err := c.sendSubscribeMsg(ctx, conn); err != nil {
+		conn.CloseNow()
+		return nil, fmt.Errorf("%w: %w", ErrSubscribe, err)
+	}
+	c.connectedAt = time.Now()
+	return conn, nil
+}
+
+// resume resumes the lost connection from the latest message received, so that no change is missed
+// or processed twice.
+func (c *Client) resume(ctx context.Context) (*websocket.Conn, error) {
+	u, err := c.websocketURL(reconnectPath)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := c.dial(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	c.connectedAt = time.Now()
+	return conn, nil
+}
+
+// reconnect re-establishes the lost connection. It is resumed until the disconnect timeout of the
+// connection passes, after which the server has discarded it, and replaced by a new connection
+// receiving the state of the session anew after that. Failed attempts are retried with exponential
+// backoff until the retry deadline passes.
+func (c *Client) reconnect(ctx context.Context) (*websocket.Conn, error) {
+	deadline, err := c.retryDeadline()
+	if err != nil {
+		return nil, err
+	}
+	resumeBy := time.Now().Add(c.disconnectTimeout)
+	if deadline.Before(resumeBy) {
+		resumeBy = deadline
+	}
+	conn, err := retry(ctx, c.logger, resumeBy, c.resume)
+	if err == nil || ctx.Err() != nil {
+		return conn, err
+	}
+	c.logger.Warn("error resuming connection; starting a new connection", "err", err)
+	return retry(ctx, c.logger, deadline, c.connect)
+}
+
+// retryDeadline returns the time by which a lost connection must be re-established; connections
+// lost again before any data was received on them don't extend it. An error is returned if it has
+// already passed.
+func (c *Client) retryDeadline() (time.Time, error) {
+	deadline := c.lastData.Add(c.retryFor)
+	if !time.Now().Before(deadline) {
+		return deadline, fmt.Errorf("no data received for %s", c.retryFor)
+	}
+	return deadline, nil
+}
+
+// retry calls attempt until it succeeds, waiting between failed attempts for a delay doubling from
+// minReconnectDelay to maxReconnectDelay. The error of the latest attempt is returned once the next
+// one would be past the deadline or the context is cancelled.
+func retry[T any](ctx context.Context, logger *slog.Logger, deadline time.Time, attempt func(context.Context) (T, error)) (T, error) {
+	delay := minReconnectDelay
+	for {
+		v, err := attempt(ctx)
+		if err == nil {
+			return v, nil
+		}
+		if ctx.Err() != nil || time.Now().Add(delay).After(deadline) {
+			return v, err
+		}
+		logger.Warn("error reconnecting", "err", err, "retry_in", delay)
+		select {
+		case <-ctx.Done():
+			return v, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(maxReconnectDelay, delay*2)
+	}
+}
+
+// readMessages processes the messages received on the connection until it is closed, which it is
+// once readMessages returns. The error returned wraps errConnectionLost if the connection should be
+// resumed, or one of the client errors otherwise; nil is returned once the connection is closed
+// normally or the context is cancelled.
+func (c *Client) readMessages(ctx context.Context, conn *websocket.Conn) error {
+	defer conn.CloseNow()
+
+	for {
+		msg, err := c.read(ctx, conn)
+		if err != nil {
+			switch websocket.CloseStatus(err) {
+			case websocket.StatusNormalClosure:
+				conn.Close(websocket.StatusNormalClosure, "client closed")
+				return nil
+			case websocket.StatusProtocolError, websocket.StatusUnsupportedData, websocket.StatusInvalidFramePayloadData:
+				return fmt.Errorf("%w: %w", ErrProtocol, err)
+			}
+			if ctx.Err() != nil {
+				conn.Close(websocket.StatusNormalClosure, "client closed")
+				return nil
+			}
+			return fmt.Errorf("%w: %w", errConnectionLost, err)
+		}
+		// No errors, process the message from the livetiming API
+		if err := c.handleMessage(msg); err != nil {
+			return err
+		}
+	}
+}
+
+// handleMessage passes the message received from the F1 LiveTiming API to the raw message handler
+// before processing it.
+func (c *Client) handleMessage(msg []byte) error {
+	if c.rawMessageHandler != nil {
+		c.rawMessageHandler(msg)
+	}
+	return c.processMessage(msg)
+}
+
+// read returns the next message received on the connection; the connection is considered lost if
+// nothing, not even a keep-alive, is received within the keep-alive timeout, or if it stalls, i.e.
+// keep-alives are received but no data is.
+func (c *Client) read(ctx context.Context, conn *websocket.Conn) ([]byte, error) {
+	ctx, cancel, err := c.readContext(ctx, c.keepAliveTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	_, msg, err := conn.Read(ctx)
+	return msg, err
+}
+
+// readContext returns the context of reading the next message, which expires once the timeout
+// passes or the connection stalls, whichever comes first; a timeout of 0 is no timeout. An error is
+// returned if the connection has already stalled.
+func (c *Client) readContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc, error) {
+	if deadline, ok := c.stallDeadline(); ok {
+		stall := time.Until(deadline)
+		if stall <= 0 {
+			return nil, nil, fmt.Errorf("no data received for %s", c.staleAfter)
+		}
+		if timeout <= 0 || stall < timeout {
+			timeout = stall
+		}
+	}
+	if timeout <= 0 {
+		ctx, cancel := context.WithCancel(ctx)
+		return ctx, cancel, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, cancel, nil
+}
+
+// processProtocolFields tracks the SignalR protocol fields of the message required to resume the
+// connection. An error wrapping ErrSubscribe is returned if the message is the failed result of
+// the Subscribe invocation, and errConnectionLost if the server asks the client to reconnect.
+func (c *Client) processProtocolFields(msg f1Message) error {
+	// hub responses answer an invocation and are never keep-alives or part of the message stream
+	if len(msg.InvocationID) > 0 {
+		id := msg.invocationID()
+		switch {
+		case msg.Error == "":
+			c.logger.Debug("received invocation result", "id", id)
+			return nil
+		case id == subscribeInvocationID:
+			return fmt.Errorf("%w: %s", ErrSubscribe, msg.Error)
+		default:
+			return fmt.Errorf("%w: invocation %s failed: %s", ErrProtocol, id, msg.Error)
+		}
+	}
+	if msg.MessageID != "" {
+		c.messageID = msg.MessageID
+	}
+	if msg.GroupsToken != "" {
+		c.groupsToken = msg.GroupsToken
+	}
+	if len(msg.Initialized) > 0 {
+		c.logger.Debug("connection initialized", "message_id", c.messageID)
+	}
+	if len(msg.Reconnect) > 0 {
+		return fmt.Errorf("%w: server asked the client to reconnect", errConnectionLost)
+	}
+	return nil
+}
+
+// invocationID returns the id of the invocation answered by a hub response; servers answer with the
+// id as a string, but relays may echo it as it was sent.
+func (m f1Message) invocationID() string {
+	var id string
+	if err := json.Unmarshal(m.InvocationID, &id); err == nil {
+		return id
+	}
+	return string(bytes.TrimSpace(m.InvocationID))
+}
+
+// seconds returns the duration of the given number of seconds, as the SignalR timeouts are sent.
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,269 @@
+package f1livetiming
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bcdxn/f1cli/domain"
+	"github.com/coder/websocket"
+)
+
+func TestSignalR(t *testing.T) {
+	td := testdataDir()
+	ref, _ := os.ReadFile(path.Join(td, "ref-msg-race.json"))
+	timing, _ := os.ReadFile(path.Join(td, "ch-msg-race-timingdata.json"))
+	status, _ := os.ReadFile(path.Join(td, "ch-msg-race-sessionstatus.json"))
+
+	t.Run("Resume", func(t *testing.T) {
+		var resumed url.Values
+		srv := newFakeSignalR(t, 0,
+			func(ctx context.Context, conn *websocket.Conn) {
+				sendFrames(ctx, t, conn, `{"C":"c-0","S":1,"M":[]}`)
+				readSubscribe(ctx, t, conn)
+				sendFrames(ctx, t, conn, string(ref), withCursor(t, timing, "c-1", "g-1"))
+				// drop the connection without closing it normally
+				conn.Close(websocket.StatusGoingAway, "restarting")
+			},
+			func(ctx context.Context, conn *websocket.Conn, query url.Values) {
+				resumed = query
+				sendFrames(ctx, t, conn, `{}`, withCursor(t, status, "c-2", ""))
+				conn.Close(websocket.StatusNormalClosure, "session ended")
+			},
+		)
+		c := listenToFake(t, srv)
+
+		if err := <-c.Done(); err != nil {
+			t.Fatalf("expected no error but found '%s'", err)
+		}
+		if resumed.Get("messageId") != "c-1" || resumed.Get("groupsToken") != "g-1" {
+			t.Errorf("expected to resume from message '%s' of groups '%s' but found %v", "c-1", "g-1", resumed)
+		}
+		// the changes of both connections are processed
+		snapshot := c.Snapshot()
+		if p := snapshot.Drivers["23"].TimingData.Position; p != 16 {
+			t.Errorf("expected position %d but found %d", 16, p)
+		}
+		if s := snapshot.Meeting.Session.Status; s != domain.SessionStatusEnded {
+			t.Errorf("expected status '%s' but found '%s'", domain.SessionStatusEnded, s)
+		}
+	})
+
+	t.Run("KeepAlive", func(t *testing.T) {
+		resumed := make(chan struct{})
+		srv := newFakeSignalR(t, 0.1,
+			func(ctx context.Context, conn *websocket.Conn) {
+				readSubscribe(ctx, t, conn)
+				sendFrames(ctx, t, conn, string(ref))
+				// stay silent until the client gives up on the connection
+				conn.Read(ctx)
+			},
+			func(ctx context.Context, conn *websocket.Conn, _ url.Values) {
+				close(resumed)
+				conn.Close(websocket.StatusNormalClosure, "session ended")
+			},
+		)
+		c := listenToFake(t, srv)
+
+		if err := <-c.Done(); err != nil {
+			t.Fatalf("expected no error but found '%s'", err)
+		}
+		select {
+		case <-resumed:
+		default:
+			t.Errorf("expected the silent connection to be resumed")
+		}
+	})
+
+	t.Run("ReconnectRequest", func(t *testing.T) {
+		resumed := make(chan struct{})
+		srv := newFakeSignalR(t, 0,
+			func(ctx context.Context, conn *websocket.Conn) {
+				readSubscribe(ctx, t, conn)
+				sendFrames(ctx, t, conn, string(ref), `{"T":1}`)
+				conn.Read(ctx)
+			},
+			func(ctx context.Context, conn *websocket.Conn, _ url.Values) {
+				close(resumed)
+				conn.Close(websocket.StatusNormalClosure, "session ended")
+			},
+		)
+		c := listenToFake(t, srv)
+
+		if err := <-c.Done(); err != nil {
+			t.Fatalf("expected no error but found '%s'", err)
+		}
+		select {
+		case <-resumed:
+		default:
+			t.Errorf("expected the connection to be resumed when the server asks to reconnect")
+		}
+	})
+
+	t.Run("NewConnection", func(t *testing.T) {
+		var connections atomic.Int32
+		srv := newFakeSignalR(t, 0,
+			func(ctx context.Context, conn *websocket.Conn) {
+				readSubscribe(ctx, t, conn)
+				if connections.Add(1) == 1 {
+					sendFrames(ctx, t, conn, string(ref), withCursor(t, timing, "c-1", "g-1"))
+					conn.Close(websocket.StatusGoingAway, "restarting")
+					return
+				}
+				sendFrames(ctx, t, conn, string(ref), withCursor(t, status, "c-1", ""))
+				conn.Close(websocket.StatusNormalClosure, "session ended")
+			},
+			// the server has discarded the connection, so it can't be resumed
+			nil,
+		)
+		c := listenToFake(t, srv)
+
+		if err := <-c.Done(); err != nil {
+			t.Fatalf("expected no error but found '%s'", err)
+		}
+		if n := connections.Load(); n != 2 {
+			t.Errorf("expected %d connections but found %d", 2, n)
+		}
+		if s := c.Snapshot().Meeting.Session.Status; s != domain.SessionStatusEnded {
+			t.Errorf("expected status '%s' but found '%s'", domain.SessionStatusEnded, s)
+		}
+	})
+
+	t.Run("SubscribeError", func(t *testing.T) {
+		srv := newFakeSignalR(t, 0,
+			func(ctx context.Context, conn *websocket.Conn) {
+				readSubscribe(ctx, t, conn)
+				sendFrames(ctx, t, conn, `{"I":"1","E":"unknown topic","H":true}`)
+				conn.Read(ctx)
+			},
+			nil,
+		)
+		c := listenToFake(t, srv)
+
+		if err := <-c.Done(); !errors.Is(err, ErrSubscribe) {
+			t.Errorf("expected error '%s' but found '%v'", ErrSubscribe, err)
+		}
+	})
+
+	t.Run("ProtocolError", func(t *testing.T) {
+		srv := newFakeSignalR(t, 0,
+			func(ctx context.Context, conn *websocket.Conn) {
+				readSubscribe(ctx, t, conn)
+				sendFrames(ctx, t, conn, `not json`)
+				conn.Read(ctx)
+			},
+			nil,
+		)
+		c := listenToFake(t, srv)
+
+		if err := <-c.Done(); !errors.Is(err, ErrProtocol) {
+			t.Errorf("expected error '%s' but found '%v'", ErrProtocol, err)
+		}
+	})
+}
+
+// newFakeSignalR returns a server mimicking the SignalR endpoints of the F1 LiveTiming API, with the
+// given keep-alive timeout in seconds, serving connections and resumed connections with the given
+// handlers; connections are never resumed if the reconnect handler is nil.
+func newFakeSignalR(
+	t *testing.T,
+	keepAliveTimeout float64,
+	connect func(ctx context.Context, conn *websocket.Conn),
+	reconnect func(ctx context.Context, conn *websocket.Conn, query url.Values),
+) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/signalr/negotiate", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(negotiateResponse{
+			ConnectionToken:   "token",
+			KeepAliveTimeout:  keepAliveTimeout,
+			DisconnectTimeout: 1,
+		})
+	})
+	mux.HandleFunc(connectPath, func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+		conn.SetReadLimit(-1)
+		connect(r.Context(), conn)
+	})
+	if reconnect != nil {
+		mux.HandleFunc(reconnectPath, func(w http.ResponseWriter, r *http.Request) {
+			conn, err := websocket.Accept(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer conn.CloseNow()
+			reconnect(r.Context(), conn, r.URL.Query())
+		})
+	}
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// listenToFake returns a client listening to the fake server until the test ends.
+func listenToFake(t *testing.T, srv *httptest.Server, opts ...ClientOption) *Client {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	c := New(append([]ClientOption{
+		WithLogger(testLogger(t)),
+		WithHTTPBaseURL(srv.URL),
+		WithWSBaseURL(strings.Replace(srv.URL, "http://", "ws://", 1)),
+	}, opts...)...)
+	go c.Listen(ctx)
+	return c
+}
+
+// readSubscribe reads the Subscribe invocation sent by the client.
+func readSubscribe(ctx context.Context, t *testing.T, conn *websocket.Conn) {
+	t.Helper()
+	_, msg, err := conn.Read(ctx)
+	if err != nil {
+		t.Errorf("expected the subscribe message but found '%s'", err)
+		return
+	}
+	var inv struct {
+		M string `json:"M"`
+	}
+	if json.Unmarshal(msg, &inv); inv.M != "Subscribe" {
+		t.Errorf("expected the subscribe message but found '%s'", msg)
+	}
+}
+
+// sendFrames writes each frame to the connection.
+func sendFrames(ctx context.Context, t *testing.T, conn *websocket.Conn, frames ...string) {
+	t.Helper()
+	for _, f := range frames {
+		if err := conn.Write(ctx, websocket.MessageText, []byte(f)); err != nil {
+			t.Errorf("error writing frame: %s", err)
+		}
+	}
+}
+
+// withCursor returns the change message with the given message id and groups token.
+func withCursor(t *testing.T, change []byte, messageID, groupsToken string) string {
+	t.Helper()
+	var frame map[string]any
+	if err := json.Unmarshal(change, &frame); err != nil {
+		t.Fatalf("invalid change message: %s", err)
+	}
+	frame["C"] = messageID
+	if groupsToken != "" {
+		frame["G"] = groupsToken
+	}
+	b, _ := json.Marshal(frame)
+	return string(b)
+}
@@ -0,0 +1,391 @@
+package f1livetiming
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+// Transport is the protocol used to connect to the F1 LiveTiming API.
+type Transport string
+
+const (
+	TransportSignalR     Transport = "signalr"     // TransportSignalR is the legacy ASP.NET SignalR endpoint
+	TransportSignalRCore Transport = "signalrcore" // TransportSignalRCore is the ASP.NET Core SignalR endpoint
+)
+
+// Transports returns every transport.
+func Transports() []Transport {
+	return []Transport{TransportSignalR, TransportSignalRCore}
+}
+
+// ParseTransport returns the transport with the given name, e.g. "signalrcore".
+func ParseTransport(name string) (Transport, error) {
+	for _, t := range Transports() {
+		if string(t) == name {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("invalid transport '%s', expected one of: signalr, signalrcore", name)
+}
+
+const (
+	corePath = "/signalrcore" // corePath is the path of the SignalR Core hub
+	// coreRecordSeparator terminates each message of the SignalR Core JSON hub protocol
+	coreRecordSeparator = 0x1e
+	// corePingInterval is how often the client pings the server to keep the connection alive
+	corePingInterval = 15 * time.Second
+	// coreServerTimeout is how long the connection may be silent before it is considered lost; the
+	// server pings the client every 15 seconds
+	coreServerTimeout = 30 * time.Second
+)
+
+// the types of the SignalR Core hub protocol messages handled by the client
+const (
+	coreMessageInvocation = 1
+	coreMessageCompletion = 3
+	coreMessagePing       = 6
+	coreMessageClose      = 7
+)
+
+// errCoreClosed is returned once the server closes the SignalR Core connection without error.
+var errCoreClosed = errors.New("connection closed by the server")
+
+// listenCore connects to the SignalR Core endpoint of the F1 LiveTiming API and processes the
+// messages received until the context is cancelled or the connection is closed. SignalR Core
+// connections can't be resumed, so a lost or stalled connection is replaced by a new one.
+func (c *Client) listenCore(ctx context.Context) error {
+	conn, err := c.connectCore(ctx)
+	if err != nil {
+		return err
+	}
+
+	for {
+		err := c.readCore(ctx, conn)
+		if !errors.Is(err, errConnectionLost) {
+			return err
+		}
+		c.logger.Warn("signalr core connection lost; reconnecting", "err", err)
+		c.process(time.Now(), c.markStale)
+		if conn, err = c.reconnectCore(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("%w: error reconnecting: %w", ErrUpstreamClosed, err)
+		}
+		c.logger.Info("reconnected to signalr core")
+	}
+}
+
+// coreConn is an established SignalR Core connection.
+type coreConn struct {
+	conn    *websocket.Conn
+	pending []byte // pending is whatever the server sent after the handshake response in the same frame
+}
+
+// connectCore starts a new connection to the SignalR Core endpoint and subscribes to the data
+// feeds; the reference state is the result of the subscription received on the connection. The
+// error returned wraps one of the client errors.
+func (c *Client) connectCore(ctx context.Context) (coreConn, error) {
+	if err := c.negotiateCore(ctx); err != nil {
+		return coreConn{}, fmt.Errorf("%w: %w", ErrNegotiate, err)
+	}
+	u, err := c.coreURL()
+	if err != nil {
+		return coreConn{}, fmt.Errorf("%w: %w", ErrDial, err)
+	}
+	conn, err := c.dial(ctx, u)
+	if err != nil {
+		return coreConn{}, fmt.Errorf("%w: %w", ErrDial, err)
+	}
+	// messages may follow the handshake response in the same frame
+	pending, err := c.handshakeCore(ctx, conn)
+	if err != nil {
+		conn.CloseNow()
+		return coreConn{}, err
+	}
+	if err := c.sendCoreSubscribeMsg(ctx, conn); err != nil {
+		conn.CloseNow()
+		return coreConn{}, fmt.Errorf("%w: %w", ErrSubscribe, err)
+	}
+	c.connectedAt = time.Now()
+	return coreConn{conn, pending}, nil
+}
+
+// reconnectCore starts a new connection in place of the lost one, retrying failed attempts with the
+// same backoff as the legacy endpoint until the retry deadline passes.
+func (c *Client) reconnectCore(ctx context.Context) (coreConn, error) {
+	deadline, err := c.retryDeadline()
+	if err != nil {
+		return coreConn{}, err
+	}
+	return retry(ctx, c.logger, deadline, c.connectCore)
+}
+
+// readCore processes the messages received on the connection until it is closed, which it is once
+// readCore returns. The error returned wraps errConnectionLost if the connection should be replaced,
+// or one of the client errors otherwise; nil is returned once the connection is closed normally or
+// the context is cancelled.
+func (c *Client) readCore(ctx context.Context, cc coreConn) error {
+	conn, pending := cc.conn, cc.pending
+	defer conn.CloseNow()
+	pingCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go c.pingCore(pingCtx, conn)
+
+	for {
+		for _, record := range bytes.Split(pending, []byte{coreRecordSeparator}) {
+			if len(bytes.TrimSpace(record)) == 0 {
+				continue
+			}
+			if err := c.processCoreMessage(record); errors.Is(err, errCoreClosed) {
+				conn.Close(websocket.StatusNormalClosure, "client closed")
+				return nil
+			} else if err != nil {
+				return err
+			}
+		}
+
+		readCtx, cancelRead, err := c.readContext(ctx, coreServerTimeout)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errConnectionLost, err)
+		}
+		_, pending, err = conn.Read(readCtx)
+		cancelRead()
+		if err != nil {
+			switch websocket.CloseStatus(err) {
+			case websocket.StatusNormalClosure:
+				return nil
+			case websocket.StatusProtocolError, websocket.StatusUnsupportedData, websocket.StatusInvalidFramePayloadData:
+				return fmt.Errorf("%w: %w", ErrProtocol, err)
+			}
+			if ctx.Err() != nil {
+				conn.Close(websocket.StatusNormalClosure, "client closed")
+				return nil
+			}
+			return fmt.Errorf("%w: %w", errConnectionLost, err)
+		}
+	}
+}
+
+// negotiateCore calls the SignalR Core negotiate endpoint of the F1 LiveTiming API, retrieving the
+// connection token required to open the websocket connection.
+func (c *Client) negotiateCore(ctx context.Context) error {
+	u, err := url.Parse(c.httpBaseURL)
+	if err != nil {
+		return fmt.Errorf("invalid HTTPBaseURL: %w", err)
+	}
+	negotiateURL := &url.URL{
+		Scheme:   u.Scheme,
+		Host:     u.Host,
+		Path:     corePath + "/negotiate",
+		RawQuery: url.Values{"negotiateVersion": {"1"}}.Encode(),
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, negotiateURL.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending f1 livetiming api negotiation request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %w", errors.New(resp.Status))
+	}
+
+	var n coreNegotiateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&n); err != nil {
+		return fmt.Errorf("%w: error parsing negotiate response: %w", ErrProtocol, err)
+	}
+	// the connection id doubles as the token before version 1 of the negotiate protocol
+	c.connectionToken = cmp.Or(n.ConnectionToken, n.ConnectionID)
+	if c.connectionToken == "" {
+		return fmt.Errorf("%w: missing connection token", ErrProtocol)
+	}
+	c.cookie = resp.Header.Get("set-cookie")
+	c.logger.Debug("successfully negotiated signalr core connection", "token_length", len(c.connectionToken))
+	return nil
+}
+
+// coreURL returns the URL of the SignalR Core websocket endpoint for the negotiated connection.
+func (c *Client) coreURL() (*url.URL, error) {
+	u, err := url.Parse(c.wsBaseURL)
+	if err != nil {
+		return u, fmt.Errorf("invalid WSBaseURL: %w", err)
+	}
+	return &url.URL{
+		Scheme:   u.Scheme,
+		Host:     u.Host,
+		Path:     corePath,
+		RawQuery: url.Values{"id": {c.connectionToken}}.Encode(),
+	}, nil
+}
+
+// handshakeCore selects the JSON hub protocol for the connection, returning whatever the server
+// sent after the handshake response in the same frame.
+func (c *Client) handshakeCore(ctx context.Context, conn *websocket.Conn) ([]byte, error) {
+	req := append([]byte(`{"protocol":"json","version":1}`), coreRecordSeparator)
+	if err := conn.Write(ctx, websocket.MessageText, req); err != nil {
+		return nil, fmt.Errorf("%w: error sending handshake: %w", ErrDial, err)
+	}
+	readCtx, cancel := context.WithTimeout(ctx, coreServerTimeout)
+	defer cancel()
+	_, msg, err := conn.Read(readCtx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error reading handshake response: %w", ErrDial, err)
+	}
+
+	resp, rest, ok := bytes.Cut(msg, []byte{coreRecordSeparator})
+	if !ok {
+		return nil, fmt.Errorf("%w: unterminated handshake response", ErrProtocol)
+	}
+	var hs struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(resp, &hs); err != nil {
+		return nil, fmt.Errorf("%w: invalid handshake response: %w", ErrProtocol, err)
+	}
+	if hs.Error != "" {
+		return nil, fmt.Errorf("%w: handshake rejected: %s", ErrProtocol, hs.Error)
+	}
+	return rest, nil
+}
+
+// sendCoreSubscribeMsg invokes Subscribe on the hub with the data feeds to receive; the reference
+// message is the result of the invocation.
+func (*Client) sendCoreSubscribeMsg(ctx context.Context, conn *websocket.Conn) error {
+	topics, err := json.Marshal(subscribeTopics)
+	if err != nil {
+		return err
+	}
+	msg, err := json.Marshal(coreMessage{
+		Type:         coreMessageInvocation,
+		InvocationID: subscribeInvocationID,
+		Target:       "Subscribe",
+		Arguments:    []json.RawMessage{topics},
+	})
+	if err != nil {
+		return err
+	}
+	return conn.Write(ctx, websocket.MessageText, append(msg, coreRecordSeparator))
+}
+
+// pingCore pings the server until the context is cancelled so it doesn't consider the connection
+// lost while the client is only receiving.
+func (c *Client) pingCore(ctx context.Context, conn *websocket.Conn) {
+	ping := append([]byte(`{"type":6}`), coreRecordSeparator)
+	ticker := time.NewTicker(corePingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := conn.Write(ctx, websocket.MessageText, ping); err != nil {
+				c.logger.Debug("error pinging signalr core server", "err", err)
+				return
+			}
+		}
+	}
+}
+
+// processCoreMessage handles a single SignalR Core hub protocol message. Feed invocations and the
+// result of Subscribe are translated into the equivalent legacy SignalR messages before they are
+// handled, so the raw message handler, e.g. a recording or relay, sees the same feed regardless of
+// the transport. errCoreClosed is returned once the server closes the connection without error, and
+// errConnectionLost if it closes it with an error but allows the client to reconnect.
+func (c *Client) processCoreMessage(record []byte) error {
+	var m coreMessage
+	if err := json.Unmarshal(record, &m); err != nil {
+		return fmt.Errorf("%w: unknown message format: %w", ErrProtocol, err)
+	}
+
+	switch m.Type {
+	case coreMessageInvocation:
+		if m.Target != "feed" {
+			c.logger.Debug("ignoring signalr core invocation", "target", m.Target)
+			return nil
+		}
+		msg, err := json.Marshal(legacyMessage{Changes: []legacyFeedMessage{{"Streaming", "feed", m.Arguments}}})
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrProtocol, err)
+		}
+		return c.handleMessage(msg)
+	case coreMessageCompletion:
+		if m.Error != "" {
+			if m.InvocationID == subscribeInvocationID {
+				return fmt.Errorf("%w: %s", ErrSubscribe, m.Error)
+			}
+			return fmt.Errorf("%w: invocation %s failed: %s", ErrProtocol, m.InvocationID, m.Error)
+		}
+		if len(m.Result) == 0 {
+			return nil
+		}
+		msg, err := json.Marshal(legacyMessage{Reference: m.Result, InvocationID: m.InvocationID})
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrProtocol, err)
+		}
+		return c.handleMessage(msg)
+	case coreMessagePing:
+		return nil
+	case coreMessageClose:
+		switch {
+		case m.Error == "":
+			return errCoreClosed
+		case m.AllowReconnect:
+			return fmt.Errorf("%w: %s", errConnectionLost, m.Error)
+		default:
+			return fmt.Errorf("%w: %s", ErrUpstreamClosed, m.Error)
+		}
+	default:
+		c.logger.Debug("ignoring signalr core message", "type", m.Type)
+		return nil
+	}
+}
+
+/* Private types
+------------------------------------------------------------------------------------------------- */
+
+// coreNegotiateResponse is the response of the SignalR Core negotiate endpoint.
+type coreNegotiateResponse struct {
+	NegotiateVersion int    `json:"negotiateVersion"`
+	ConnectionID     string `json:"connectionId"`
+	ConnectionToken  string `json:"connectionToken"`
+}
+
+// coreMessage is a message of the SignalR Core JSON hub protocol; the fields used depend on its
+// type.
+type coreMessage struct {
+	Type           int               `json:"type"`
+	InvocationID   string            `json:"invocationId,omitempty"`
+	Target         string            `json:"target,omitempty"`
+	Arguments      []json.RawMessage `json:"arguments,omitempty"`
+	Result         json.RawMessage   `json:"result,omitempty"`
+	Error          string            `json:"error,omitempty"`
+	AllowReconnect bool              `json:"allowReconnect,omitempty"`
+}
+
+// legacyMessage is a message as sent by the legacy SignalR endpoint, carrying either feed
+// invocations or the result of an invocation.
+type legacyMessage struct {
+	Changes      []legacyFeedMessage `json:"M,omitempty"`
+	Reference    json.RawMessage     `json:"R,omitempty"`
+	InvocationID string              `json:"I,omitempty"`
+}
+
+// legacyFeedMessage is a feed invocation as sent by the legacy SignalR endpoint.
+type legacyFeedMessage struct {
+	Hub       string            `json:"H"`
+	Method    string            `json:"M"`
+	Arguments []json.RawMessage `json:"A"`
+}
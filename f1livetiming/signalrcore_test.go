@@ -0,0 +1,235 @@
+package f1livetiming
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+func TestSignalRCore(t *testing.T) {
+	td := testdataDir()
+	ref, _ := os.ReadFile(path.Join(td, "ref-msg-race.json"))
+	timing, _ := os.ReadFile(path.Join(td, "ch-msg-race-timingdata.json"))
+
+	t.Run("Feed", func(t *testing.T) {
+		srv := newFakeSignalRCore(t, func(ctx context.Context, conn *websocket.Conn) {
+			id := readCoreSubscribe(ctx, t, conn)
+			// several messages may share a frame
+			sendCoreMessages(ctx, t, conn,
+				coreCompletion(t, id, ref),
+				`{"type":6}`,
+			)
+			sendCoreMessages(ctx, t, conn, append(coreFeed(t, timing), `{"type":7}`)...)
+			conn.Read(ctx)
+		})
+		var raw [][]byte
+		c := listenToFakeCore(t, srv, WithRawMessageHandler(func(msg []byte) { raw = append(raw, msg) }))
+
+		if err := <-c.Done(); err != nil {
+			t.Fatalf("expected no error but found '%s'", err)
+		}
+		drivers := c.Snapshot().Drivers
+		if len(drivers) != 20 {
+			t.Errorf("expected %d drivers but found %d", 20, len(drivers))
+		}
+		if p := drivers["23"].TimingData.Position; p != 16 {
+			t.Errorf("expected position %d but found %d", 16, p)
+		}
+		// the raw messages are legacy SignalR messages, e.g. so they can be recorded and replayed
+		replayed := New(WithLogger(testLogger(t)))
+		for _, msg := range raw {
+			if err := replayed.processMessage(msg); err != nil {
+				t.Errorf("expected no error replaying '%s' but found '%s'", msg, err)
+			}
+		}
+		if p := replayed.Snapshot().Drivers["23"].TimingData.Position; p != 16 {
+			t.Errorf("expected replayed position %d but found %d", 16, p)
+		}
+	})
+
+	t.Run("HandshakeError", func(t *testing.T) {
+		srv := newFakeSignalRCoreHandshake(t, `{"error":"unsupported protocol"}`, nil)
+		c := listenToFakeCore(t, srv)
+
+		if err := <-c.Done(); !errors.Is(err, ErrProtocol) {
+			t.Errorf("expected error '%s' but found '%v'", ErrProtocol, err)
+		}
+	})
+
+	t.Run("SubscribeError", func(t *testing.T) {
+		srv := newFakeSignalRCore(t, func(ctx context.Context, conn *websocket.Conn) {
+			id := readCoreSubscribe(ctx, t, conn)
+			sendCoreMessages(ctx, t, conn, `{"type":3,"invocationId":"`+id+`","error":"unknown topic"}`)
+			conn.Read(ctx)
+		})
+		c := listenToFakeCore(t, srv)
+
+		if err := <-c.Done(); !errors.Is(err, ErrSubscribe) {
+			t.Errorf("expected error '%s' but found '%v'", ErrSubscribe, err)
+		}
+	})
+
+	t.Run("Reconnect", func(t *testing.T) {
+		var connections atomic.Int32
+		srv := newFakeSignalRCore(t, func(ctx context.Context, conn *websocket.Conn) {
+			id := readCoreSubscribe(ctx, t, conn)
+			if connections.Add(1) == 1 {
+				sendCoreMessages(ctx, t, conn, coreCompletion(t, id, ref), `{"type":7,"error":"server restarting","allowReconnect":true}`)
+				conn.Read(ctx)
+				return
+			}
+			sendCoreMessages(ctx, t, conn, coreCompletion(t, id, ref))
+			sendCoreMessages(ctx, t, conn, append(coreFeed(t, timing), `{"type":7}`)...)
+			conn.Read(ctx)
+		})
+		c := listenToFakeCore(t, srv)
+
+		if err := <-c.Done(); err != nil {
+			t.Fatalf("expected no error but found '%s'", err)
+		}
+		if n := connections.Load(); n != 2 {
+			t.Errorf("expected %d connections but found %d", 2, n)
+		}
+		if p := c.Snapshot().Drivers["23"].TimingData.Position; p != 16 {
+			t.Errorf("expected position %d but found %d", 16, p)
+		}
+	})
+
+	t.Run("CloseError", func(t *testing.T) {
+		srv := newFakeSignalRCore(t, func(ctx context.Context, conn *websocket.Conn) {
+			readCoreSubscribe(ctx, t, conn)
+			sendCoreMessages(ctx, t, conn, `{"type":7,"error":"server shutting down"}`)
+			conn.Read(ctx)
+		})
+		c := listenToFakeCore(t, srv)
+
+		if err := <-c.Done(); !errors.Is(err, ErrUpstreamClosed) {
+			t.Errorf("expected error '%s' but found '%v'", ErrUpstreamClosed, err)
+		}
+	})
+}
+
+// newFakeSignalRCore returns a server mimicking the SignalR Core endpoint of the F1 LiveTiming API,
+// serving connections with the given handler once the handshake succeeded.
+func newFakeSignalRCore(t *testing.T, serve func(ctx context.Context, conn *websocket.Conn)) *httptest.Server {
+	t.Helper()
+	return newFakeSignalRCoreHandshake(t, `{}`, serve)
+}
+
+// newFakeSignalRCoreHandshake returns a server mimicking the SignalR Core endpoint of the F1
+// LiveTiming API, answering the handshake with the given response and serving connections with the
+// given handler if it is not nil.
+func newFakeSignalRCoreHandshake(t *testing.T, handshake string, serve func(ctx context.Context, conn *websocket.Conn)) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/signalrcore/negotiate", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("negotiateVersion") != "1" {
+			t.Errorf("expected negotiate version %d but found '%s'", 1, r.URL.Query().Get("negotiateVersion"))
+		}
+		json.NewEncoder(w).Encode(coreNegotiateResponse{NegotiateVersion: 1, ConnectionID: "id", ConnectionToken: "token"})
+	})
+	mux.HandleFunc("/signalrcore", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("id") != "token" {
+			t.Errorf("expected connection token '%s' but found '%s'", "token", r.URL.Query().Get("id"))
+		}
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+		conn.SetReadLimit(-1)
+
+		_, msg, err := conn.Read(r.Context())
+		if err != nil || !bytes.Contains(msg, []byte(`"protocol":"json"`)) {
+			t.Errorf("expected the json protocol handshake but found '%s'", msg)
+			return
+		}
+		sendCoreMessages(r.Context(), t, conn, handshake)
+		if serve != nil {
+			serve(r.Context(), conn)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// listenToFakeCore returns a client listening to the fake SignalR Core server until the test ends.
+func listenToFakeCore(t *testing.T, srv *httptest.Server, opts ...ClientOption) *Client {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	c := New(append([]ClientOption{
+		WithLogger(testLogger(t)),
+		WithTransport(TransportSignalRCore),
+		WithHTTPBaseURL(srv.URL),
+		WithWSBaseURL(strings.Replace(srv.URL, "http://", "ws://", 1)),
+	}, opts...)...)
+	go c.Listen(ctx)
+	return c
+}
+
+// readCoreSubscribe reads the Subscribe invocation sent by the client, returning its id.
+func readCoreSubscribe(ctx context.Context, t *testing.T, conn *websocket.Conn) string {
+	t.Helper()
+	_, msg, err := conn.Read(ctx)
+	if err != nil {
+		t.Errorf("expected the subscribe message but found '%s'", err)
+		return ""
+	}
+	var m coreMessage
+	if err := json.Unmarshal(bytes.TrimSuffix(msg, []byte{coreRecordSeparator}), &m); err != nil || m.Target != "Subscribe" {
+		t.Errorf("expected the subscribe message but found '%s'", msg)
+	}
+	return m.InvocationID
+}
+
+// sendCoreMessages writes the messages to the connection in a single frame.
+func sendCoreMessages(ctx context.Context, t *testing.T, conn *websocket.Conn, msgs ...string) {
+	t.Helper()
+	var frame []byte
+	for _, m := range msgs {
+		frame = append(append(frame, m...), coreRecordSeparator)
+	}
+	if err := conn.Write(ctx, websocket.MessageText, frame); err != nil {
+		t.Errorf("error writing frame: %s", err)
+	}
+}
+
+// coreCompletion returns the completion of the invocation with the given id carrying the reference
+// state of the legacy reference message.
+func coreCompletion(t *testing.T, id string, ref []byte) string {
+	t.Helper()
+	var msg f1Message
+	if err := json.Unmarshal(ref, &msg); err != nil {
+		t.Fatalf("invalid reference message: %s", err)
+	}
+	b, _ := json.Marshal(coreMessage{Type: coreMessageCompletion, InvocationID: id, Result: msg.Reference})
+	return string(b)
+}
+
+// coreFeed returns a feed invocation for each change of the legacy change message.
+func coreFeed(t *testing.T, change []byte) []string {
+	t.Helper()
+	var msg legacyMessage
+	if err := json.Unmarshal(change, &msg); err != nil {
+		t.Fatalf("invalid change message: %s", err)
+	}
+	feed := make([]string, 0, len(msg.Changes))
+	for _, m := range msg.Changes {
+		b, _ := json.Marshal(coreMessage{Type: coreMessageInvocation, Target: "feed", Arguments: m.Arguments})
+		feed = append(feed, string(b))
+	}
+	return feed
+}
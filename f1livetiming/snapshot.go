@@ -0,0 +1,46 @@
+package f1livetiming
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/bcdxn/f1cli/domain"
+)
+
+// Snapshot is the state of the session once the latest message from the F1 LiveTiming API was
+// processed. Snapshots are shared between callers and must not be modified.
+type Snapshot struct {
+	Meeting      domain.Meeting           `json:"meeting"`
+	Drivers      map[string]domain.Driver `json:"drivers"`
+	RaceCtrlMsgs []domain.RaceCtrlMsg     `json:"race_ctrl_msgs"` // RaceCtrlMsgs are every race control message of the session, oldest first
+}
+
+// Snapshot returns the current state of the session without consuming the updates written to the
+// client channels; it is safe to call from any goroutine while the client is running.
+func (c *Client) Snapshot() Snapshot {
+	return *c.snapshot.Load()
+}
+
+// Driver returns the current state of the driver with the given racing number; false is returned if
+// the driver isn't part of the session. It is safe to call from any goroutine while the client is
+// running.
+func (c *Client) Driver(number string) (domain.Driver, bool) {
+	d, ok := c.snapshot.Load().Drivers[number]
+	return d, ok
+}
+
+// publishSnapshot replaces the snapshot returned by Snapshot with the current state of the session.
+// The drivers of the previous snapshot are reused unless they were updated, and the race control
+// messages are only ever appended to, so they are shared with the client state.
+func (c *Client) publishSnapshot(driversUpdated bool) {
+	drivers := c.snapshot.Load().Drivers
+	if driversUpdated {
+		drivers = maps.Clone(c.drivers)
+	}
+	c.snapshot.Store(&Snapshot{
+		Meeting: c.meeting,
+		Drivers: drivers,
+		// clipped so callers appending to the messages can't overwrite later messages
+		RaceCtrlMsgs: slices.Clip(c.raceCtrlMsgs),
+	})
+}
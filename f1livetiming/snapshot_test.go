@@ -0,0 +1,67 @@
+package f1livetiming
+
+import (
+	"os"
+	"path"
+	"sync"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	td := testdataDir()
+
+	t.Run("Reference", func(t *testing.T) {
+		ref, _ := os.ReadFile(path.Join(td, "ref-msg-race.json"))
+		c := New(WithLogger(testLogger(t)))
+		c.processMessage(ref)
+
+		// the snapshot is available without reading the channels
+		snapshot := c.Snapshot()
+		if len(snapshot.Drivers) != 20 {
+			t.Errorf("expected %d drivers but found %d", 20, len(snapshot.Drivers))
+		}
+		if snapshot.Meeting.Session.TotalLaps != 58 {
+			t.Errorf("expected total laps %d but found %d", 58, snapshot.Meeting.Session.TotalLaps)
+		}
+		if len(snapshot.RaceCtrlMsgs) < 2 {
+			t.Fatalf("expected the race control history but found %d messages", len(snapshot.RaceCtrlMsgs))
+		}
+		if latest := snapshot.RaceCtrlMsgs[len(snapshot.RaceCtrlMsgs)-1]; latest != <-c.RaceCtrlMsgs() {
+			t.Errorf("expected the latest race control message last but found '%s'", latest.Body)
+		}
+		driver, ok := c.Driver("1")
+		if !ok || driver.Name != "Max Verstappen" {
+			t.Errorf("expected name '%s' but found '%s'", "Max Verstappen", driver.Name)
+		}
+		if _, ok := c.Driver("99"); ok {
+			t.Errorf("expected driver '%s' not to be found", "99")
+		}
+	})
+
+	t.Run("ConcurrentCallers", func(t *testing.T) {
+		ref, _ := os.ReadFile(path.Join(td, "ref-msg-race.json"))
+		change, _ := os.ReadFile(path.Join(td, "ch-msg-race-timingdata.json"))
+		c := New(WithLogger(testLogger(t)))
+		c.processMessage(ref)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					for _, d := range c.Snapshot().Drivers {
+						_ = d.TimingData.Position
+					}
+					c.Driver("61")
+				}
+			}()
+		}
+		c.processMessage(change)
+		wg.Wait()
+
+		if driver, _ := c.Driver("61"); driver.TimingData.Position != 18 {
+			t.Errorf("expected position %d but found %d", 18, driver.TimingData.Position)
+		}
+	})
+}
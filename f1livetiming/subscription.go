@@ -0,0 +1,235 @@
+package f1livetiming
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/bcdxn/f1cli/domain"
+)
+
+// Topic is a kind of update delivered to subscriptions.
+type Topic string
+
+const (
+	TopicMeeting      Topic = "meeting"      // TopicMeeting delivers snapshots of the meeting
+	TopicDrivers      Topic = "drivers"      // TopicDrivers delivers snapshots of the drivers
+	TopicRaceCtrlMsgs Topic = "race_control" // TopicRaceCtrlMsgs delivers race control messages
+	TopicEvents       Topic = "events"       // TopicEvents delivers the events derived from the updates
+)
+
+// Subscription receives the updates of the client independently of any other subscription, each
+// with its own buffers, so several consumers in one process can observe every update. Like the
+// channels of the client, snapshots are delivered latest-value-wins and race control messages and
+// events are dropped once the buffers are full; the client never waits for subscribers.
+type Subscription struct {
+	client *Client
+	// filters; every update is delivered if nil
+	topics  map[Topic]bool
+	drivers map[string]bool
+	// channels
+	bufferSize    int
+	driversCh     chan map[string]domain.Driver
+	meetingCh     chan domain.Meeting
+	raceCtrlMsgCh chan domain.RaceCtrlMsg
+	eventsCh      chan domain.Event
+	// delivery of updates to the subscriber
+	delivery *deliveryCounters
+}
+
+/* Subscription Optional Functional Parameters
+------------------------------------------------------------------------------------------------- */
+
+type SubscriptionOption = func(s *Subscription)
+
+// WithTopics configures the kinds of updates delivered to the subscription; every topic is
+// delivered by default.
+func WithTopics(topics ...Topic) SubscriptionOption {
+	return func(s *Subscription) {
+		s.topics = make(map[Topic]bool, len(topics))
+		for _, t := range topics {
+			s.topics[t] = true
+		}
+	}
+}
+
+// WithDriverNumbers configures the racing numbers of the drivers delivered to the subscription;
+// the drivers snapshots only contain the given drivers and events of other drivers are skipped.
+// Every driver is delivered by default.
+func WithDriverNumbers(numbers ...string) SubscriptionOption {
+	return func(s *Subscription) {
+		s.drivers = make(map[string]bool, len(numbers))
+		for _, n := range numbers {
+			s.drivers[n] = true
+		}
+	}
+}
+
+// WithSubscriptionBufferSize configures the number of race control messages and events buffered
+// for the subscription; the buffer size of the client is used by default.
+func WithSubscriptionBufferSize(size int) SubscriptionOption {
+	return func(s *Subscription) { s.bufferSize = size }
+}
+
+/* Subscription API
+------------------------------------------------------------------------------------------------- */
+
+// Subscribe returns a new subscription to the updates of the client configured by the given
+// options. Updates are delivered from the next message processed; Snapshot returns the current state
+// of the session. The subscription must be closed once it is no longer read.
+func (c *Client) Subscribe(opts ...SubscriptionOption) *Subscription {
+	s := newSubscription(c, opts...)
+	c.subsMu.Lock()
+	defer c.subsMu.Unlock()
+	c.subs[s] = struct{}{}
+	return s
+}
+
+// Drivers exposes the drivers channel of the subscription as read-only; see Client.Drivers.
+func (s *Subscription) Drivers() <-chan map[string]domain.Driver {
+	return s.driversCh
+}
+
+// Meeting exposes the meeting channel of the subscription as read-only; see Client.Meeting.
+func (s *Subscription) Meeting() <-chan domain.Meeting {
+	return s.meetingCh
+}
+
+// RaceCtrlMsgs exposes the race control messages channel of the subscription as read-only; see
+// Client.RaceCtrlMsgs.
+func (s *Subscription) RaceCtrlMsgs() <-chan domain.RaceCtrlMsg {
+	return s.raceCtrlMsgCh
+}
+
+// Events exposes the events channel of the subscription as read-only; see Client.Events.
+func (s *Subscription) Events() <-chan domain.Event {
+	return s.eventsCh
+}
+
+// DeliveryStats returns the number of updates that weren't delivered to the subscription because it
+// wasn't read fast enough.
+func (s *Subscription) DeliveryStats() DeliveryStats {
+	return s.delivery.stats()
+}
+
+// Close stops the delivery of updates to the subscription and closes its channels; updates still
+// buffered can be read until the channels are drained. Closing a subscription more than once has no
+// effect.
+func (s *Subscription) Close() {
+	c := s.client
+	c.subsMu.Lock()
+	defer c.subsMu.Unlock()
+	if _, ok := c.subs[s]; !ok {
+		return
+	}
+	delete(c.subs, s)
+	close(s.driversCh)
+	close(s.meetingCh)
+	close(s.raceCtrlMsgCh)
+	close(s.eventsCh)
+}
+
+/* Private Helper Functions
+------------------------------------------------------------------------------------------------- */
+
+// newSubscription returns a subscription to the client configured by the given options.
+func newSubscription(c *Client, opts ...SubscriptionOption) *Subscription {
+	s := &Subscription{
+		client:     c,
+		bufferSize: c.bufferSize,
+		delivery:   &deliveryCounters{},
+	}
+	// apply given options
+	for _, opt := range opts {
+		opt(s)
+	}
+	// the buffers are sized by the options
+	s.driversCh = make(chan map[string]domain.Driver, 1)
+	s.meetingCh = make(chan domain.Meeting, 1)
+	s.raceCtrlMsgCh = make(chan domain.RaceCtrlMsg, s.bufferSize)
+	s.eventsCh = make(chan domain.Event, s.bufferSize)
+	return s
+}
+
+// wants indicates that updates of the topic are delivered to the subscription.
+func (s *Subscription) wants(t Topic) bool {
+	return s.topics == nil || s.topics[t]
+}
+
+// wantsDriver indicates that updates of the driver are delivered to the subscription.
+func (s *Subscription) wantsDriver(number string) bool {
+	return s.drivers == nil || s.drivers[number]
+}
+
+// sendMeeting writes the meeting to the subscription, replacing an unread meeting.
+func (s *Subscription) sendMeeting(m domain.Meeting) {
+	if s.wants(TopicMeeting) && sendLatest(s.meetingCh, m) {
+		s.delivery.snapshotsReplaced.Add(1)
+	}
+}
+
+// sendDrivers writes a copy of the drivers of the subscription to it, replacing an unread
+// snapshot. The drivers themselves are never modified once stored in the client state, so only the
+// map is copied.
+func (s *Subscription) sendDrivers(drivers map[string]domain.Driver) {
+	if !s.wants(TopicDrivers) {
+		return
+	}
+	cpy := maps.Clone(drivers)
+	if s.drivers != nil {
+		maps.DeleteFunc(cpy, func(number string, _ domain.Driver) bool { return !s.drivers[number] })
+	}
+	if sendLatest(s.driversCh, cpy) {
+		s.delivery.snapshotsReplaced.Add(1)
+	}
+}
+
+// sendRaceCtrlMsg writes the race control message to the subscription; the message is dropped if
+// the buffer is full.
+func (s *Subscription) sendRaceCtrlMsg(msg domain.RaceCtrlMsg) {
+	if !s.wants(TopicRaceCtrlMsgs) {
+		return
+	}
+	if !sendOrDrop(s.raceCtrlMsgCh, msg) {
+		s.delivery.raceCtrlMsgsDropped.Add(1)
+		s.client.logger.Debug("race control channel full; dropping message", "msg", msg.Body)
+	}
+}
+
+// sendEvent writes the event to the subscription; the event is dropped if the buffer is full.
+func (s *Subscription) sendEvent(e domain.Event) {
+	if !s.wants(TopicEvents) {
+		return
+	}
+	if number, ok := eventDriver(e); ok && !s.wantsDriver(number) {
+		return
+	}
+	if !sendOrDrop(s.eventsCh, e) {
+		s.delivery.eventsDropped.Add(1)
+		s.client.logger.Debug("events channel full; dropping event", "event", fmt.Sprintf("%T", e))
+	}
+}
+
+// eventDriver returns the racing number of the driver an event is about; false is returned for
+// events about the session.
+func eventDriver(e domain.Event) (string, bool) {
+	switch e := e.(type) {
+	case domain.LapCompleted:
+		return e.Driver, true
+	case domain.PositionChanged:
+		return e.Driver, true
+	case domain.PitEntry:
+		return e.Driver, true
+	case domain.PitExit:
+		return e.Driver, true
+	case domain.TyreChanged:
+		return e.Driver, true
+	case domain.FastestLap:
+		return e.Driver, true
+	case domain.DriverRetired:
+		return e.Driver, true
+	case domain.KnockedOut:
+		return e.Driver, true
+	default:
+		return "", false
+	}
+}
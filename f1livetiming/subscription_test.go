@@ -0,0 +1,74 @@
+package f1livetiming
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/bcdxn/f1cli/domain"
+)
+
+func TestSubscribe(t *testing.T) {
+	td := testdataDir()
+	ref, _ := os.ReadFile(path.Join(td, "ref-msg-race.json"))
+	change, _ := os.ReadFile(path.Join(td, "ch-msg-race-timingdata.json"))
+
+	t.Run("FanOut", func(t *testing.T) {
+		c := New(WithLogger(testLogger(t)))
+		first, second := c.Subscribe(), c.Subscribe()
+		defer first.Close()
+		defer second.Close()
+		c.processMessage(ref)
+
+		// every subscription and the client channels receive the same update
+		for _, ch := range []<-chan map[string]domain.Driver{first.Drivers(), second.Drivers(), c.Drivers()} {
+			if drivers := <-ch; len(drivers) != 20 {
+				t.Errorf("expected %d drivers but found %d", 20, len(drivers))
+			}
+		}
+		if msg := <-second.RaceCtrlMsgs(); msg.Body == "" {
+			t.Errorf("expected the latest race control message")
+		}
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		c := New(WithLogger(testLogger(t)))
+		c.processMessage(ref)
+		sub := c.Subscribe(WithTopics(TopicDrivers, TopicEvents), WithDriverNumbers("61"))
+		defer sub.Close()
+		c.processMessage(change)
+
+		drivers := <-sub.Drivers()
+		if _, ok := drivers["61"]; !ok || len(drivers) != 1 {
+			t.Errorf("expected only driver '%s' but found %d drivers", "61", len(drivers))
+		}
+		if len(sub.Meeting()) != 0 {
+			t.Errorf("expected no meeting for a subscription to the drivers")
+		}
+		if n := len(sub.Events()); n != 1 {
+			t.Fatalf("expected %d event but found %d", 1, n)
+		}
+		if e, ok := (<-sub.Events()).(domain.PositionChanged); !ok || e.Driver != "61" {
+			t.Errorf("expected the position change of driver '%s' but found %+v", "61", e)
+		}
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		c := New(WithLogger(testLogger(t)))
+		sub := c.Subscribe()
+		sub.Close()
+		sub.Close()
+		// updates aren't delivered to closed subscriptions
+		c.processMessage(ref)
+
+		if _, ok := <-sub.Drivers(); ok {
+			t.Errorf("expected the drivers channel to be closed")
+		}
+		if _, ok := <-sub.Events(); ok {
+			t.Errorf("expected the events channel to be closed")
+		}
+		if drivers := <-c.Drivers(); len(drivers) != 20 {
+			t.Errorf("expected %d drivers but found %d", 20, len(drivers))
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package f1livetiming
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/bcdxn/f1cli/domain"
+)
+
+const (
+	// sessionLead is how long before the start of a session the websocket connection is established;
+	// the F1 LiveTiming API starts publishing the session shortly before it starts
+	sessionLead = 10 * time.Minute
+)
+
+// WaitForSession polls the session info published by the F1 LiveTiming API until the upcoming
+// session is about to start, writing the meeting to the meeting channel after each request so the
+// time remaining until the session starts can be shown. Failed requests are retried with
+// exponential backoff. It returns nil once the session is about to start, at which point Listen
+// should be called, or the context error if it is cancelled first.
+func (c *Client) WaitForSession(ctx context.Context) error {
+	backoff := c.minPoll
+	for {
+		delay := c.maxPoll
+		info, err := c.fetchSessionInfo(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			c.logger.Warn("error fetching session info", "err", err, "retry_in", backoff)
+			delay, backoff = backoff, min(c.maxPoll, backoff*2)
+		} else {
+			backoff = c.minPoll
+			c.meeting = domain.NewMeeting()
+			c.updateSessionInfo(info)
+			// the latest session has ended and the next one hasn't been published yet
+			complete := c.meeting.Session.Status == domain.SessionStatusEnded
+
+			c.publishSnapshot(false)
+			c.writeMeetingToChan()
+
+			until := time.Until(c.meeting.Session.StartDate.Add(-sessionLead))
+			if !complete && until <= 0 {
+				c.logger.Info("session about to start", "session", c.meeting.Session.Name, "start", c.meeting.Session.StartDate)
+				return nil
+			}
+			if !complete {
+				delay = max(c.minPoll, min(c.maxPoll, until))
+			}
+			c.logger.Debug("waiting for session", "session", c.meeting.Session.Name, "status", c.meeting.Session.Status, "poll_in", delay)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// fetchSessionInfo requests the info of the latest published session, i.e. the upcoming session,
+// the session currently running, or the last session once it has ended.
+func (c *Client) fetchSessionInfo(ctx context.Context) (sessionInfo, error) {
+	var s sessionInfo
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.httpBaseURL+"/static/SessionInfo.json", nil)
+	if err != nil {
+		return s, fmt.Errorf("invalid HTTPBaseURL: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return s, fmt.Errorf("error sending f1 livetiming api session info request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return s, fmt.Errorf("error fetching f1 livetiming api session info: %w", errors.New(resp.Status))
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return s, fmt.Errorf("error reading session info: %w", err)
+	}
+	// the static files of the F1 LiveTiming API start with a byte order mark
+	b = bytes.TrimPrefix(b, []byte("\xef\xbb\xbf"))
+	if err := json.Unmarshal(b, &s); err != nil {
+		return s, fmt.Errorf("error parsing session info: %w", err)
+	}
+	return s, nil
+}
@@ -0,0 +1,114 @@
+package f1livetiming
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bcdxn/f1cli/domain"
+)
+
+func TestWaitForSession(t *testing.T) {
+	t.Run("Start", func(t *testing.T) {
+		soon := time.Now().UTC().Add(sessionLead / 2)
+		responses := []string{
+			"",
+			sessionInfoJSON(time.Now().UTC().Add(-48*time.Hour), "Complete"),
+			sessionInfoJSON(time.Now().UTC().Add(48*time.Hour), "Generating"),
+			sessionInfoJSON(soon, "Generating"),
+		}
+		srv := sessionInfoServer(t, responses)
+		defer srv.Close()
+
+		c := New(WithLogger(testLogger(t)), WithHTTPBaseURL(srv.URL), WithPollInterval(time.Millisecond, 10*time.Millisecond))
+		meetingCh := c.Meeting()
+		errCh := make(chan error, 1)
+		go func() { errCh <- c.WaitForSession(context.Background()) }()
+
+		statuses := make([]domain.SessionStatus, 0)
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case m := <-meetingCh:
+				statuses = append(statuses, m.Session.Status)
+				if m.Name != "Abu Dhabi Grand Prix" {
+					t.Errorf("expected meeting name %s but found %s", "Abu Dhabi Grand Prix", m.Name)
+				}
+				continue
+			case err := <-errCh:
+				if err != nil {
+					t.Errorf("expected no error once the session is about to start but found %s", err)
+				}
+			case <-timeout:
+				t.Fatal("expected the session to start but timed out")
+			}
+			break
+		}
+
+		expected := []domain.SessionStatus{domain.SessionStatusEnded, domain.SessionStatusPending, domain.SessionStatusPending}
+		if fmt.Sprint(statuses) != fmt.Sprint(expected) {
+			t.Errorf("expected session statuses %v but found %v", expected, statuses)
+		}
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		srv := sessionInfoServer(t, []string{sessionInfoJSON(time.Now().UTC().Add(48*time.Hour), "Generating")})
+		defer srv.Close()
+
+		c := New(WithLogger(testLogger(t)), WithHTTPBaseURL(srv.URL), WithPollInterval(time.Millisecond, time.Hour))
+		meetingCh := c.Meeting()
+		ctx, cancel := context.WithCancel(context.Background())
+		errCh := make(chan error, 1)
+		go func() { errCh <- c.WaitForSession(ctx) }()
+
+		m := <-meetingCh
+		if !m.Session.StartDate.After(time.Now()) {
+			t.Errorf("expected the session to start in the future but found %s", m.Session.StartDate)
+		}
+		cancel()
+		if err := <-errCh; !errors.Is(err, context.Canceled) {
+			t.Errorf("expected error %s but found %v", context.Canceled, err)
+		}
+	})
+}
+
+// sessionInfoServer serves the given session info responses in order, repeating the last one; an
+// empty response is served as an internal server error.
+func sessionInfoServer(t *testing.T, responses []string) *httptest.Server {
+	t.Helper()
+	var n atomic.Int32
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/static/SessionInfo.json" {
+			http.NotFound(w, r)
+			return
+		}
+		i := min(int(n.Add(1))-1, len(responses)-1)
+		if responses[i] == "" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		// the F1 LiveTiming API prefixes its static files with a byte order mark
+		fmt.Fprint(w, "\xef\xbb\xbf"+responses[i])
+	}))
+}
+
+// sessionInfoJSON returns the session info of a race starting at the given UTC time at a track 4
+// hours ahead of UTC.
+func sessionInfoJSON(start time.Time, archiveStatus string) string {
+	const layout = "2006-01-02T15:04:05"
+	local := start.In(time.FixedZone("", 4*60*60))
+	return fmt.Sprintf(`{
+		"Meeting": {"Name": "Abu Dhabi Grand Prix", "Location": "Yas Marina", "Number": 24},
+		"ArchiveStatus": {"Status": %q},
+		"Type": "Race",
+		"Name": "Race",
+		"StartDate": %q,
+		"EndDate": %q,
+		"GMTOffset": "04:00:00"
+	}`, archiveStatus, local.Format(layout), local.Add(2*time.Hour).Format(layout))
+}
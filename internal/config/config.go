@@ -0,0 +1,305 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/bcdxn/f1cli/f1livetiming"
+	"github.com/bcdxn/f1cli/internal/logger"
+	"github.com/bcdxn/f1cli/internal/notify"
+	"github.com/bcdxn/f1cli/tui"
+	"github.com/bcdxn/f1cli/tui/styles"
+)
+
+// Config is the user configuration loaded from the TOML config file at startup. Command line flags
+// take precedence over the values configured in the file.
+type Config struct {
+	Theme         string              `toml:"theme"`       // Theme is the name of the built-in theme used to render the TUI
+	PitLoss       float64             `toml:"pit_loss"`    // PitLoss is the time lost making a pit stop in seconds
+	Delay         float64             `toml:"delay"`       // Delay is the broadcast delay of the live timing board in seconds
+	Compact       bool                `toml:"compact"`     // Compact draws the timing table with a single line per driver
+	Favourites    Favourites          `toml:"favourites"`  // Favourites are the drivers and teams highlighted on the timing board
+	Columns       Columns             `toml:"columns"`     // Columns are the visible columns of the timing table in order
+	Keybindings   map[string][]string `toml:"keybindings"` // Keybindings maps TUI actions to the keys that trigger them
+	Notifications Notifications       `toml:"notifications"`
+	Upstream      Upstream            `toml:"upstream"`
+	Log           Log                 `toml:"log"`
+	// resolved state
+	dir   string        // dir is the directory of the config file in which user defined themes are found
+	theme *styles.Theme // theme is the definition of the configured theme
+}
+
+// Favourites are the drivers and teams to follow.
+type Favourites struct {
+	Drivers []string `toml:"drivers"` // Drivers are racing numbers or abbreviations, e.g. "16" or "LEC"
+	Teams   []string `toml:"teams"`   // Teams are team names as shown on the driver detail, e.g. "Ferrari"
+}
+
+// Columns are the visible columns of the timing table for each session type.
+type Columns struct {
+	Race       []string `toml:"race"`
+	Qualifying []string `toml:"qualifying"`
+}
+
+// Notifications configures the notifications fired during the session.
+type Notifications struct {
+	Methods []string `toml:"methods"` // Methods are the terminal notification methods, e.g. "bell"
+	Command string   `toml:"command"` // Command is a shell command run for each notification
+	On      []string `toml:"on"`      // On are the triggers that fire notifications; all when empty
+}
+
+// Upstream configures the F1 LiveTiming API endpoint, e.g. to connect to a relay.
+type Upstream struct {
+	HTTPURL   string `toml:"http_url"`
+	WSURL     string `toml:"ws_url"`
+	Transport string `toml:"transport"` // Transport is the protocol of the endpoint, i.e. "signalr" or "signalrcore"
+}
+
+// Log configures the application log.
+type Log struct {
+	Path       string `toml:"path"`        // Path is the file the log is written to
+	Level      string `toml:"level"`       // Level is the minimum level of the records written, e.g. "debug"
+	Format     string `toml:"format"`      // Format is the encoding of the records, i.e. "text" or "json"
+	MaxSizeMB  int    `toml:"max_size_mb"` // MaxSizeMB is the size the log file may grow to before it is rotated
+	MaxBackups int    `toml:"max_backups"` // MaxBackups is the number of rotated log files kept
+}
+
+// Error is a problem with the config file; Line is 0 if the problem can't be tied to a line.
+type Error struct {
+	Path string
+	Line int
+	Msg  string
+}
+
+func (e Error) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %s", e.Path, e.Msg)
+	}
+	return fmt.Sprintf("%s:%d: %s", e.Path, e.Line, e.Msg)
+}
+
+// DefaultPath returns the path of the config file following the XDG base directory specification,
+// i.e. `$XDG_CONFIG_HOME/f1cli/config.toml` falling back to `~/.config/f1cli/config.toml`.
+func DefaultPath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("error finding config directory: %w", err)
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "f1cli", "config.toml"), nil
+}
+
+// Load reads and validates the config file at the given path. When no path is given the file at the
+// default path is loaded if it exists, otherwise the zero value config is returned.
+func Load(path string) (Config, error) {
+	var cfg Config
+	explicit := path != ""
+	if !explicit {
+		p, err := DefaultPath()
+		if err != nil {
+			return cfg, err
+		}
+		path = p
+	}
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, fs.ErrNotExist) {
+			cfg.dir = filepath.Dir(path)
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("error reading config file: %w", err)
+	}
+	return Parse(path, src)
+}
+
+var (
+	typeErrRe = regexp.MustCompile(`^toml: line (\d+) \(last key "(.*)"\): (.*)$`)
+)
+
+// Parse decodes and validates the contents of a config file; user defined themes are read from the
+// directory of the given path.
+func Parse(path string, src []byte) (Config, error) {
+	cfg := Config{dir: filepath.Dir(path)}
+	md, err := toml.Decode(string(src), &cfg)
+	if err != nil {
+		return cfg, decodeError(path, err)
+	}
+
+	v := validator{path: path, src: src}
+	for _, k := range md.Undecoded() {
+		v.errorf(k, "unknown key '%s'", k)
+	}
+	if cfg.Theme != "" {
+		if c, err := cfg.WithTheme(cfg.Theme); err != nil {
+			var ce Error
+			if errors.As(err, &ce) {
+				// errors within the theme file point at the theme file itself
+				v.errs = append(v.errs, unjoin(err)...)
+			} else {
+				v.errorf(toml.Key{"theme"}, "%s", err)
+			}
+		} else {
+			cfg = c
+		}
+	}
+	if cfg.PitLoss < 0 {
+		v.errorf(toml.Key{"pit_loss"}, "pit_loss must not be negative")
+	}
+	if cfg.Delay < 0 || cfg.Delay > f1livetiming.MaxDelay.Seconds() {
+		v.errorf(toml.Key{"delay"}, "delay must be between 0 and %.0f seconds", f1livetiming.MaxDelay.Seconds())
+	}
+	v.allOf(toml.Key{"columns", "race"}, cfg.Columns.Race, tui.Columns())
+	v.allOf(toml.Key{"columns", "qualifying"}, cfg.Columns.Qualifying, tui.Columns())
+	for action, keys := range cfg.Keybindings {
+		k := toml.Key{"keybindings", action}
+		if !slices.Contains(tui.KeyActions(), action) {
+			v.errorf(k, "unknown action '%s', expected one of: %s", action, strings.Join(tui.KeyActions(), ", "))
+		} else if len(keys) == 0 {
+			v.errorf(k, "action '%s' must be bound to at least one key", action)
+		}
+	}
+	methods := []string{string(notify.MethodBell), string(notify.MethodOSC9), string(notify.MethodOSC777)}
+	v.allOf(toml.Key{"notifications", "methods"}, cfg.Notifications.Methods, methods)
+	triggers := make([]string, 0)
+	for _, t := range notify.Triggers() {
+		triggers = append(triggers, string(t))
+	}
+	v.allOf(toml.Key{"notifications", "on"}, cfg.Notifications.On, triggers)
+	transports := make([]string, 0)
+	for _, t := range f1livetiming.Transports() {
+		transports = append(transports, string(t))
+	}
+	v.oneOf(toml.Key{"upstream", "transport"}, cfg.Upstream.Transport, transports)
+	v.oneOf(toml.Key{"log", "level"}, cfg.Log.Level, logger.Levels())
+	formats := make([]string, 0)
+	for _, f := range logger.Formats() {
+		formats = append(formats, string(f))
+	}
+	v.oneOf(toml.Key{"log", "format"}, cfg.Log.Format, formats)
+	if cfg.Log.MaxSizeMB < 0 {
+		v.errorf(toml.Key{"log", "max_size_mb"}, "max_size_mb must not be negative")
+	}
+	if cfg.Log.MaxBackups < 0 {
+		v.errorf(toml.Key{"log", "max_backups"}, "max_backups must not be negative")
+	}
+
+	return cfg, v.err()
+}
+
+// decodeError converts an error decoding a TOML file into an Error pointing at the offending line.
+func decodeError(path string, err error) error {
+	var pe toml.ParseError
+	if errors.As(err, &pe) {
+		return Error{Path: path, Line: pe.Position.Line, Msg: pe.Message}
+	}
+	// type errors aren't returned as a ParseError but still carry the line of the key
+	if m := typeErrRe.FindStringSubmatch(err.Error()); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return Error{Path: path, Line: line, Msg: fmt.Sprintf("invalid value for %s: %s", m[2], m[3])}
+	}
+	return Error{Path: path, Msg: err.Error()}
+}
+
+// unjoin returns the errors joined by errors.Join, or the error itself if it isn't joined.
+func unjoin(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+/* Validation
+------------------------------------------------------------------------------------------------- */
+
+// validator collects the validation errors of a config file.
+type validator struct {
+	path string
+	src  []byte
+	errs []error
+}
+
+// err returns the collected errors in the order they appear in the file, or nil if there are none.
+func (v *validator) err() error {
+	slices.SortStableFunc(v.errs, func(a, b error) int { return v.errLine(a) - v.errLine(b) })
+	return errors.Join(v.errs...)
+}
+
+// errLine returns the line of an error in the file being validated; errors of other files, i.e.
+// theme files, are ordered first.
+func (v *validator) errLine(err error) int {
+	var ce Error
+	if errors.As(err, &ce) && ce.Path == v.path {
+		return ce.Line
+	}
+	return 0
+}
+
+// errorf records an error for the given key, pointing at the line the key is defined on.
+func (v *validator) errorf(k toml.Key, format string, args ...any) {
+	v.errs = append(v.errs, Error{Path: v.path, Line: keyLine(v.src, k), Msg: fmt.Sprintf(format, args...)})
+}
+
+// oneOf validates that the value of the key is one of the allowed values, if set.
+func (v *validator) oneOf(k toml.Key, value string, allowed []string) {
+	if value != "" && !slices.Contains(allowed, value) {
+		v.errorf(k, "invalid %s '%s', expected one of: %s", k[len(k)-1], value, strings.Join(allowed, ", "))
+	}
+}
+
+// allOf validates that every value of the key is one of the allowed values.
+func (v *validator) allOf(k toml.Key, values []string, allowed []string) {
+	for _, value := range values {
+		v.oneOf(k, value, allowed)
+	}
+}
+
+// keyLine returns the 1-based line number on which the key is defined in the config file, or 0 if
+// it can't be found. Only the subset of TOML used by the config file is recognised, i.e. tables,
+// dotted keys and inline keys.
+func keyLine(src []byte, k toml.Key) int {
+	var table []string
+	for i, line := range strings.Split(string(src), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "["):
+			name := strings.Trim(line[:strings.LastIndex(line, "]")+1], "[]")
+			table = splitKey(name)
+			if slices.Equal(table, k) {
+				return i + 1
+			}
+		default:
+			name, _, ok := strings.Cut(line, "=")
+			if !ok {
+				continue
+			}
+			full := append(slices.Clone(table), splitKey(name)...)
+			if len(full) <= len(k) && slices.Equal(full, k[:len(full)]) {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
+
+// splitKey splits a (dotted) TOML key into its parts, removing whitespace and quotes.
+func splitKey(name string) []string {
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(p), `"'`)
+	}
+	return parts
+}
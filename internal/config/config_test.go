@@ -0,0 +1,210 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		cfg, err := Parse("config.toml", []byte(`
+theme = "default"
+pit_loss = 21.5
+delay = 32.5
+
+[favourites]
+drivers = ["16", "NOR"]
+teams = ["Ferrari"]
+
+[columns]
+race = ["position", "driver", "leader", "pit_rejoin"]
+
+[keybindings]
+strategy = ["t"]
+
+[notifications]
+methods = ["bell", "osc9"]
+on = ["red_flag"]
+
+[upstream]
+http_url = "http://localhost:8081"
+ws_url = "ws://localhost:8081"
+transport = "signalrcore"
+
+[log]
+level = "debug"
+format = "json"
+`))
+		if err != nil {
+			t.Fatalf("expected no error but found '%s'", err)
+		}
+		if cfg.PitLoss != 21.5 {
+			t.Errorf("expected pit loss %.1f but found %.1f", 21.5, cfg.PitLoss)
+		}
+		if cfg.Delay != 32.5 {
+			t.Errorf("expected delay %.1f but found %.1f", 32.5, cfg.Delay)
+		}
+		if len(cfg.Favourites.Drivers) != 2 || cfg.Favourites.Teams[0] != "Ferrari" {
+			t.Errorf("expected favourites to be decoded but found %v", cfg.Favourites)
+		}
+		if strings.Join(cfg.Columns.Race, ",") != "position,driver,leader,pit_rejoin" {
+			t.Errorf("expected race columns to be decoded in order but found %v", cfg.Columns.Race)
+		}
+		if cfg.Keybindings["strategy"][0] != "t" {
+			t.Errorf("expected keybinding '%s' but found %v", "t", cfg.Keybindings["strategy"])
+		}
+		if cfg.Upstream.WSURL != "ws://localhost:8081" {
+			t.Errorf("expected ws url '%s' but found '%s'", "ws://localhost:8081", cfg.Upstream.WSURL)
+		}
+		if cfg.Upstream.Transport != "signalrcore" {
+			t.Errorf("expected transport '%s' but found '%s'", "signalrcore", cfg.Upstream.Transport)
+		}
+		if cfg.Log.Level != "debug" || cfg.Log.Format != "json" {
+			t.Errorf("expected log level '%s' and format '%s' but found %v", "debug", "json", cfg.Log)
+		}
+	})
+
+	t.Run("Syntax", func(t *testing.T) {
+		_, err := Parse("config.toml", []byte("theme = \"default\"\n\npit_loss = 2x\n"))
+		assertLines(t, err, 3)
+	})
+
+	t.Run("Type", func(t *testing.T) {
+		_, err := Parse("config.toml", []byte("theme = \"default\"\npit_loss = \"fast\"\n"))
+		assertLines(t, err, 2)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := Parse("config.toml", []byte(`theme = "default"
+delay = 600
+
+[columns]
+race = ["position", "gearbox"]
+
+[keybindings]
+jump = ["g"]
+
+[notifications]
+methods = ["bell"]
+colour = "red"
+
+[log]
+level = "verbose"
+`))
+		assertLines(t, err, 2, 5, 8, 12, 15)
+		if err != nil && !strings.Contains(err.Error(), "config.toml:5: invalid race 'gearbox'") {
+			t.Errorf("expected invalid column error but found '%s'", err)
+		}
+	})
+}
+
+func TestLoad(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	if _, err := Load(""); err != nil {
+		t.Errorf("expected no error for a missing default config but found '%s'", err)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.toml")); err == nil {
+		t.Errorf("expected error for a missing config given explicitly")
+	}
+
+	path, _ := DefaultPath()
+	os.MkdirAll(filepath.Dir(path), 0o755)
+	os.WriteFile(path, []byte(`theme = "default"`+"\n"+`pit_loss = 20`), 0o644)
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("expected no error but found '%s'", err)
+	}
+	if cfg.PitLoss != 20 {
+		t.Errorf("expected pit loss %.1f but found %.1f", 20.0, cfg.PitLoss)
+	}
+}
+
+// assertLines asserts that err contains a config error on each of the given lines.
+func assertLines(t *testing.T, err error, lines ...int) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("expected errors on lines %v but found none", lines)
+	}
+	var errs []error
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	} else {
+		errs = []error{err}
+	}
+	found := make([]int, 0, len(errs))
+	for _, e := range errs {
+		var ce Error
+		if errors.As(e, &ce) {
+			found = append(found, ce.Line)
+		}
+	}
+	if len(found) != len(lines) {
+		t.Fatalf("expected errors on lines %v but found %v (%s)", lines, found, err)
+	}
+	for i := range lines {
+		if found[i] != lines[i] {
+			t.Errorf("expected errors on lines %v but found %v (%s)", lines, found, err)
+			return
+		}
+	}
+}
+
+func TestTheme(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.toml")
+	os.MkdirAll(filepath.Join(dir, "themes"), 0o755)
+
+	t.Run("Builtin", func(t *testing.T) {
+		cfg, err := Parse(path, []byte(`theme = "colour-blind"`))
+		if err != nil {
+			t.Fatalf("expected no error but found '%s'", err)
+		}
+		if g := cfg.Styles().Glyphs.SectorFastest; g != "█" {
+			t.Errorf("expected fastest sector glyph '%s' but found '%s'", "█", g)
+		}
+	})
+
+	t.Run("UserDefined", func(t *testing.T) {
+		os.WriteFile(ThemePath(dir, "mine"), []byte(`base = "high-contrast"
+[colors]
+fastest = "#AA00AA"
+[glyphs]
+fastest_lap = "F"
+`), 0o644)
+		cfg, err := Parse(path, []byte(`theme = "mine"`))
+		if err != nil {
+			t.Fatalf("expected no error but found '%s'", err)
+		}
+		st := cfg.Styles()
+		if st.Color.Fastest != "#AA00AA" {
+			t.Errorf("expected fastest color '%s' but found '%s'", "#AA00AA", st.Color.Fastest)
+		}
+		if st.Glyphs.FastestLap != "F" {
+			t.Errorf("expected fastest lap glyph '%s' but found '%s'", "F", st.Glyphs.FastestLap)
+		}
+		if st.Color.PersonalBest != "#00FF00" {
+			t.Errorf("expected personal best color of the base theme '%s' but found '%s'", "#00FF00", st.Color.PersonalBest)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		os.WriteFile(ThemePath(dir, "broken"), []byte(`base = "default"
+[colors]
+fastest = "purple"
+mauve = "#FF00FF"
+`), 0o644)
+		_, err := Parse(path, []byte(`theme = "broken"`))
+		assertLines(t, err, 3, 4)
+		if err != nil && !strings.Contains(err.Error(), "broken.toml:3: invalid color 'purple'") {
+			t.Errorf("expected invalid color error in the theme file but found '%s'", err)
+		}
+
+		_, err = Parse(path, []byte("pit_loss = 20\ntheme = \"missing\""))
+		assertLines(t, err, 2)
+	})
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/bcdxn/f1cli/tui/styles"
+)
+
+// themeFile is a user defined theme that overrides the colors and glyphs of a built-in theme.
+type themeFile struct {
+	Base     string            `toml:"base"`     // Base is the built-in theme that is overridden; default when not set
+	Emphasis *bool             `toml:"emphasis"` // Emphasis marks lap and sector states with bold and underline
+	Colors   map[string]string `toml:"colors"`   // Colors are hex colors or ANSI color numbers keyed by color name
+	Glyphs   map[string]string `toml:"glyphs"`   // Glyphs are single characters keyed by glyph name
+}
+
+// Styles returns the styles of the configured theme.
+func (c Config) Styles() *styles.Style {
+	if c.theme == nil {
+		return styles.Default()
+	}
+	return styles.New(*c.theme)
+}
+
+// WithTheme returns a copy of the config using the theme with the given name, which is either a
+// built-in theme or a user defined theme in the `themes` directory next to the config file.
+func (c Config) WithTheme(name string) (Config, error) {
+	t, err := loadTheme(c.dir, name)
+	if err != nil {
+		return c, err
+	}
+	c.Theme = name
+	c.theme = &t
+	return c, nil
+}
+
+// ThemePath returns the path of the user defined theme with the given name.
+func ThemePath(dir, name string) string {
+	return filepath.Join(dir, "themes", name+".toml")
+}
+
+// loadTheme returns the definition of the named built-in theme, or reads it from the user defined
+// theme file in the given config directory.
+func loadTheme(dir, name string) (styles.Theme, error) {
+	if t, ok := styles.Builtin(name); ok {
+		return t, nil
+	}
+	path := ThemePath(dir, name)
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return styles.Theme{}, fmt.Errorf("unknown theme '%s', expected one of: %s or a theme file at %s", name, strings.Join(styles.Themes(), ", "), path)
+	}
+	return parseTheme(path, src)
+}
+
+// parseTheme decodes and validates the contents of a user defined theme file.
+func parseTheme(path string, src []byte) (styles.Theme, error) {
+	var tf themeFile
+	md, err := toml.Decode(string(src), &tf)
+	if err != nil {
+		return styles.Theme{}, decodeError(path, err)
+	}
+
+	v := validator{path: path, src: src}
+	for _, k := range md.Undecoded() {
+		v.errorf(k, "unknown key '%s'", k)
+	}
+	t, ok := styles.Builtin(tf.Base)
+	if !ok {
+		v.errorf(toml.Key{"base"}, "invalid base '%s', expected one of: %s", tf.Base, strings.Join(styles.Themes(), ", "))
+	}
+	if tf.Emphasis != nil {
+		t.Emphasis = *tf.Emphasis
+	}
+	for name, value := range tf.Colors {
+		if !t.SetColor(name, value) {
+			v.errorf(toml.Key{"colors", name}, "unknown color '%s', expected one of: %s", name, strings.Join(styles.ColorNames(), ", "))
+		} else if !isColor(value) {
+			v.errorf(toml.Key{"colors", name}, "invalid color '%s', expected a hex color, e.g. '#DA0ED3', or an ANSI color number", value)
+		}
+	}
+	for name, value := range tf.Glyphs {
+		if !t.SetGlyph(name, value) {
+			v.errorf(toml.Key{"glyphs", name}, "unknown glyph '%s', expected one of: %s", name, strings.Join(styles.GlyphNames(), ", "))
+		} else if len([]rune(value)) != 1 {
+			v.errorf(toml.Key{"glyphs", name}, "invalid glyph '%s', expected a single character", value)
+		}
+	}
+
+	return t, v.err()
+}
+
+// isColor indicates if the value is a hex color (#RGB or #RRGGBB) or an ANSI color number.
+func isColor(value string) bool {
+	if hex, ok := strings.CutPrefix(value, "#"); ok {
+		return (len(hex) == 3 || len(hex) == 6) && strings.Trim(strings.ToLower(hex), "0123456789abcdef") == ""
+	}
+	n, err := strconv.Atoi(value)
+	return err == nil && n >= 0 && n <= 255
+}
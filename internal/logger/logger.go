@@ -1,22 +1,178 @@
 package logger
 
 import (
-	"log"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
-func New() (*slog.Logger, *os.File) {
-	file, err := os.Create("app.log")
+const (
+	// defaultMaxSize is the size in bytes a log file may grow to before it is rotated
+	defaultMaxSize = 10 * 1024 * 1024
+	// defaultMaxBackups is the number of rotated log files kept
+	defaultMaxBackups = 3
+)
+
+// Format is the encoding of the log records.
+type Format string
+
+const (
+	FormatText Format = "text" // FormatText writes records as logfmt style key=value pairs
+	FormatJSON Format = "json" // FormatJSON writes records as newline-delimited JSON objects
+)
+
+// Formats returns every supported log format.
+func Formats() []Format {
+	return []Format{FormatText, FormatJSON}
+}
+
+// Levels returns the names of every supported log level, from most to least verbose.
+func Levels() []string {
+	return []string{"debug", "info", "warn", "error"}
+}
+
+// ParseLevel returns the log level with the given name, e.g. "debug".
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return level, fmt.Errorf("invalid log level '%s', expected one of: %s", name, strings.Join(Levels(), ", "))
+	}
+	return level, nil
+}
+
+// ParseFormat returns the log format with the given name, e.g. "json".
+func ParseFormat(name string) (Format, error) {
+	for _, f := range Formats() {
+		if string(f) == name {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("invalid log format '%s', expected one of: text, json", name)
+}
+
+// DefaultPath returns the path of the log file following the XDG base directory specification,
+// i.e. `$XDG_STATE_HOME/f1cli/f1cli.log` falling back to `~/.local/state/f1cli/f1cli.log`.
+func DefaultPath() (string, error) {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("error finding state directory: %w", err)
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "f1cli", "f1cli.log"), nil
+}
+
+// New returns a logger appending to the configured log file, which is rotated once it grows past
+// its maximum size. Every record carries the ID of the session, i.e. the run of the program, to tell
+// runs apart within the file. The file must be closed by the caller.
+//
+// Logging never prevents the program from running: if the log file can't be opened the returned
+// logger discards every record and the error is returned alongside it so it can be reported.
+func New(opts ...LoggerOption) (*slog.Logger, io.Closer, error) {
+	c := loggerConfig{
+		level:      slog.LevelInfo,
+		format:     FormatText,
+		maxSize:    defaultMaxSize,
+		maxBackups: defaultMaxBackups,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	w, err := openLogFile(c)
 	if err != nil {
-		log.Fatal(err)
+		w = nopCloser{io.Discard}
+		err = fmt.Errorf("error opening log file: %w", err)
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: c.level}
+	var handler slog.Handler = slog.NewTextHandler(w, handlerOpts)
+	if c.format == FormatJSON {
+		handler = slog.NewJSONHandler(w, handlerOpts)
+	}
+
+	return slog.New(handler).With("session_id", newSessionID()), w, err
+}
+
+type LoggerOption = func(c *loggerConfig)
+
+// WithPath configures the file the log is written to; defaults to DefaultPath.
+func WithPath(path string) LoggerOption {
+	return func(c *loggerConfig) { c.path = path }
+}
+
+// WithLevel configures the minimum level of the records written to the log; defaults to info.
+func WithLevel(level slog.Level) LoggerOption {
+	return func(c *loggerConfig) { c.level = level }
+}
+
+// WithFormat configures the encoding of the log records; defaults to text.
+func WithFormat(format Format) LoggerOption {
+	return func(c *loggerConfig) { c.format = format }
+}
+
+// WithMaxSize configures the size in bytes the log file may grow to before it is rotated; a size of
+// 0 disables rotation. Defaults to 10MB.
+func WithMaxSize(bytes int64) LoggerOption {
+	return func(c *loggerConfig) { c.maxSize = bytes }
+}
+
+// WithMaxBackups configures the number of rotated log files kept; defaults to 3.
+func WithMaxBackups(n int) LoggerOption {
+	return func(c *loggerConfig) { c.maxBackups = n }
+}
+
+/* Helpers
+------------------------------------------------------------------------------------------------- */
+
+// openLogFile opens the configured log file for appending, creating its directory if needed.
+func openLogFile(c loggerConfig) (io.WriteCloser, error) {
+	path := c.path
+	if path == "" {
+		p, err := DefaultPath()
+		if err != nil {
+			return nil, err
+		}
+		path = p
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	return openRotatingFile(path, c.maxSize, c.maxBackups)
+}
+
+// newSessionID returns a random ID identifying the current run of the program.
+func newSessionID() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
 	}
+	return hex.EncodeToString(b)
+}
+
+// nopCloser is a writer that doesn't need to be closed.
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
 
-	// Create a text handler that writes to the file
-	handler := slog.NewTextHandler(file, &slog.HandlerOptions{
-		Level: slog.LevelDebug,
-	})
+/* Type Definitions
+------------------------------------------------------------------------------------------------- */
 
-	// Create a logger with the file handler
-	return slog.New(handler), file
+type loggerConfig struct {
+	path       string
+	level      slog.Level
+	format     Format
+	maxSize    int64 // maxSize is the size in bytes the log file may grow to before it is rotated
+	maxBackups int   // maxBackups is the number of rotated log files kept
 }
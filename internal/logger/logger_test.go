@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("JSON", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "nested", "f1cli.log")
+		l, f, err := New(WithPath(path), WithFormat(FormatJSON), WithLevel(slog.LevelWarn))
+		if err != nil {
+			t.Fatalf("expected no error but found %v", err)
+		}
+		l.Info("dropped")
+		l.Warn("kept", "lap", 12)
+		f.Close()
+
+		src, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSpace(string(src)), "\n")
+		if len(lines) != 1 {
+			t.Fatalf("expected %d records but found %d", 1, len(lines))
+		}
+		var record map[string]any
+		if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+			t.Fatalf("expected a JSON record but found %q", lines[0])
+		}
+		if record["msg"] != "kept" {
+			t.Errorf("expected message '%s' but found '%v'", "kept", record["msg"])
+		}
+		if id, _ := record["session_id"].(string); len(id) != 8 {
+			t.Errorf("expected an 8 character session ID but found '%v'", record["session_id"])
+		}
+	})
+
+	t.Run("Append", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "f1cli.log")
+		for _, msg := range []string{"first run", "second run"} {
+			l, f, err := New(WithPath(path))
+			if err != nil {
+				t.Fatalf("expected no error but found %v", err)
+			}
+			l.Info(msg)
+			f.Close()
+		}
+		src, _ := os.ReadFile(path)
+		if !strings.Contains(string(src), "first run") || !strings.Contains(string(src), "second run") {
+			t.Errorf("expected the records of both runs but found %q", src)
+		}
+	})
+
+	t.Run("Fallback", func(t *testing.T) {
+		dir := t.TempDir()
+		// a file where the log directory should be makes the log file impossible to create
+		blocker := filepath.Join(dir, "blocker")
+		os.WriteFile(blocker, nil, 0o644)
+
+		l, f, err := New(WithPath(filepath.Join(blocker, "f1cli.log")))
+		if err == nil {
+			t.Errorf("expected an error opening the log file")
+		}
+		if l == nil || f == nil {
+			t.Fatalf("expected a logger discarding records")
+		}
+		l.Info("discarded")
+		if err := f.Close(); err != nil {
+			t.Errorf("expected no error closing the discarded log but found %v", err)
+		}
+	})
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "f1cli.log")
+	f, err := openRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range []string{"aaaaaa\n", "bbbbbb\n", "cccccc\n", "dddddd\n"} {
+		if _, err := f.Write([]byte(record)); err != nil {
+			t.Fatalf("expected no error but found %v", err)
+		}
+	}
+	f.Close()
+
+	// the oldest record is dropped with the third backup
+	expected := map[string]string{
+		path:                "dddddd\n",
+		backupPath(path, 1): "cccccc\n",
+		backupPath(path, 2): "bbbbbb\n",
+		backupPath(path, 3): "",
+	}
+	for p, content := range expected {
+		src, err := os.ReadFile(p)
+		if content == "" {
+			if !os.IsNotExist(err) {
+				t.Errorf("expected '%s' to be removed but found %q", filepath.Base(p), src)
+			}
+			continue
+		}
+		if string(src) != content {
+			t.Errorf("expected '%s' to contain %q but found %q", filepath.Base(p), content, src)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// rotatingFile is a log file that is rotated once it grows past its maximum size. Rotated files are
+// named after the log file with an increasing suffix, e.g. `f1cli.log.1` is the most recent, and
+// only the configured number of them are kept.
+type rotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64 // size is the current size of the log file in bytes
+}
+
+// openRotatingFile opens the log file at the given path for appending, creating it if needed.
+func openRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	f := &rotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write appends to the log file, rotating it first if the write would grow it past its maximum
+// size; a single record larger than the maximum size is still written whole.
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
+
+// open opens the log file for appending and records its current size.
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// rotate shifts every rotated file up by one suffix, dropping the oldest, moves the log file to the
+// first suffix and opens a new empty log file.
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	for i := f.maxBackups - 1; i > 0; i-- {
+		err := os.Rename(backupPath(f.path, i), backupPath(f.path, i+1))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			// keep logging to the current file rather than losing every following record
+			return errors.Join(err, f.open())
+		}
+	}
+	var err error
+	if f.maxBackups > 0 {
+		err = os.Rename(f.path, backupPath(f.path, 1))
+	} else {
+		err = os.Remove(f.path)
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return errors.Join(err, f.open())
+	}
+	return f.open()
+}
+
+// backupPath returns the path of the nth most recent rotated log file.
+func backupPath(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
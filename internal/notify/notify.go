@@ -0,0 +1,354 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bcdxn/f1cli/domain"
+)
+
+const (
+	// MethodBell rings the terminal bell; tmux and most terminals flag the window that rang it.
+	MethodBell Method = "bell"
+	// MethodOSC9 sends a desktop notification using the OSC 9 escape sequence (iTerm2, Windows
+	// Terminal, WezTerm, kitty, ghostty).
+	MethodOSC9 Method = "osc9"
+	// MethodOSC777 sends a desktop notification using the OSC 777 escape sequence (urxvt, foot,
+	// VTE based terminals).
+	MethodOSC777 Method = "osc777"
+)
+
+// Method is the way a notification is delivered through the terminal.
+type Method string
+
+const (
+	TriggerTrackStatus Trigger = "track_status" // TriggerTrackStatus fires when the flag or safety car status of the track changes
+	TriggerRedFlag     Trigger = "red_flag"     // TriggerRedFlag fires when the session is red flagged
+	TriggerFastestLap  Trigger = "fastest_lap"  // TriggerFastestLap fires when a different driver sets the fastest lap of the session
+	TriggerPit         Trigger = "pit"          // TriggerPit fires when a favourite driver enters the pit
+	TriggerRetirement  Trigger = "retirement"   // TriggerRetirement fires when a driver retires from the session
+	TriggerKnockout    Trigger = "knockout"     // TriggerKnockout fires when drivers are knocked out of qualifying
+)
+
+// Trigger identifies the kind of change in the session that fires a notification.
+type Trigger string
+
+// Triggers returns every supported trigger.
+func Triggers() []Trigger {
+	return []Trigger{TriggerTrackStatus, TriggerRedFlag, TriggerFastestLap, TriggerPit, TriggerRetirement, TriggerKnockout}
+}
+
+// commandTimeout is the maximum time the notification command hook is allowed to run.
+const commandTimeout = 10 * time.Second
+
+// Event is a single notification.
+type Event struct {
+	Trigger Trigger // Trigger is the kind of change that fired the notification
+	Title   string  // Title is the short summary of the notification
+	Body    string  // Body is the detail of the notification
+}
+
+// New returns a new notifier that watches the updates from the F1 LiveTiming API; by default it
+// rings the terminal bell on stderr for every trigger.
+func New(opts ...NotifierOption) *Notifier {
+	n := &Notifier{
+		out:        os.Stderr,
+		methods:    []Method{MethodBell},
+		triggers:   make(map[Trigger]bool),
+		favourites: make(map[string]bool),
+		drivers:    make(map[string]domain.Driver),
+		logger:     slog.Default(),
+	}
+	for _, t := range Triggers() {
+		n.triggers[t] = true
+	}
+	// apply given options
+	for _, opt := range opts {
+		opt(n)
+	}
+	return n
+}
+
+// Notifier compares successive meeting and drivers snapshots and fires a notification for each
+// configured trigger. The first snapshot of each kind only establishes the initial state so that
+// nothing fires for what already happened before connecting.
+type Notifier struct {
+	// configuration
+	out        io.Writer
+	methods    []Method
+	command    string
+	triggers   map[Trigger]bool
+	favourites map[string]bool
+	passthru   bool
+	// previous state
+	mu          sync.Mutex
+	hasMeeting  bool
+	trackStatus domain.TrackStatus
+	fastestLap  string
+	drivers     map[string]domain.Driver
+	hasDrivers  bool
+	// logger
+	logger *slog.Logger
+}
+
+/* Notifier Optional Functional Parameters
+------------------------------------------------------------------------------------------------- */
+
+type NotifierOption = func(n *Notifier)
+
+// WithWriter configures where terminal notifications are written; stderr by default so that they
+// don't interfere with the output of the TUI or the JSON stream.
+func WithWriter(w io.Writer) NotifierOption {
+	return func(n *Notifier) { n.out = w }
+}
+
+// WithMethods configures how notifications are delivered through the terminal; no terminal
+// notifications are sent if no methods are given.
+func WithMethods(methods ...Method) NotifierOption {
+	return func(n *Notifier) { n.methods = methods }
+}
+
+// WithCommand configures a shell command that is run for each notification; the event is passed
+// in the F1_EVENT, F1_TITLE and F1_BODY environment variables.
+func WithCommand(command string) NotifierOption {
+	return func(n *Notifier) { n.command = command }
+}
+
+// WithTriggers configures the kinds of changes that fire notifications.
+func WithTriggers(triggers ...Trigger) NotifierOption {
+	return func(n *Notifier) {
+		n.triggers = make(map[Trigger]bool, len(triggers))
+		for _, t := range triggers {
+			n.triggers[t] = true
+		}
+	}
+}
+
+// WithFavourites configures the drivers, by racing number or abbreviation, whose pit stops fire
+// notifications.
+func WithFavourites(drivers ...string) NotifierOption {
+	return func(n *Notifier) {
+		for _, d := range drivers {
+			n.favourites[strings.ToUpper(d)] = true
+		}
+	}
+}
+
+// WithTmuxPassthrough wraps escape sequences so that tmux forwards them to the outer terminal;
+// requires `set -g allow-passthrough on` in the tmux configuration.
+func WithTmuxPassthrough(enabled bool) NotifierOption {
+	return func(n *Notifier) { n.passthru = enabled }
+}
+
+// WithLogger configures the logger to use within the notifier.
+func WithLogger(l *slog.Logger) NotifierOption {
+	return func(n *Notifier) { n.logger = l }
+}
+
+/* Notifier API
+------------------------------------------------------------------------------------------------- */
+
+// ObserveMeeting compares the meeting with the previous snapshot, fires notifications for the
+// configured triggers and returns them.
+func (n *Notifier) ObserveMeeting(m domain.Meeting) []Event {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var events []Event
+	s := m.Session
+	if n.hasMeeting {
+		if s.TrackStatus != n.trackStatus && s.TrackStatus != domain.TrackStatusUnknown {
+			events = append(events, trackStatusEvent(s.TrackStatus))
+		}
+		if s.FastestLapOwner != "" && s.FastestLapOwner != n.fastestLap {
+			events = append(events, Event{
+				Trigger: TriggerFastestLap,
+				Title:   "Fastest Lap",
+				Body:    fmt.Sprintf("%s %s", n.driverName(s.FastestLapOwner), s.FastestLapTime),
+			})
+		}
+	}
+	n.hasMeeting = true
+	n.trackStatus = s.TrackStatus
+	n.fastestLap = s.FastestLapOwner
+
+	return n.fire(events)
+}
+
+// ObserveDrivers compares the drivers with the previous snapshot, fires notifications for the
+// configured triggers and returns them.
+func (n *Notifier) ObserveDrivers(drivers map[string]domain.Driver) []Event {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var events []Event
+	if n.hasDrivers {
+		var knockedOut []domain.Driver
+		for _, number := range sortedNumbers(drivers) {
+			d := drivers[number]
+			prev, ok := n.drivers[number]
+			if !ok {
+				continue
+			}
+			if d.TimingData.IsInPit && !prev.TimingData.IsInPit && n.isFavourite(d) {
+				events = append(events, Event{
+					Trigger: TriggerPit,
+					Title:   "Pit Stop",
+					Body:    fmt.Sprintf("%s is in the pit", d.Name),
+				})
+			}
+			if d.TimingData.IsRetired && !prev.TimingData.IsRetired {
+				events = append(events, Event{
+					Trigger: TriggerRetirement,
+					Title:   "Retirement",
+					Body:    fmt.Sprintf("%s has retired", d.Name),
+				})
+			}
+			if d.TimingData.IsKnockedOut && !prev.TimingData.IsKnockedOut {
+				knockedOut = append(knockedOut, d)
+			}
+		}
+		// drivers are knocked out together at the end of each qualifying part
+		if len(knockedOut) > 0 {
+			sort.Slice(knockedOut, func(i, j int) bool {
+				return knockedOut[i].TimingData.Position < knockedOut[j].TimingData.Position
+			})
+			names := make([]string, 0, len(knockedOut))
+			for _, d := range knockedOut {
+				names = append(names, d.ShortName)
+			}
+			events = append(events, Event{
+				Trigger: TriggerKnockout,
+				Title:   "Knocked Out",
+				Body:    strings.Join(names, ", "),
+			})
+		}
+	}
+	n.hasDrivers = true
+	n.drivers = drivers
+
+	return n.fire(events)
+}
+
+/* Private Helper Functions
+------------------------------------------------------------------------------------------------- */
+
+// fire delivers the events that match the configured triggers and returns them. The caller must
+// hold the lock.
+func (n *Notifier) fire(events []Event) []Event {
+	fired := make([]Event, 0, len(events))
+	for _, e := range events {
+		if !n.triggers[e.Trigger] {
+			continue
+		}
+		fired = append(fired, e)
+		n.logger.Debug("notification", "trigger", e.Trigger, "title", e.Title, "body", e.Body)
+		for _, m := range n.methods {
+			if _, err := io.WriteString(n.out, n.sequence(m, e)); err != nil {
+				n.logger.Warn("error writing notification", "method", m, "err", err)
+			}
+		}
+		if n.command != "" {
+			go n.runCommand(e)
+		}
+	}
+	return fired
+}
+
+// sequence returns the terminal escape sequence delivering the event using the given method.
+func (n *Notifier) sequence(m Method, e Event) string {
+	var seq string
+	switch m {
+	case MethodBell:
+		// tmux handles the bell itself so it never needs to be passed through
+		return "\a"
+	case MethodOSC9:
+		seq = fmt.Sprintf("\x1b]9;%s: %s\a", sanitize(e.Title), sanitize(e.Body))
+	case MethodOSC777:
+		seq = fmt.Sprintf("\x1b]777;notify;%s;%s\a", sanitize(e.Title), sanitize(e.Body))
+	default:
+		return ""
+	}
+	if n.passthru {
+		seq = "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	}
+	return seq
+}
+
+// runCommand runs the notification command hook for the event.
+func (n *Notifier) runCommand(e Event) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", n.command)
+	cmd.Env = append(os.Environ(),
+		"F1_EVENT="+string(e.Trigger),
+		"F1_TITLE="+e.Title,
+		"F1_BODY="+e.Body,
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		n.logger.Warn("error running notification command", "err", err, "output", string(out))
+	}
+}
+
+// driverName returns the name of the driver with the given number, falling back to the number if
+// the driver isn't known yet. The caller must hold the lock.
+func (n *Notifier) driverName(number string) string {
+	if d, ok := n.drivers[number]; ok && d.Name != "" {
+		return d.Name
+	}
+	return number
+}
+
+// isFavourite indicates if the driver was configured as a favourite by number or abbreviation.
+func (n *Notifier) isFavourite(d domain.Driver) bool {
+	return n.favourites[d.Number] || n.favourites[strings.ToUpper(d.ShortName)]
+}
+
+// trackStatusEvent returns the notification for a change in track status; a red flag is reported
+// as its own trigger so that it can be enabled independently.
+func trackStatusEvent(status domain.TrackStatus) Event {
+	switch status {
+	case domain.TrackStatusRed:
+		return Event{Trigger: TriggerRedFlag, Title: "Red Flag", Body: "The session has been red flagged"}
+	case domain.TrackStatusSC:
+		return Event{Trigger: TriggerTrackStatus, Title: "Safety Car", Body: "The safety car has been deployed"}
+	case domain.TrackStatusVSC:
+		return Event{Trigger: TriggerTrackStatus, Title: "Virtual Safety Car", Body: "The virtual safety car has been deployed"}
+	case domain.TrackStatusVSCEnding:
+		return Event{Trigger: TriggerTrackStatus, Title: "Virtual Safety Car", Body: "The virtual safety car is ending"}
+	case domain.TrackStatusYellow:
+		return Event{Trigger: TriggerTrackStatus, Title: "Yellow Flag", Body: "Yellow flag on track"}
+	default:
+		return Event{Trigger: TriggerTrackStatus, Title: "Track Clear", Body: "The track is clear"}
+	}
+}
+
+// sortedNumbers returns the racing numbers of the drivers in a stable order so that notifications
+// fired from the same snapshot are always delivered in the same order.
+func sortedNumbers(drivers map[string]domain.Driver) []string {
+	numbers := make([]string, 0, len(drivers))
+	for number := range drivers {
+		numbers = append(numbers, number)
+	}
+	sort.Strings(numbers)
+	return numbers
+}
+
+// sanitize removes characters that would terminate or corrupt an escape sequence.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == ';' {
+			return ' '
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,117 @@
+package notify
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/bcdxn/f1cli/domain"
+)
+
+func TestObserveMeeting(t *testing.T) {
+	var out bytes.Buffer
+	n := New(WithWriter(&out), WithMethods(MethodBell, MethodOSC9), WithLogger(testLogger(t)))
+
+	m := domain.NewMeeting()
+	m.Session.TrackStatus = domain.TrackStatusYellow
+	m.Session.FastestLapOwner = "1"
+	if events := n.ObserveMeeting(m); len(events) != 0 {
+		t.Errorf("expected %d events for the initial snapshot but found %d", 0, len(events))
+	}
+
+	m.Session.TrackStatus = domain.TrackStatusRed
+	m.Session.FastestLapOwner = "16"
+	m.Session.FastestLapTime = "1:23.456"
+	events := n.ObserveMeeting(m)
+	if len(events) != 2 {
+		t.Fatalf("expected %d events but found %d", 2, len(events))
+	}
+	if events[0].Trigger != TriggerRedFlag {
+		t.Errorf("expected trigger '%s' but found '%s'", TriggerRedFlag, events[0].Trigger)
+	}
+	if events[1].Trigger != TriggerFastestLap || events[1].Body != "16 1:23.456" {
+		t.Errorf("expected fastest lap '%s' but found '%s'", "16 1:23.456", events[1].Body)
+	}
+	expected := "\a\x1b]9;Red Flag: The session has been red flagged\a\a\x1b]9;Fastest Lap: 16 1:23.456\a"
+	if out.String() != expected {
+		t.Errorf("expected output %q but found %q", expected, out.String())
+	}
+
+	if events := n.ObserveMeeting(m); len(events) != 0 {
+		t.Errorf("expected %d events for an unchanged snapshot but found %d", 0, len(events))
+	}
+}
+
+func TestObserveDrivers(t *testing.T) {
+	n := New(WithWriter(io.Discard), WithFavourites("lec"), WithLogger(testLogger(t)))
+
+	drivers := map[string]domain.Driver{
+		"16": testDriver("16", "LEC", 1),
+		"44": testDriver("44", "HAM", 2),
+		"10": testDriver("10", "GAS", 3),
+		"31": testDriver("31", "OCO", 4),
+	}
+	n.ObserveDrivers(drivers)
+
+	next := copyDrivers(drivers)
+	setTimingData(next, "16", func(td *domain.DriverTimingData) { td.IsInPit = true })
+	setTimingData(next, "44", func(td *domain.DriverTimingData) { td.IsInPit = true })
+	setTimingData(next, "31", func(td *domain.DriverTimingData) { td.IsKnockedOut = true })
+	setTimingData(next, "10", func(td *domain.DriverTimingData) { td.IsKnockedOut = true })
+	events := n.ObserveDrivers(next)
+
+	if len(events) != 2 {
+		t.Fatalf("expected %d events but found %d", 2, len(events))
+	}
+	if events[0].Trigger != TriggerPit || events[0].Body != "LEC is in the pit" {
+		t.Errorf("expected favourite pit stop but found '%s' '%s'", events[0].Trigger, events[0].Body)
+	}
+	if events[1].Trigger != TriggerKnockout || events[1].Body != "GAS, OCO" {
+		t.Errorf("expected knockout of '%s' but found '%s'", "GAS, OCO", events[1].Body)
+	}
+
+	t.Run("Triggers", func(t *testing.T) {
+		n := New(WithWriter(io.Discard), WithTriggers(TriggerRetirement), WithLogger(testLogger(t)))
+		n.ObserveDrivers(drivers)
+		next := copyDrivers(drivers)
+		setTimingData(next, "44", func(td *domain.DriverTimingData) { td.IsRetired = true })
+		setTimingData(next, "10", func(td *domain.DriverTimingData) { td.IsKnockedOut = true })
+		events := n.ObserveDrivers(next)
+		if len(events) != 1 || events[0].Trigger != TriggerRetirement {
+			t.Errorf("expected only a retirement but found %v", events)
+		}
+	})
+}
+
+/* Test Helpers
+------------------------------------------------------------------------------------------------- */
+
+func testDriver(number, shortName string, position int) domain.Driver {
+	d := domain.NewDriver(number)
+	d.ShortName = shortName
+	d.Name = shortName
+	d.TimingData.Position = position
+	return d
+}
+
+func copyDrivers(drivers map[string]domain.Driver) map[string]domain.Driver {
+	c := make(map[string]domain.Driver, len(drivers))
+	for k, v := range drivers {
+		c[k] = v
+	}
+	return c
+}
+
+func setTimingData(drivers map[string]domain.Driver, number string, fn func(td *domain.DriverTimingData)) {
+	d := drivers[number]
+	fn(&d.TimingData)
+	drivers[number] = d
+}
+
+// testLogger creates a new logger to be used in tests that writes all logs to /dev/null so they
+// don't uglify the test output.
+func testLogger(t *testing.T) *slog.Logger {
+	t.Helper()
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
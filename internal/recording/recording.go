@@ -0,0 +1,110 @@
+package recording
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Frame is a single line of a recording; a raw message received from the F1 LiveTiming API and the
+// time it was received.
+type Frame struct {
+	Time time.Time       `json:"t"` // Time is when the message was received
+	Msg  json.RawMessage `json:"m"` // Msg is the raw SignalR message
+}
+
+// NewWriter returns a new writer that records raw messages as newline-delimited JSON frames to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		enc: json.NewEncoder(w),
+		now: time.Now,
+	}
+}
+
+// Writer records raw messages received from the F1 LiveTiming API; it is safe for concurrent use.
+type Writer struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	now func() time.Time
+}
+
+// Write records a raw message received now; messages that aren't valid JSON can't be replayed and
+// are rejected.
+func (w *Writer) Write(msg []byte) error {
+	if !json.Valid(msg) {
+		return errors.New("error recording message: invalid JSON")
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.enc.Encode(Frame{Time: w.now().UTC(), Msg: msg})
+}
+
+// NewReader returns a new reader of the frames recorded to r.
+func NewReader(r io.Reader) *Reader {
+	sc := bufio.NewScanner(r)
+	// reference messages are a few hundred kilobytes
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	return &Reader{sc: sc}
+}
+
+// Reader reads the frames of a recording in order.
+type Reader struct {
+	sc   *bufio.Scanner
+	line int
+}
+
+// Read returns the next frame of the recording, or io.EOF once every frame has been read.
+func (r *Reader) Read() (Frame, error) {
+	var f Frame
+	for r.sc.Scan() {
+		r.line++
+		if len(r.sc.Bytes()) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(r.sc.Bytes(), &f); err != nil {
+			return f, fmt.Errorf("error reading recording line %d: %w", r.line, err)
+		}
+		return f, nil
+	}
+	if err := r.sc.Err(); err != nil {
+		return f, fmt.Errorf("error reading recording: %w", err)
+	}
+	return f, io.EOF
+}
+
+// Play writes the raw message of each frame of the recording to msgs with the delays between them
+// as they were recorded, divided by the speed; a speed of 0 plays the recording without any delay.
+// The msgs channel is closed once the recording ends, an error occurs or the context is cancelled.
+func Play(ctx context.Context, r *Reader, speed float64, msgs chan<- []byte) error {
+	defer close(msgs)
+
+	var prev time.Time
+	for {
+		f, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if speed > 0 && !prev.IsZero() && f.Time.After(prev) {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(time.Duration(float64(f.Time.Sub(prev)) / speed)):
+			}
+		}
+		prev = f.Time
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case msgs <- f.Msg:
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package recording
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecording(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	start := time.Date(2024, 5, 26, 13, 0, 0, 0, time.UTC)
+	times := []time.Time{start, start.Add(time.Second), start.Add(3 * time.Second)}
+	w.now = func() time.Time {
+		t := times[0]
+		times = times[1:]
+		return t
+	}
+	for _, msg := range []string{`{"C":"1"}`, `{"M":[]}`, `{}`} {
+		if err := w.Write([]byte(msg)); err != nil {
+			t.Fatalf("expected no error but found %v", err)
+		}
+	}
+	if err := w.Write([]byte(`{"C":`)); err == nil {
+		t.Errorf("expected an error recording invalid JSON")
+	}
+
+	t.Run("Read", func(t *testing.T) {
+		r := NewReader(bytes.NewReader(buf.Bytes()))
+		f, err := r.Read()
+		if err != nil {
+			t.Fatalf("expected no error but found %v", err)
+		}
+		if !f.Time.Equal(start) || string(f.Msg) != `{"C":"1"}` {
+			t.Errorf("expected frame '%s' at %s but found '%s' at %s", `{"C":"1"}`, start, f.Msg, f.Time)
+		}
+		r.Read()
+		r.Read()
+		if _, err := r.Read(); err != io.EOF {
+			t.Errorf("expected io.EOF at the end of the recording but found %v", err)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		r := NewReader(strings.NewReader("\n" + `{"t":"2024-05-26T13:00:00Z","m":{}}` + "\nnot json\n"))
+		if _, err := r.Read(); err != nil {
+			t.Fatalf("expected no error but found %v", err)
+		}
+		_, err := r.Read()
+		if err == nil || !strings.Contains(err.Error(), "line 3") {
+			t.Errorf("expected an error on line 3 but found %v", err)
+		}
+	})
+
+	t.Run("Play", func(t *testing.T) {
+		msgs := make(chan []byte)
+		errCh := make(chan error, 1)
+		// a second and two seconds between the frames played back at a 100x speed
+		go func() { errCh <- Play(context.Background(), NewReader(bytes.NewReader(buf.Bytes())), 100, msgs) }()
+
+		begin := time.Now()
+		count := 0
+		for range msgs {
+			count++
+		}
+		if count != 3 {
+			t.Errorf("expected %d messages but found %d", 3, count)
+		}
+		if elapsed := time.Since(begin); elapsed < 30*time.Millisecond {
+			t.Errorf("expected playback to take at least %s but found %s", 30*time.Millisecond, elapsed)
+		}
+		if err := <-errCh; err != nil {
+			t.Errorf("expected no error but found %v", err)
+		}
+	})
+}
@@ -0,0 +1,451 @@
+package relay
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+const (
+	// subscriberBufferSize is the number of frames buffered for each downstream client; a client that
+	// falls further behind is disconnected since dropping change frames would corrupt its state.
+	subscriberBufferSize = 1024
+	// keepAliveInterval is how often an empty keep-alive frame is sent to downstream clients.
+	keepAliveInterval = 10 * time.Second
+	// disconnectTimeout is how long a lost downstream connection may be resumed for.
+	disconnectTimeout = 30 * time.Second
+)
+
+// New returns a new relay that re-broadcasts the F1 LiveTiming API to downstream clients.
+func New(opts ...RelayOption) *Relay {
+	r := &Relay{
+		state:       make(state),
+		ready:       make(chan struct{}),
+		subscribers: make(map[*subscriber]struct{}),
+		connections: make(map[string]*connection),
+		logger:      slog.Default(),
+	}
+	// apply given options
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Relay holds the current reference state of the upstream F1 LiveTiming API connection and serves
+// a SignalR compatible endpoint so that any number of downstream clients (configured with
+// `WithHTTPBaseURL` and `WithWSBaseURL`) receive the reference state followed by live changes
+// without connecting to the public endpoint themselves.
+type Relay struct {
+	mu          sync.Mutex
+	state       state
+	cursor      json.RawMessage
+	ready       chan struct{}
+	isReady     bool
+	subscribers map[*subscriber]struct{}
+	connections map[string]*connection // connections are the downstream connections that may be resumed by token
+	logger      *slog.Logger
+}
+
+// subscriber is a single downstream client connection.
+type subscriber struct {
+	token  string // token is the connection token the client connected with
+	topics map[string]bool
+	frames chan []byte
+	cancel context.CancelFunc
+}
+
+// connection is a downstream connection that may be resumed by its token until the disconnect
+// timeout passes after it was lost.
+type connection struct {
+	topics []string  // topics are the topics subscribed to
+	open   int       // open is the number of websocket connections currently using the token
+	lostAt time.Time // lostAt is when the latest websocket connection using the token ended
+}
+
+/* Relay Optional Functional Parameters
+------------------------------------------------------------------------------------------------- */
+
+type RelayOption = func(r *Relay)
+
+// WithLogger configures the logger to use within the relay.
+func WithLogger(l *slog.Logger) RelayOption {
+	return func(r *Relay) { r.logger = l }
+}
+
+/* Relay API
+------------------------------------------------------------------------------------------------- */
+
+// HandleMessage updates the reference state with a raw message received from the upstream F1
+// LiveTiming API and forwards any changes to the downstream clients.
+func (r *Relay) HandleMessage(msg []byte) {
+	var f frame
+	if err := json.Unmarshal(msg, &f); err != nil {
+		r.logger.Warn("relay received unknown message format", "err", err)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(f.Cursor) > 0 {
+		r.cursor = f.Cursor
+	}
+
+	if len(f.Reference) > 0 {
+		var ref map[string]any
+		if err := decode(f.Reference, &ref); err != nil {
+			r.logger.Warn("relay received invalid reference message", "err", err)
+		} else {
+			r.state.setReference(ref)
+			if !r.isReady {
+				r.isReady = true
+				close(r.ready)
+			}
+		}
+	}
+
+	if len(f.Changes) == 0 {
+		return
+	}
+	for _, m := range f.Changes {
+		topic, data, ok := m.topicData()
+		if !ok {
+			continue
+		}
+		var change any
+		if err := decode(data, &change); err != nil {
+			r.logger.Warn("relay received invalid change message", "topic", topic, "err", err)
+			continue
+		}
+		r.state.applyChange(topic, change)
+	}
+	for sub := range r.subscribers {
+		changes := make([]hubMessage, 0, len(f.Changes))
+		for _, m := range f.Changes {
+			if topic, _, ok := m.topicData(); ok && sub.topics[topic] {
+				changes = append(changes, m)
+			}
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		b, err := json.Marshal(frame{Cursor: r.cursor, Changes: changes})
+		if err != nil {
+			r.logger.Warn("relay failed to encode change message", "err", err)
+			continue
+		}
+		r.send(sub, b)
+	}
+}
+
+// Handler returns the HTTP handler serving the SignalR negotiate, connect and reconnect endpoints.
+func (r *Relay) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/signalr/negotiate", r.handleNegotiate)
+	mux.HandleFunc("/signalr/connect", r.handleConnect)
+	mux.HandleFunc("/signalr/reconnect", r.handleReconnect)
+	return mux
+}
+
+// ListenAndServe serves the relay on the given address until the context is cancelled.
+func (r *Relay) ListenAndServe(ctx context.Context, addr string) error {
+	srv := &http.Server{
+		Addr:        addr,
+		Handler:     r.Handler(),
+		BaseContext: func(_ net.Listener) context.Context { return ctx },
+	}
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.ListenAndServe() }()
+	r.logger.Info("serving relay", "addr", addr)
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("error serving relay: %w", err)
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("error shutting down relay: %w", err)
+		}
+		return nil
+	}
+}
+
+/* HTTP Handlers
+------------------------------------------------------------------------------------------------- */
+
+// handleNegotiate mimics the SignalR negotiate endpoint, issuing a connection token that is required
+// by the connect endpoint and identifies the connection when it is resumed.
+func (r *Relay) handleNegotiate(w http.ResponseWriter, req *http.Request) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		http.Error(w, "error generating connection token", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"Url":                     "/signalr",
+		"ConnectionToken":         hex.EncodeToString(token),
+		"ConnectionId":            hex.EncodeToString(token[:8]),
+		"KeepAliveTimeout":        20.0,
+		"DisconnectTimeout":       disconnectTimeout.Seconds(),
+		"ConnectionTimeout":       110.0,
+		"TryWebSockets":           true,
+		"ProtocolVersion":         "1.5",
+		"TransportConnectTimeout": 10.0,
+		"LongPollDelay":           0.0,
+	})
+}
+
+// handleConnect upgrades the connection to a websocket, waits for the client to subscribe and then
+// sends the current reference state of the subscribed topics followed by all subsequent changes.
+func (r *Relay) handleConnect(w http.ResponseWriter, req *http.Request) {
+	r.serve(w, req, nil)
+}
+
+// handleReconnect resumes a downstream connection lost less than the disconnect timeout ago. The
+// relay keeps no history of the changes, so the client is resynced with the current reference state
+// of the topics it subscribed to before receiving subsequent changes; connections that can't be
+// resumed are rejected so that the client starts a new one.
+func (r *Relay) handleReconnect(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	var topics []string
+	if c, ok := r.connections[req.URL.Query().Get("connectionToken")]; ok {
+		topics = slices.Clone(c.topics)
+	}
+	r.mu.Unlock()
+	if len(topics) == 0 {
+		http.Error(w, "unknown connection", http.StatusNotFound)
+		return
+	}
+	r.serve(w, req, topics)
+}
+
+/* Private Helper Functions
+------------------------------------------------------------------------------------------------- */
+
+// serve upgrades the connection to a websocket and sends the frames of the downstream client until
+// it disconnects. A new connection is subscribed once the client invokes Subscribe, while a resumed
+// connection is subscribed to the given topics right away.
+func (r *Relay) serve(w http.ResponseWriter, req *http.Request, resumed []string) {
+	conn, err := websocket.Accept(w, req, nil)
+	if err != nil {
+		r.logger.Warn("relay failed to accept websocket connection", "err", err)
+		return
+	}
+	defer conn.CloseNow()
+	conn.SetReadLimit(-1)
+
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+	sub := &subscriber{
+		token:  req.URL.Query().Get("connectionToken"),
+		topics: make(map[string]bool),
+		frames: make(chan []byte, subscriberBufferSize),
+		cancel: cancel,
+	}
+	r.open(sub.token)
+	defer r.unsubscribe(sub)
+
+	if resumed != nil {
+		if err := r.subscribe(sub, resumed, nil); err != nil {
+			r.logger.Warn("relay failed to resume client", "err", err)
+			return
+		}
+	} else if err := conn.Write(ctx, websocket.MessageText, []byte(`{"C":"","S":1,"M":[]}`)); err != nil {
+		// SignalR sends an initialization frame as soon as the connection is established
+		return
+	}
+
+	go r.readInvocations(ctx, conn, sub)
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		var msg []byte
+		select {
+		case <-ctx.Done():
+			conn.Close(websocket.StatusNormalClosure, "relay closed")
+			return
+		case <-keepAlive.C:
+			msg = []byte(`{}`)
+		case msg = <-sub.frames:
+		}
+		if err := conn.Write(ctx, websocket.MessageText, msg); err != nil {
+			r.logger.Debug("relay failed to write to client", "err", err)
+			return
+		}
+	}
+}
+
+// readInvocations reads hub invocations sent by a downstream client, subscribing it to the
+// requested topics; the connection is closed when the client disconnects.
+func (r *Relay) readInvocations(ctx context.Context, conn *websocket.Conn, sub *subscriber) {
+	defer sub.cancel()
+	for {
+		_, msg, err := conn.Read(ctx)
+		if err != nil {
+			return
+		}
+		var inv invocation
+		if err := json.Unmarshal(msg, &inv); err != nil || inv.Method != "Subscribe" || len(inv.Arguments) == 0 {
+			r.logger.Debug("relay ignoring client message", "msg", string(msg))
+			continue
+		}
+		var topics []string
+		if err := json.Unmarshal(inv.Arguments[0], &topics); err != nil {
+			r.logger.Debug("relay received invalid subscribe message", "msg", string(msg))
+			continue
+		}
+		// wait until the upstream reference state is available before answering
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.ready:
+		}
+		if err := r.subscribe(sub, topics, inv.ID); err != nil {
+			r.logger.Warn("relay failed to subscribe client", "err", err)
+			return
+		}
+	}
+}
+
+// subscribe sends the reference state of the given topics as the result of the subscribe
+// invocation and registers the subscriber for subsequent changes. Both happen while holding the
+// lock so that no change is missed or duplicated.
+func (r *Relay) subscribe(sub *subscriber, topics []string, id json.RawMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ref := make(map[string]any, len(topics))
+	for _, topic := range topics {
+		sub.topics[topic] = true
+		if v, ok := r.state[topic]; ok {
+			ref[topic] = v
+		}
+	}
+	// the topics are kept so that the connection can be resumed
+	if c, ok := r.connections[sub.token]; ok {
+		c.topics = slices.Collect(maps.Keys(sub.topics))
+	}
+	b, err := json.Marshal(struct {
+		Reference map[string]any  `json:"R"`
+		ID        json.RawMessage `json:"I,omitempty"`
+	}{ref, id})
+	if err != nil {
+		return err
+	}
+	r.send(sub, b)
+	r.subscribers[sub] = struct{}{}
+	return nil
+}
+
+// unsubscribe removes a downstream client; its connection may be resumed until the disconnect
+// timeout passes.
+func (r *Relay) unsubscribe(sub *subscriber) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.subscribers, sub)
+	if c, ok := r.connections[sub.token]; ok {
+		c.open--
+		c.lostAt = time.Now()
+		time.AfterFunc(disconnectTimeout, func() { r.expire(sub.token) })
+	}
+}
+
+// open registers a websocket connection using the given connection token.
+func (r *Relay) open(token string) {
+	if token == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.connections[token]
+	if !ok {
+		c = &connection{}
+		r.connections[token] = c
+	}
+	c.open++
+}
+
+// expire forgets the connection with the given token once it was lost for the disconnect timeout
+// without being resumed.
+func (r *Relay) expire(token string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if c, ok := r.connections[token]; ok && c.open == 0 && time.Since(c.lostAt) >= disconnectTimeout {
+		delete(r.connections, token)
+	}
+}
+
+// send queues a frame for a downstream client without blocking; clients that can't keep up are
+// disconnected. The caller must hold the lock.
+func (r *Relay) send(sub *subscriber, b []byte) {
+	select {
+	case sub.frames <- b:
+	default:
+		r.logger.Warn("relay disconnecting slow client")
+		delete(r.subscribers, sub)
+		sub.cancel()
+	}
+}
+
+// decode unmarshals JSON preserving the exact representation of numbers so that re-encoded state
+// is identical to what was received upstream.
+func decode(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+/* Private types
+------------------------------------------------------------------------------------------------- */
+
+// frame is a single SignalR message sent by the server; it contains either the result of an
+// invocation (e.g. the reference state returned from Subscribe) or hub messages with changes.
+type frame struct {
+	Cursor    json.RawMessage `json:"C,omitempty"`
+	Changes   []hubMessage    `json:"M,omitempty"`
+	Reference json.RawMessage `json:"R,omitempty"`
+}
+
+// hubMessage is a single hub method call sent by the server, e.g. a `feed` call with a change.
+type hubMessage struct {
+	Hub       string            `json:"H"`
+	Method    string            `json:"M"`
+	Arguments []json.RawMessage `json:"A"`
+}
+
+// topicData returns the topic and data of a feed hub message.
+func (m hubMessage) topicData() (string, json.RawMessage, bool) {
+	if len(m.Arguments) < 2 {
+		return "", nil, false
+	}
+	var topic string
+	if err := json.Unmarshal(m.Arguments[0], &topic); err != nil {
+		return "", nil, false
+	}
+	return topic, m.Arguments[1], true
+}
+
+// invocation is a hub method call sent by a client, e.g. Subscribe.
+type invocation struct {
+	Hub       string            `json:"H"`
+	Method    string            `json:"M"`
+	Arguments []json.RawMessage `json:"A"`
+	ID        json.RawMessage   `json:"I"`
+}
@@ -0,0 +1,206 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bcdxn/f1cli/domain"
+	"github.com/bcdxn/f1cli/f1livetiming"
+	"github.com/coder/websocket"
+)
+
+func TestRelay(t *testing.T) {
+	td := testdataDir()
+	ref, _ := os.ReadFile(path.Join(td, "ref-msg-race.json"))
+	change, _ := os.ReadFile(path.Join(td, "ch-msg-race-timingdata.json"))
+
+	r := New(WithLogger(testLogger(t)))
+	r.HandleMessage(ref)
+
+	srv := httptest.NewServer(r.Handler())
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	c := f1livetiming.New(
+		f1livetiming.WithLogger(testLogger(t)),
+		f1livetiming.WithHTTPBaseURL(srv.URL),
+		f1livetiming.WithWSBaseURL(strings.Replace(srv.URL, "http://", "ws://", 1)),
+	)
+	// read the channels before listening since the getters copy the client
+	ch := clientChannels{c.Drivers(), c.Meeting(), c.RaceCtrlMsgs(), c.Done()}
+	go c.Listen(ctx)
+
+	// the relayed reference state is received first
+	drivers := receiveDrivers(ctx, t, ch)
+	if len(drivers) != 20 {
+		t.Errorf("expected %d drivers but found %d", 20, len(drivers))
+	}
+	if drivers["23"].TimingData.Position == 16 {
+		t.Errorf("expected position to differ from %d before the change", 16)
+	}
+
+	// subsequent changes are forwarded to the connected client
+	r.HandleMessage(change)
+	for drivers["23"].TimingData.Position != 16 && ctx.Err() == nil {
+		drivers = receiveDrivers(ctx, t, ch)
+	}
+	if drivers["23"].TimingData.Position != 16 {
+		t.Errorf("expected position %d but found %d", 16, drivers["23"].TimingData.Position)
+	}
+	if drivers["23"].TimingData.LeaderGap != "+4.625" {
+		t.Errorf("expected leader gap %s but found %s", "+4.625", drivers["23"].TimingData.LeaderGap)
+	}
+}
+
+func TestResume(t *testing.T) {
+	td := testdataDir()
+	ref, _ := os.ReadFile(path.Join(td, "ref-msg-race.json"))
+
+	r := New(WithLogger(testLogger(t)))
+	r.HandleMessage(ref)
+
+	srv := httptest.NewServer(r.Handler())
+	defer srv.Close()
+	wsURL := strings.Replace(srv.URL, "http://", "ws://", 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := http.Get(srv.URL + "/signalr/negotiate")
+	if err != nil {
+		t.Fatalf("expected no error but found '%s'", err)
+	}
+	var n struct{ ConnectionToken string }
+	json.NewDecoder(resp.Body).Decode(&n)
+	resp.Body.Close()
+	query := "?connectionToken=" + url.QueryEscape(n.ConnectionToken)
+
+	// subscribe to a topic before the connection is lost
+	conn, _, err := websocket.Dial(ctx, wsURL+"/signalr/connect"+query, nil)
+	if err != nil {
+		t.Fatalf("expected no error but found '%s'", err)
+	}
+	conn.SetReadLimit(-1)
+	conn.Write(ctx, websocket.MessageText, []byte(`{"H":"Streaming","M":"Subscribe","A":[["LapCount"]],"I":1}`))
+	readFrame(ctx, t, conn) // initialization
+	readFrame(ctx, t, conn) // reference
+	conn.CloseNow()
+
+	// the resumed connection is resynced with the reference state of the topics subscribed to
+	conn, _, err = websocket.Dial(ctx, wsURL+"/signalr/reconnect"+query, nil)
+	if err != nil {
+		t.Fatalf("expected the connection to be resumed but found '%s'", err)
+	}
+	conn.SetReadLimit(-1)
+	var resumed struct{ R map[string]json.RawMessage }
+	json.Unmarshal(readFrame(ctx, t, conn), &resumed)
+	if _, ok := resumed.R["LapCount"]; !ok || len(resumed.R) != 1 {
+		t.Errorf("expected the reference state of topic '%s' but found %v", "LapCount", resumed.R)
+	}
+	conn.CloseNow()
+
+	// unknown connections can't be resumed
+	_, resp, err = websocket.Dial(ctx, wsURL+"/signalr/reconnect?connectionToken=unknown", nil)
+	if err == nil || resp == nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status %d resuming an unknown connection but found '%v'", http.StatusNotFound, err)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	target := map[string]any{
+		"Lines": map[string]any{
+			"1": map[string]any{"Position": "1", "Sectors": []any{
+				map[string]any{"Value": "30.1"},
+				map[string]any{"Value": "31.2"},
+			}},
+			"4": map[string]any{"Position": "2"},
+		},
+	}
+	change := map[string]any{
+		"Lines": map[string]any{
+			"1": map[string]any{"Sectors": map[string]any{"1": map[string]any{"Value": "30.9"}}},
+			"4": map[string]any{"Position": "3"},
+		},
+		"_deleted": []any{"Withheld"},
+	}
+
+	merged := merge(target, change).(map[string]any)
+	lines := merged["Lines"].(map[string]any)
+	sectors := lines["1"].(map[string]any)["Sectors"].([]any)
+	if v := sectors[1].(map[string]any)["Value"]; v != "30.9" {
+		t.Errorf("expected sector value '%s' but found '%s'", "30.9", v)
+	}
+	if v := sectors[0].(map[string]any)["Value"]; v != "30.1" {
+		t.Errorf("expected sector value '%s' but found '%s'", "30.1", v)
+	}
+	if v := lines["4"].(map[string]any)["Position"]; v != "3" {
+		t.Errorf("expected position '%s' but found '%s'", "3", v)
+	}
+	if v := lines["1"].(map[string]any)["Position"]; v != "1" {
+		t.Errorf("expected position '%s' but found '%s'", "1", v)
+	}
+}
+
+/* Test Helpers
+------------------------------------------------------------------------------------------------- */
+
+// clientChannels are the channels of an F1 LiveTiming client.
+type clientChannels struct {
+	drivers      <-chan map[string]domain.Driver
+	meeting      <-chan domain.Meeting
+	raceCtrlMsgs <-chan domain.RaceCtrlMsg
+	done         <-chan error
+}
+
+// receiveDrivers waits for the next drivers snapshot from the client, draining the other channels.
+func receiveDrivers(ctx context.Context, t *testing.T, c clientChannels) map[string]domain.Driver {
+	t.Helper()
+	for {
+		select {
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for drivers")
+		case err := <-c.done:
+			t.Fatalf("client exited unexpectedly: %v", err)
+		case <-c.meeting:
+		case <-c.raceCtrlMsgs:
+		case drivers := <-c.drivers:
+			return drivers
+		}
+	}
+}
+
+// readFrame returns the next frame received on the connection.
+func readFrame(ctx context.Context, t *testing.T, conn *websocket.Conn) []byte {
+	t.Helper()
+	_, msg, err := conn.Read(ctx)
+	if err != nil {
+		t.Fatalf("expected a frame but found '%s'", err)
+	}
+	return msg
+}
+
+func testdataDir() string {
+	_, p, _, _ := runtime.Caller(0)
+	return path.Join(filepath.Dir(p), "..", "..", "f1livetiming", "testdata")
+}
+
+// testLogger creates a new logger to be used in tests that writes all logs to /dev/null so they
+// don't uglify the test output.
+func testLogger(t *testing.T) *slog.Logger {
+	t.Helper()
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
@@ -0,0 +1,69 @@
+package relay
+
+import (
+	"strconv"
+)
+
+// state is the current reference state of each topic of the F1 LiveTiming API, i.e. the initial
+// reference message with every subsequent change message merged into it, as decoded JSON values.
+type state map[string]any
+
+// setReference replaces the state of each topic given in the reference message.
+func (s state) setReference(ref map[string]any) {
+	for topic, v := range ref {
+		s[topic] = v
+	}
+}
+
+// applyChange merges a change for the given topic into the state.
+func (s state) applyChange(topic string, change any) {
+	s[topic] = merge(s[topic], change)
+}
+
+// merge applies a change to a JSON value the same way the F1 LiveTiming API expects its consumers
+// to: objects are merged recursively, keys listed in `_deleted` are removed, lists in the reference
+// are updated by index using the numeric keys of change objects and any other value is replaced.
+func merge(target, change any) any {
+	patch, ok := change.(map[string]any)
+	if !ok {
+		return change
+	}
+
+	switch t := target.(type) {
+	case map[string]any:
+		for k, v := range patch {
+			if k == "_deleted" {
+				continue
+			}
+			t[k] = merge(t[k], v)
+		}
+		if deleted, ok := patch["_deleted"].([]any); ok {
+			for _, k := range deleted {
+				if key, ok := k.(string); ok {
+					delete(t, key)
+				}
+			}
+		}
+		return t
+	case []any:
+		for k, v := range patch {
+			i, err := strconv.Atoi(k)
+			if err != nil || i < 0 {
+				continue
+			}
+			for len(t) <= i {
+				t = append(t, nil)
+			}
+			t[i] = merge(t[i], v)
+		}
+		return t
+	default:
+		m := make(map[string]any, len(patch))
+		for k, v := range patch {
+			if k != "_deleted" {
+				m[k] = merge(nil, v)
+			}
+		}
+		return m
+	}
+}
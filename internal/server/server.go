@@ -0,0 +1,266 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bcdxn/f1cli/domain"
+	"github.com/bcdxn/f1cli/f1livetiming"
+	"github.com/bcdxn/f1cli/internal/stream"
+)
+
+const (
+	// subscriberBufferSize is the number of events buffered for each SSE subscriber; events are
+	// dropped for subscribers that fall further behind so a slow consumer can't stall the others.
+	subscriberBufferSize = 32
+	// keepAliveInterval is how often a comment is written to idle SSE connections to keep proxies
+	// from closing them.
+	keepAliveInterval = 15 * time.Second
+)
+
+// New returns a new HTTP API server exposing the live state of the session.
+func New(opts ...ServerOption) *Server {
+	s := &Server{
+		meeting:     domain.NewMeeting(),
+		drivers:     make(map[string]domain.Driver),
+		subscribers: make(map[chan stream.Record]struct{}),
+		logger:      slog.Default(),
+	}
+	// apply given options
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Server serves the latest meeting, drivers and race control state over HTTP and streams updates to
+// any number of consumers as Server-Sent Events, so that many tools can share a single upstream
+// connection to the F1 LiveTiming API.
+type Server struct {
+	// session state
+	mu           sync.RWMutex
+	meeting      domain.Meeting
+	drivers      map[string]domain.Driver
+	raceCtrlMsgs []domain.RaceCtrlMsg
+	source       func() f1livetiming.Snapshot // source provides the session state on demand if configured
+	// SSE subscribers
+	subscribers map[chan stream.Record]struct{}
+	// logger
+	logger *slog.Logger
+}
+
+/* Server Optional Functional Parameters
+------------------------------------------------------------------------------------------------- */
+
+type ServerOption = func(s *Server)
+
+// WithLogger configures the logger to use within the server.
+func WithLogger(l *slog.Logger) ServerOption {
+	return func(s *Server) { s.logger = l }
+}
+
+// WithSnapshotSource configures the server to read the state of the session on demand from the
+// source, e.g. the Snapshot method of the F1 LiveTiming client, rather than keeping the state set by
+// SetMeeting, SetDrivers and AddRaceCtrlMsg; these still publish the updates to subscribers. The race
+// control history served is then complete even if messages were dropped before reaching the server.
+func WithSnapshotSource(source func() f1livetiming.Snapshot) ServerOption {
+	return func(s *Server) { s.source = source }
+}
+
+/* Server API
+------------------------------------------------------------------------------------------------- */
+
+// SetMeeting stores the latest meeting snapshot and publishes it to all subscribers.
+func (s *Server) SetMeeting(m domain.Meeting) {
+	s.mu.Lock()
+	s.meeting = m
+	s.mu.Unlock()
+	s.publish(stream.NewRecord(stream.RecordTypeMeeting, m, time.Now()))
+}
+
+// SetDrivers stores the latest drivers snapshot and publishes it to all subscribers.
+func (s *Server) SetDrivers(d map[string]domain.Driver) {
+	s.mu.Lock()
+	s.drivers = d
+	s.mu.Unlock()
+	s.publish(stream.NewRecord(stream.RecordTypeDrivers, d, time.Now()))
+}
+
+// AddRaceCtrlMsg appends a race control message to the history and publishes it to all
+// subscribers.
+func (s *Server) AddRaceCtrlMsg(msg domain.RaceCtrlMsg) {
+	s.mu.Lock()
+	s.raceCtrlMsgs = append(s.raceCtrlMsgs, msg)
+	s.mu.Unlock()
+	s.publish(stream.NewRecord(stream.RecordTypeRaceControl, msg, time.Now()))
+}
+
+// Handler returns the HTTP handler serving the API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /meeting", s.handleMeeting)
+	mux.HandleFunc("GET /drivers", s.handleDrivers)
+	mux.HandleFunc("GET /race-control", s.handleRaceCtrlMsgs)
+	mux.HandleFunc("GET /events", s.handleEvents)
+	return mux
+}
+
+// ListenAndServe serves the API on the given address until the context is cancelled.
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	srv := &http.Server{
+		Addr:        addr,
+		Handler:     s.Handler(),
+		BaseContext: func(_ net.Listener) context.Context { return ctx },
+	}
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.ListenAndServe() }()
+	s.logger.Info("serving http api", "addr", addr)
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("error serving http api: %w", err)
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("error shutting down http api: %w", err)
+		}
+		return nil
+	}
+}
+
+/* HTTP Handlers
+------------------------------------------------------------------------------------------------- */
+
+func (s *Server) handleMeeting(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, s.snapshot().Meeting)
+}
+
+func (s *Server) handleDrivers(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, s.snapshot().Drivers)
+}
+
+func (s *Server) handleRaceCtrlMsgs(w http.ResponseWriter, r *http.Request) {
+	msgs := s.snapshot().RaceCtrlMsgs
+	if msgs == nil {
+		msgs = []domain.RaceCtrlMsg{}
+	}
+	s.writeJSON(w, msgs)
+}
+
+// handleEvents streams updates as Server-Sent Events; the event name is the record type and the
+// data is the same record written by the JSON output mode. The current meeting and drivers
+// snapshots are sent as soon as the client connects.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	ch := s.subscribe()
+	defer s.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	snapshot := s.snapshot()
+	initial := []stream.Record{
+		stream.NewRecord(stream.RecordTypeMeeting, snapshot.Meeting, time.Now()),
+		stream.NewRecord(stream.RecordTypeDrivers, snapshot.Drivers, time.Now()),
+	}
+	for _, rec := range initial {
+		if err := writeEvent(w, rec); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case rec := <-ch:
+			if err := writeEvent(w, rec); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+/* Private Helper Functions
+------------------------------------------------------------------------------------------------- */
+
+// snapshot returns the current state of the session, read from the snapshot source if configured.
+func (s *Server) snapshot() f1livetiming.Snapshot {
+	if s.source != nil {
+		return s.source()
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return f1livetiming.Snapshot{Meeting: s.meeting, Drivers: s.drivers, RaceCtrlMsgs: s.raceCtrlMsgs}
+}
+
+// writeJSON writes the given value as the JSON response body.
+func (s *Server) writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.logger.Warn("error writing http response", "err", err)
+	}
+}
+
+// writeEvent writes a record as a single Server-Sent Event.
+func writeEvent(w http.ResponseWriter, rec stream.Record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", rec.Type, data)
+	return err
+}
+
+// subscribe registers a new SSE subscriber.
+func (s *Server) subscribe() chan stream.Record {
+	ch := make(chan stream.Record, subscriberBufferSize)
+	s.mu.Lock()
+	s.subscribers[ch] = struct{}{}
+	s.mu.Unlock()
+	return ch
+}
+
+// unsubscribe removes an SSE subscriber.
+func (s *Server) unsubscribe(ch chan stream.Record) {
+	s.mu.Lock()
+	delete(s.subscribers, ch)
+	s.mu.Unlock()
+}
+
+// publish sends the record to every subscriber without blocking; the record is dropped for any
+// subscriber whose buffer is full.
+func (s *Server) publish(rec stream.Record) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for ch := range s.subscribers {
+		select {
+		case ch <- rec:
+		default:
+			s.logger.Warn("dropping event for slow subscriber", "type", rec.Type)
+		}
+	}
+}
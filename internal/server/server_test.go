@@ -0,0 +1,126 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bcdxn/f1cli/domain"
+	"github.com/bcdxn/f1cli/f1livetiming"
+)
+
+func TestServer(t *testing.T) {
+	s := New(WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	m := domain.NewMeeting()
+	m.Name = "Abu Dhabi Grand Prix"
+	s.SetMeeting(m)
+	s.SetDrivers(map[string]domain.Driver{"44": domain.NewDriver("44")})
+	s.AddRaceCtrlMsg(domain.RaceCtrlMsg{Body: "PIT EXIT OPEN"})
+	s.AddRaceCtrlMsg(domain.RaceCtrlMsg{Body: "GREEN LIGHT"})
+
+	t.Run("Meeting", func(t *testing.T) {
+		var meeting domain.Meeting
+		getJSON(t, ts.URL+"/meeting", &meeting)
+		if meeting.Name != "Abu Dhabi Grand Prix" {
+			t.Errorf("expected name '%s' but found '%s'", "Abu Dhabi Grand Prix", meeting.Name)
+		}
+	})
+
+	t.Run("Drivers", func(t *testing.T) {
+		var drivers map[string]domain.Driver
+		getJSON(t, ts.URL+"/drivers", &drivers)
+		if _, ok := drivers["44"]; !ok || len(drivers) != 1 {
+			t.Errorf("expected driver '%s' but found %v", "44", drivers)
+		}
+	})
+
+	t.Run("RaceControl", func(t *testing.T) {
+		var msgs []domain.RaceCtrlMsg
+		getJSON(t, ts.URL+"/race-control", &msgs)
+		if len(msgs) != 2 || msgs[1].Body != "GREEN LIGHT" {
+			t.Errorf("expected %d race control messages but found %v", 2, msgs)
+		}
+	})
+
+	t.Run("Events", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/events", nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		defer resp.Body.Close()
+		if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+			t.Errorf("expected content type '%s' but found '%s'", "text/event-stream", ct)
+		}
+
+		events := make([]string, 0)
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() && len(events) < 3 {
+			if name, ok := strings.CutPrefix(scanner.Text(), "event: "); ok {
+				events = append(events, name)
+				// publish a new message once the initial snapshots have been received
+				if len(events) == 2 {
+					s.AddRaceCtrlMsg(domain.RaceCtrlMsg{Body: "SAFETY CAR DEPLOYED"})
+				}
+			}
+		}
+		expected := []string{"meeting", "drivers", "race_control"}
+		if strings.Join(events, ",") != strings.Join(expected, ",") {
+			t.Errorf("expected events %v but found %v", expected, events)
+		}
+	})
+}
+
+func TestSnapshotSource(t *testing.T) {
+	snapshot := f1livetiming.Snapshot{
+		Meeting:      domain.NewMeeting(),
+		Drivers:      map[string]domain.Driver{"44": domain.NewDriver("44")},
+		RaceCtrlMsgs: []domain.RaceCtrlMsg{{Body: "PIT EXIT OPEN"}, {Body: "GREEN LIGHT"}},
+	}
+	s := New(
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		WithSnapshotSource(func() f1livetiming.Snapshot { return snapshot }),
+	)
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+	// only the latest message reached the server but the full history is served from the source
+	s.AddRaceCtrlMsg(domain.RaceCtrlMsg{Body: "GREEN LIGHT"})
+
+	var msgs []domain.RaceCtrlMsg
+	getJSON(t, ts.URL+"/race-control", &msgs)
+	if len(msgs) != 2 || msgs[0].Body != "PIT EXIT OPEN" {
+		t.Errorf("expected %d race control messages but found %v", 2, msgs)
+	}
+	var drivers map[string]domain.Driver
+	getJSON(t, ts.URL+"/drivers", &drivers)
+	if _, ok := drivers["44"]; !ok || len(drivers) != 1 {
+		t.Errorf("expected driver '%s' but found %v", "44", drivers)
+	}
+}
+
+func getJSON(t *testing.T, url string, v any) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d but found %d", http.StatusOK, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
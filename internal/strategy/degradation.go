@@ -0,0 +1,288 @@
+package strategy
+
+import (
+	"sort"
+
+	"github.com/bcdxn/f1cli/domain"
+)
+
+const (
+	// fuelEffectPerLap is the approximate lap time (in seconds) gained on each lap as fuel is burned;
+	// lap times are corrected for it so that degradation isn't masked by the car getting lighter.
+	fuelEffectPerLap = 0.06
+	// cleanLapThreshold is the ratio to the fastest lap in a stint above which a lap is considered
+	// compromised (traffic, mistakes, etc.) and excluded from the model.
+	cleanLapThreshold = 1.07
+	// minCleanLaps is the minimum number of clean laps in a stint required to fit a degradation rate.
+	minCleanLaps = 4
+)
+
+// Lap is a single completed lap recorded for a driver.
+type Lap struct {
+	Number        int                 // Number is the lap number as counted by the driver
+	Time          float64             // Time is the lap time in seconds
+	Stint         int                 // Stint is the 0-based index of the stint that the lap was completed on
+	Compound      domain.TireCompound // Compound is the tire compound the lap was completed on
+	TireAge       int                 // TireAge is the number of laps on the tires at the end of the lap
+	IsPitIn       bool                // IsPitIn indicates the driver entered the pit at the end of the lap
+	IsPitOut      bool                // IsPitOut indicates the lap was an outlap
+	IsNeutralised bool                // IsNeutralised indicates the lap was (partially) completed under a SC, VSC or red flag
+}
+
+// IsClean indicates if the lap is representative of race pace, i.e. not the opening lap, an inlap,
+// an outlap or a lap completed under neutralised conditions.
+func (l Lap) IsClean() bool {
+	return l.Number > 1 && !l.IsPitIn && !l.IsPitOut && !l.IsNeutralised
+}
+
+// NewLapHistory returns a new, empty lap history.
+func NewLapHistory() *LapHistory {
+	return &LapHistory{drivers: make(map[string]*driverLaps)}
+}
+
+// LapHistory records the laps completed by each driver from successive drivers snapshots since the
+// F1 LiveTiming API only publishes the latest lap time.
+type LapHistory struct {
+	drivers map[string]*driverLaps
+}
+
+// driverLaps contains the recorded laps of a single driver along with the conditions observed
+// during the lap currently in progress.
+type driverLaps struct {
+	laps        []Lap
+	lapCount    int
+	stint       int
+	compound    domain.TireCompound
+	tireAge     int
+	pitIn       bool
+	pitOut      bool
+	neutralised bool
+}
+
+// Observe updates the lap history with the latest drivers snapshot and the track status of the
+// meeting; a lap is recorded each time a driver's lap count increases.
+func (h *LapHistory) Observe(drivers map[string]domain.Driver, m domain.Meeting) {
+	neutralised := m.Session.TrackStatus.IsNeutralised()
+
+	for number, d := range drivers {
+		dl, ok := h.drivers[number]
+		if !ok {
+			dl = &driverLaps{
+				lapCount: d.TimingData.NumberOfLaps,
+				compound: d.TimingData.TireCompound,
+				tireAge:  d.TimingData.TireLapCount,
+			}
+			h.drivers[number] = dl
+		}
+		// a new stint starts when the tires are changed
+		if d.TimingData.TireCompound != dl.compound || d.TimingData.TireLapCount < dl.tireAge {
+			if dl.compound != domain.TireCompoundUnknown && len(dl.laps) > 0 {
+				dl.stint++
+			}
+			dl.compound = d.TimingData.TireCompound
+		}
+		dl.tireAge = d.TimingData.TireLapCount
+		dl.pitIn = dl.pitIn || d.TimingData.IsInPit
+		dl.pitOut = dl.pitOut || d.TimingData.IsPitOut
+		dl.neutralised = dl.neutralised || neutralised
+
+		if d.TimingData.NumberOfLaps <= dl.lapCount {
+			continue
+		}
+		dl.lapCount = d.TimingData.NumberOfLaps
+		if t, ok := ParseLapTime(d.TimingData.LastLap.Time); ok {
+			lap := Lap{
+				Number:        d.TimingData.NumberOfLaps,
+				Time:          t,
+				Stint:         dl.stint,
+				Compound:      dl.compound,
+				TireAge:       dl.tireAge,
+				IsPitIn:       dl.pitIn,
+				IsPitOut:      dl.pitOut,
+				IsNeutralised: dl.neutralised,
+			}
+			// the tires have already been changed by the time the inlap is completed
+			if lap.IsPitIn && len(dl.laps) > 0 {
+				prev := dl.laps[len(dl.laps)-1]
+				lap.Stint, lap.Compound, lap.TireAge = prev.Stint, prev.Compound, prev.TireAge+1
+			}
+			dl.laps = append(dl.laps, lap)
+		}
+		// conditions carry over into the next lap only while they are still ongoing
+		dl.pitIn = d.TimingData.IsInPit
+		dl.pitOut = d.TimingData.IsPitOut
+		dl.neutralised = neutralised
+	}
+}
+
+// Laps returns the laps recorded for the given driver in the order they were completed.
+func (h *LapHistory) Laps(number string) []Lap {
+	if dl, ok := h.drivers[number]; ok {
+		return dl.laps
+	}
+	return nil
+}
+
+// stints returns the laps recorded for the given driver grouped by stint.
+func (h *LapHistory) stints(number string) map[int][]Lap {
+	byStint := make(map[int][]Lap)
+	for _, l := range h.Laps(number) {
+		byStint[l.Stint] = append(byStint[l.Stint], l)
+	}
+	return byStint
+}
+
+// StintPace is the pace and degradation of a single stint fitted on its clean laps.
+type StintPace struct {
+	Driver      string              // Driver is the number of the driver that completed the stint
+	Stint       int                 // Stint is the 0-based index of the stint
+	Compound    domain.TireCompound // Compound is the tire compound used in the stint
+	Laps        int                 // Laps is the number of laps recorded in the stint
+	CleanLaps   int                 // CleanLaps is the number of laps the model was fitted on
+	Pace        float64             // Pace is the mean fuel-corrected clean lap time in seconds
+	Degradation float64             // Degradation is the time lost per lap of tire age in seconds
+	HasFit      bool                // HasFit indicates there were enough clean laps to fit the degradation
+}
+
+// FitStints fits the pace and degradation of each of the driver's stints recorded in the history.
+// Degradation is the slope of a least-squares fit of fuel-corrected lap time against tire age.
+func FitStints(h *LapHistory, number string) []StintPace {
+	byStint := h.stints(number)
+	stints := make([]StintPace, 0, len(byStint))
+	for stint, laps := range byStint {
+		sp := StintPace{
+			Driver:   number,
+			Stint:    stint,
+			Compound: laps[len(laps)-1].Compound,
+			Laps:     len(laps),
+		}
+		clean := cleanLaps(laps)
+		sp.CleanLaps = len(clean)
+		if len(clean) > 0 {
+			ages := make([]float64, 0, len(clean))
+			times := make([]float64, 0, len(clean))
+			for _, l := range clean {
+				ages = append(ages, float64(l.TireAge))
+				times = append(times, fuelCorrected(l))
+			}
+			sp.Pace = mean(times)
+			if len(clean) >= minCleanLaps {
+				sp.Degradation, sp.HasFit = slope(ages, times)
+			}
+		}
+		stints = append(stints, sp)
+	}
+
+	sort.Slice(stints, func(i, j int) bool { return stints[i].Stint < stints[j].Stint })
+	return stints
+}
+
+// CompoundPace compares the pace and degradation of a tire compound across the field.
+type CompoundPace struct {
+	Compound    domain.TireCompound // Compound is the tire compound being compared
+	Stints      int                 // Stints is the number of stints that contributed to the degradation
+	CleanLaps   int                 // CleanLaps is the number of clean laps completed on the compound
+	Delta       float64             // Delta is the pace deficit (in seconds per lap) to the fastest compound
+	Degradation float64             // Degradation is the mean time lost per lap of tire age in seconds
+}
+
+// CompareCompounds compares the pace of each compound used in the session. To remove the
+// difference in car performance, each clean lap is compared with the median pace of the driver
+// that completed it; the compound with the smallest median deficit is the fastest.
+func CompareCompounds(h *LapHistory) []CompoundPace {
+	residuals := make(map[domain.TireCompound][]float64)
+	degradation := make(map[domain.TireCompound][]float64)
+
+	for number := range h.drivers {
+		for _, sp := range FitStints(h, number) {
+			if sp.HasFit {
+				degradation[sp.Compound] = append(degradation[sp.Compound], sp.Degradation)
+			}
+		}
+		var all []Lap
+		for _, laps := range h.stints(number) {
+			all = append(all, cleanLaps(laps)...)
+		}
+		if len(all) == 0 {
+			continue
+		}
+		times := make([]float64, 0, len(all))
+		for _, l := range all {
+			times = append(times, fuelCorrected(l))
+		}
+		baseline := median(times)
+		for _, l := range all {
+			residuals[l.Compound] = append(residuals[l.Compound], fuelCorrected(l)-baseline)
+		}
+	}
+
+	compounds := make([]CompoundPace, 0, len(residuals))
+	for compound, r := range residuals {
+		cp := CompoundPace{
+			Compound:  compound,
+			Stints:    len(degradation[compound]),
+			CleanLaps: len(r),
+			Delta:     median(r),
+		}
+		if len(degradation[compound]) > 0 {
+			cp.Degradation = mean(degradation[compound])
+		}
+		compounds = append(compounds, cp)
+	}
+	if len(compounds) == 0 {
+		return compounds
+	}
+
+	sort.Slice(compounds, func(i, j int) bool { return compounds[i].Delta < compounds[j].Delta })
+	fastest := compounds[0].Delta
+	for i := range compounds {
+		compounds[i].Delta -= fastest
+	}
+	return compounds
+}
+
+// cleanLaps returns the laps of a stint that are representative of race pace.
+func cleanLaps(laps []Lap) []Lap {
+	fastest := 0.0
+	for _, l := range laps {
+		if l.IsClean() && (fastest == 0 || l.Time < fastest) {
+			fastest = l.Time
+		}
+	}
+	clean := make([]Lap, 0, len(laps))
+	for _, l := range laps {
+		if l.IsClean() && l.Time <= fastest*cleanLapThreshold {
+			clean = append(clean, l)
+		}
+	}
+	return clean
+}
+
+// fuelCorrected returns the lap time corrected to the fuel load at the start of the race.
+func fuelCorrected(l Lap) float64 {
+	return l.Time + float64(l.Number)*fuelEffectPerLap
+}
+
+// slope returns the slope of the least-squares line fitted through the given points; ok is false if
+// the slope is undefined, i.e. all x values are equal.
+func slope(xs, ys []float64) (float64, bool) {
+	mx, my := mean(xs), mean(ys)
+	var num, den float64
+	for i := range xs {
+		num += (xs[i] - mx) * (ys[i] - my)
+		den += (xs[i] - mx) * (xs[i] - mx)
+	}
+	if den == 0 {
+		return 0, false
+	}
+	return num / den, true
+}
+
+// mean returns the arithmetic mean of the given values.
+func mean(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
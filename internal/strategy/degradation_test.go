@@ -0,0 +1,77 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/bcdxn/f1cli/domain"
+)
+
+func TestLapHistory(t *testing.T) {
+	h := NewLapHistory()
+	m := domain.NewMeeting()
+	m.Session.TrackStatus = domain.TrackStatusClear
+
+	d := domain.NewDriver("1")
+	d.TimingData.TireCompound = domain.TireCompoundMedium
+	h.Observe(map[string]domain.Driver{"1": d}, m)
+	// medium stint losing 0.1s per lap of tire age (before fuel correction) with a VSC on lap 6
+	for lap := 1; lap <= 12; lap++ {
+		if lap == 6 {
+			// VSC deployed and ended mid-lap
+			m.Session.TrackStatus = domain.TrackStatusVSC
+			h.Observe(map[string]domain.Driver{"1": d}, m)
+			m.Session.TrackStatus = domain.TrackStatusClear
+		}
+		d.TimingData.IsInPit = lap == 12
+		d.TimingData.NumberOfLaps = lap
+		d.TimingData.TireLapCount = lap
+		d.TimingData.LastLap.Time = lapTime(90 + 0.1*float64(lap) - fuelEffectPerLap*float64(lap))
+		h.Observe(map[string]domain.Driver{"1": d}, m)
+	}
+	// hard stint after the stop
+	d.TimingData.TireCompound = domain.TireCompoundHard
+	for lap := 13; lap <= 20; lap++ {
+		d.TimingData.IsInPit = false
+		d.TimingData.IsPitOut = lap == 13
+		d.TimingData.NumberOfLaps = lap
+		d.TimingData.TireLapCount = lap - 12
+		d.TimingData.LastLap.Time = lapTime(91 + 0.05*float64(lap-12) - fuelEffectPerLap*float64(lap))
+		h.Observe(map[string]domain.Driver{"1": d}, m)
+	}
+
+	laps := h.Laps("1")
+	if len(laps) != 20 {
+		t.Fatalf("expected %d laps but found %d", 20, len(laps))
+	}
+	if !laps[5].IsNeutralised || laps[6].IsNeutralised {
+		t.Errorf("expected only lap 6 to be neutralised")
+	}
+	if !laps[11].IsPitIn || laps[11].Compound != domain.TireCompoundMedium {
+		t.Errorf("expected lap 12 to be a medium inlap but found '%s' (pit in: %t)", laps[11].Compound, laps[11].IsPitIn)
+	}
+
+	stints := FitStints(h, "1")
+	if len(stints) != 2 {
+		t.Fatalf("expected %d stints but found %d", 2, len(stints))
+	}
+	if stints[0].CleanLaps != 9 {
+		t.Errorf("expected %d clean laps but found %d", 9, stints[0].CleanLaps)
+	}
+	if !stints[0].HasFit || math.Abs(stints[0].Degradation-0.1) > 0.001 {
+		t.Errorf("expected degradation %.3f but found %.3f", 0.1, stints[0].Degradation)
+	}
+	if stints[1].Compound != domain.TireCompoundHard || math.Abs(stints[1].Degradation-0.05) > 0.001 {
+		t.Errorf("expected hard degradation %.3f but found '%s' %.3f", 0.05, stints[1].Compound, stints[1].Degradation)
+	}
+
+	compounds := CompareCompounds(h)
+	if len(compounds) != 2 || compounds[0].Compound != domain.TireCompoundMedium {
+		t.Errorf("expected medium to be the fastest compound but found %v", compounds)
+	}
+}
+
+func lapTime(seconds float64) string {
+	return fmt.Sprintf("%d:%06.3f", int(seconds)/60, math.Mod(seconds, 60))
+}
@@ -0,0 +1,55 @@
+package strategy
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	leaderGapRe = regexp.MustCompile(`^LAP\s*\d+$`)
+	lappedGapRe = regexp.MustCompile(`^(\d+)\s*L$`)
+)
+
+// ParseGap converts a gap as reported by the F1 LiveTiming API (e.g. "+4.625", "1L", "LAP 12") to
+// a number of seconds. The leader's gap ("LAP n" or empty for position 1) is reported as zero. The
+// number of laps is returned for lapped cars since the time gap is not published for them; ok is
+// false when the gap could not be interpreted.
+func ParseGap(gap string) (seconds float64, laps int, ok bool) {
+	gap = strings.TrimSpace(gap)
+	if leaderGapRe.MatchString(gap) {
+		return 0, 0, true
+	}
+	if m := lappedGapRe.FindStringSubmatch(gap); m != nil {
+		laps, _ = strconv.Atoi(m[1])
+		return 0, laps, true
+	}
+	seconds, err := strconv.ParseFloat(strings.TrimPrefix(gap, "+"), 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return seconds, 0, true
+}
+
+// ParseLapTime converts a lap time as reported by the F1 LiveTiming API (e.g. "1:26.241") to a
+// number of seconds; ok is false when the lap time could not be interpreted.
+func ParseLapTime(lapTime string) (seconds float64, ok bool) {
+	lapTime = strings.TrimSpace(lapTime)
+	if lapTime == "" {
+		return 0, false
+	}
+	minutes := 0
+	if i := strings.Index(lapTime, ":"); i >= 0 {
+		m, err := strconv.Atoi(lapTime[:i])
+		if err != nil {
+			return 0, false
+		}
+		minutes = m
+		lapTime = lapTime[i+1:]
+	}
+	s, err := strconv.ParseFloat(lapTime, 64)
+	if err != nil {
+		return 0, false
+	}
+	return float64(minutes)*60 + s, true
+}
@@ -0,0 +1,230 @@
+package strategy
+
+import (
+	"math"
+	"sort"
+
+	"github.com/bcdxn/f1cli/domain"
+)
+
+const (
+	PitLossSourceConfigured PitLossSource = "CONFIGURED"
+	PitLossSourceObserved   PitLossSource = "OBSERVED"
+	PitLossSourceCircuit    PitLossSource = "CIRCUIT"
+	PitLossSourceDefault    PitLossSource = "DEFAULT"
+)
+
+const (
+	// defaultPitLoss is the time lost (in seconds) making a green-flag pit stop at a circuit we don't
+	// have a specific value for.
+	defaultPitLoss = 22.0
+	// minObservedPitLoss and maxObservedPitLoss bound the observed pit stops that are used to learn
+	// the pit loss; anything outside of these bounds is most likely a stop under neutralised
+	// conditions, a penalty or a garage stop and would skew the estimate.
+	minObservedPitLoss = 10.0
+	maxObservedPitLoss = 45.0
+)
+
+// circuitPitLoss contains the approximate time lost (in seconds) making a green-flag pit stop at
+// each circuit keyed by the circuit short name used by the F1 LiveTiming API.
+var circuitPitLoss = map[string]float64{
+	"Austin":             20.0,
+	"Baku":               20.5,
+	"Catalunya":          22.0,
+	"Hungaroring":        21.0,
+	"Imola":              28.0,
+	"Interlagos":         21.0,
+	"Jeddah":             20.0,
+	"Las Vegas":          21.0,
+	"Lusail":             26.0,
+	"Melbourne":          19.0,
+	"Mexico City":        22.0,
+	"Miami":              20.5,
+	"Monte Carlo":        20.0,
+	"Montreal":           18.5,
+	"Monza":              24.0,
+	"Sakhir":             23.0,
+	"Shanghai":           22.5,
+	"Silverstone":        20.5,
+	"Singapore":          28.0,
+	"Spa-Francorchamps":  21.0,
+	"Spielberg":          20.5,
+	"Suzuka":             22.5,
+	"Yas Marina Circuit": 22.0,
+	"Zandvoort":          21.5,
+}
+
+// PitLossSource indicates where the pit loss used in a prediction came from.
+type PitLossSource string
+
+// NewPitLoss returns a new pit loss estimator. A configured pit loss greater than zero (in seconds)
+// always takes precedence over observed pit stops and circuit defaults.
+func NewPitLoss(configured float64) *PitLoss {
+	return &PitLoss{
+		configured: configured,
+		drivers:    make(map[string]pitObservation),
+	}
+}
+
+// PitLoss estimates the time lost making a pit stop. Unless explicitly configured, the estimate is
+// learned from the pit stops observed during the session and falls back to a per-circuit default
+// until the first stop has been made.
+type PitLoss struct {
+	configured float64
+	observed   []float64
+	drivers    map[string]pitObservation
+}
+
+// pitObservation tracks the progress of a single driver through the pit lane.
+type pitObservation struct {
+	gapBefore    float64 // gapBefore is the last known gap to the leader before entering the pit
+	hasGapBefore bool    // hasGapBefore indicates that gapBefore is valid
+	inPitLane    bool    // inPitLane indicates that the driver is currently in the pit or on an outlap
+}
+
+// Observe updates the estimator with the latest drivers snapshot. Pit stops are measured as the
+// increase in the gap to the leader between pit entry and the first timing update after the outlap.
+func (p *PitLoss) Observe(drivers map[string]domain.Driver) {
+	for number, d := range drivers {
+		obs := p.drivers[number]
+		gap, laps, ok := ParseGap(d.TimingData.LeaderGap)
+		validGap := ok && laps == 0 && d.TimingData.Position > 1 && !d.TimingData.IsRetired
+
+		switch {
+		case d.TimingData.IsInPit || d.TimingData.IsPitOut:
+			obs.inPitLane = true
+		case obs.inPitLane:
+			// first timing update after leaving the pit lane
+			if validGap && obs.hasGapBefore {
+				if loss := gap - obs.gapBefore; loss >= minObservedPitLoss && loss <= maxObservedPitLoss {
+					p.observed = append(p.observed, loss)
+				}
+			}
+			obs = pitObservation{gapBefore: gap, hasGapBefore: validGap}
+		default:
+			obs.gapBefore, obs.hasGapBefore = gap, validGap
+		}
+
+		p.drivers[number] = obs
+	}
+}
+
+// Estimate returns the time lost (in seconds) making a pit stop in the given meeting along with the
+// source of the estimate.
+func (p *PitLoss) Estimate(m domain.Meeting) (float64, PitLossSource) {
+	if p.configured > 0 {
+		return p.configured, PitLossSourceConfigured
+	}
+	if len(p.observed) > 0 {
+		return median(p.observed), PitLossSourceObserved
+	}
+	if loss, ok := circuitPitLoss[m.CircuitShortName]; ok {
+		return loss, PitLossSourceCircuit
+	}
+	return defaultPitLoss, PitLossSourceDefault
+}
+
+// Observed returns the number of pit stops the estimate has been learned from.
+func (p *PitLoss) Observed() int {
+	return len(p.observed)
+}
+
+// Rejoin is the predicted outcome of a driver making a pit stop on the current lap.
+type Rejoin struct {
+	Position  int     // Position is the position the driver would rejoin the race in
+	Ahead     string  // Ahead is the number of the driver that would be directly ahead after rejoining
+	GapAhead  float64 // GapAhead is the gap (in seconds) to the driver ahead after rejoining
+	Behind    string  // Behind is the number of the driver that would be directly behind after rejoining
+	GapBehind float64 // GapBehind is the gap (in seconds) to the driver behind after rejoining
+}
+
+// PredictRejoin estimates where each running driver would rejoin the race if they were to pit now
+// and who they would be fighting with, given the time lost making a pit stop (in seconds). Drivers
+// that are retired, already in the pit lane or whose gap to the leader is unknown are omitted.
+func PredictRejoin(drivers map[string]domain.Driver, pitLoss float64) map[string]Rejoin {
+	gaps := raceGaps(drivers)
+	rejoins := make(map[string]Rejoin, len(gaps))
+
+	for _, car := range gaps {
+		d := drivers[car.number]
+		if d.TimingData.IsInPit || d.TimingData.IsPitOut || math.IsInf(car.gap, 1) {
+			continue
+		}
+		rejoinGap := car.gap + pitLoss
+		r := Rejoin{Position: 1}
+		for _, other := range gaps {
+			if other.number == car.number {
+				continue
+			}
+			if other.gap < rejoinGap {
+				// gaps are sorted so the last car found ahead is directly ahead
+				r.Position++
+				r.Ahead = other.number
+				r.GapAhead = rejoinGap - other.gap
+			} else if r.Behind == "" {
+				r.Behind = other.number
+				r.GapBehind = other.gap - rejoinGap
+			}
+		}
+		rejoins[car.number] = r
+	}
+
+	return rejoins
+}
+
+// carGap is the gap (in seconds) between a driver and the leader.
+type carGap struct {
+	number string
+	gap    float64
+}
+
+// raceGaps returns the gap to the leader for each running driver sorted from the leader backwards.
+// Lapped drivers are approximated using the leader's last lap time or placed at the back of the
+// field if it is unknown.
+func raceGaps(drivers map[string]domain.Driver) []carGap {
+	lapTime := math.Inf(1)
+	for _, d := range drivers {
+		if d.TimingData.Position == 1 {
+			if t, ok := ParseLapTime(d.TimingData.LastLap.Time); ok {
+				lapTime = t
+			}
+		}
+	}
+
+	gaps := make([]carGap, 0, len(drivers))
+	for number, d := range drivers {
+		if d.TimingData.IsRetired || !d.TimingData.ShowPosition || d.TimingData.Position == 0 {
+			continue
+		}
+		gap, laps, ok := ParseGap(d.TimingData.LeaderGap)
+		switch {
+		case d.TimingData.Position == 1:
+			gap = 0
+		case !ok:
+			gap = math.Inf(1)
+		case laps > 0:
+			gap = float64(laps) * lapTime
+		}
+		gaps = append(gaps, carGap{number: number, gap: gap})
+	}
+
+	sort.SliceStable(gaps, func(i, j int) bool {
+		if gaps[i].gap == gaps[j].gap {
+			return drivers[gaps[i].number].TimingData.Position < drivers[gaps[j].number].TimingData.Position
+		}
+		return gaps[i].gap < gaps[j].gap
+	})
+
+	return gaps
+}
+
+// median returns the median of the given values.
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,87 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/bcdxn/f1cli/domain"
+)
+
+func TestPredictRejoin(t *testing.T) {
+	drivers := map[string]domain.Driver{
+		"4":  testDriver("4", 1, "LAP 20"),
+		"81": testDriver("81", 2, "+3.100"),
+		"55": testDriver("55", 3, "+12.400"),
+		"1":  testDriver("1", 4, "+25.000"),
+		"16": testDriver("16", 5, "+30.500"),
+	}
+
+	rejoins := PredictRejoin(drivers, 22)
+
+	r := rejoins["81"]
+	if r.Position != 4 {
+		t.Errorf("expected rejoin position %d but found %d", 4, r.Position)
+	}
+	if r.Ahead != "1" || r.Behind != "16" {
+		t.Errorf("expected to rejoin between '%s' and '%s' but found '%s' and '%s'", "1", "16", r.Ahead, r.Behind)
+	}
+	if r.GapAhead < 0.09 || r.GapAhead > 0.11 {
+		t.Errorf("expected gap ahead %.3f but found %.3f", 0.1, r.GapAhead)
+	}
+
+	r = rejoins["4"]
+	if r.Position != 3 || r.Ahead != "55" || r.Behind != "1" {
+		t.Errorf("expected leader to rejoin P3 between '55' and '1' but found P%d between '%s' and '%s'", r.Position, r.Ahead, r.Behind)
+	}
+
+	r = rejoins["16"]
+	if r.Position != 5 || r.Behind != "" {
+		t.Errorf("expected last car to rejoin P5 with nobody behind but found P%d ahead of '%s'", r.Position, r.Behind)
+	}
+}
+
+func TestPitLoss(t *testing.T) {
+	m := domain.NewMeeting()
+	m.CircuitShortName = "Monza"
+
+	t.Run("Circuit", func(t *testing.T) {
+		p := NewPitLoss(0)
+		loss, src := p.Estimate(m)
+		if loss != 24 || src != PitLossSourceCircuit {
+			t.Errorf("expected pit loss %.1f from '%s' but found %.1f from '%s'", 24.0, PitLossSourceCircuit, loss, src)
+		}
+	})
+
+	t.Run("Configured", func(t *testing.T) {
+		p := NewPitLoss(19.5)
+		loss, src := p.Estimate(m)
+		if loss != 19.5 || src != PitLossSourceConfigured {
+			t.Errorf("expected pit loss %.1f from '%s' but found %.1f from '%s'", 19.5, PitLossSourceConfigured, loss, src)
+		}
+	})
+
+	t.Run("Observed", func(t *testing.T) {
+		p := NewPitLoss(0)
+		d := testDriver("44", 3, "+5.000")
+		p.Observe(map[string]domain.Driver{"44": d})
+		d.TimingData.IsInPit = true
+		p.Observe(map[string]domain.Driver{"44": d})
+		d.TimingData.IsInPit = false
+		d.TimingData.IsPitOut = true
+		p.Observe(map[string]domain.Driver{"44": d})
+		d.TimingData.IsPitOut = false
+		d.TimingData.LeaderGap = "+26.000"
+		p.Observe(map[string]domain.Driver{"44": d})
+
+		loss, src := p.Estimate(m)
+		if loss != 21 || src != PitLossSourceObserved {
+			t.Errorf("expected pit loss %.1f from '%s' but found %.1f from '%s'", 21.0, PitLossSourceObserved, loss, src)
+		}
+	})
+}
+
+func testDriver(number string, position int, leaderGap string) domain.Driver {
+	d := domain.NewDriver(number)
+	d.TimingData.Position = position
+	d.TimingData.LeaderGap = leaderGap
+	return d
+}
@@ -0,0 +1,61 @@
+package stream
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// SchemaVersion is the version of the record schema written by the encoder; it is incremented
+// whenever a backwards incompatible change is made to the records or the domain models they carry.
+const SchemaVersion = 2
+
+const (
+	RecordTypeMeeting     RecordType = "meeting"
+	RecordTypeDrivers     RecordType = "drivers"
+	RecordTypeRaceControl RecordType = "race_control"
+)
+
+// RecordType identifies the kind of data carried by a record.
+type RecordType string
+
+// Record is a single line of the newline-delimited JSON stream.
+type Record struct {
+	Version int        `json:"v"`    // Version is the schema version of the record
+	Type    RecordType `json:"type"` // Type identifies the kind of data carried by the record
+	Time    time.Time  `json:"time"` // Time is when the record was emitted
+	Data    any        `json:"data"` // Data is a full snapshot of the meeting, drivers or a race control message
+}
+
+// NewRecord returns a new record of the given type carrying data emitted at the given time.
+func NewRecord(t RecordType, data any, at time.Time) Record {
+	return Record{
+		Version: SchemaVersion,
+		Type:    t,
+		Time:    at.UTC(),
+		Data:    data,
+	}
+}
+
+// NewEncoder returns a new encoder that writes records as newline-delimited JSON to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{
+		enc: json.NewEncoder(w),
+		now: time.Now,
+	}
+}
+
+// Encoder writes records as newline-delimited JSON; it is safe for concurrent use.
+type Encoder struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	now func() time.Time
+}
+
+// Encode writes a single record of the given type carrying data followed by a newline.
+func (e *Encoder) Encode(t RecordType, data any) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.enc.Encode(NewRecord(t, data, e.now()))
+}
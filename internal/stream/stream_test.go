@@ -0,0 +1,66 @@
+package stream
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/bcdxn/f1cli/domain"
+)
+
+func TestEncode(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	enc.now = func() time.Time { return time.Date(2024, 12, 8, 13, 3, 55, 0, time.UTC) }
+
+	d := domain.NewDriver("44")
+	d.TimingData.Position = 3
+	if err := enc.Encode(RecordTypeDrivers, map[string]domain.Driver{"44": d}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := enc.Encode(RecordTypeMeeting, domain.NewMeeting()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("expected %d lines but found %d", 2, len(lines))
+	}
+
+	var drivers struct {
+		Version int    `json:"v"`
+		Type    string `json:"type"`
+		Time    string `json:"time"`
+		Data    map[string]struct {
+			Number     string `json:"number"`
+			TimingData struct {
+				Position int `json:"position"`
+			} `json:"timing_data"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(lines[0], &drivers); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if drivers.Version != SchemaVersion || drivers.Type != string(RecordTypeDrivers) || drivers.Time != "2024-12-08T13:03:55Z" {
+		t.Errorf("unexpected record envelope: %s", lines[0])
+	}
+	if drivers.Data["44"].Number != "44" || drivers.Data["44"].TimingData.Position != 3 {
+		t.Errorf("unexpected driver data: %s", lines[0])
+	}
+
+	var meeting struct {
+		Type string `json:"type"`
+		Data struct {
+			Session struct {
+				Status string `json:"status"`
+			} `json:"session"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(lines[1], &meeting); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if meeting.Type != string(RecordTypeMeeting) || meeting.Data.Session.Status != string(domain.SessionStatusPending) {
+		t.Errorf("unexpected meeting record: %s", lines[1])
+	}
+}
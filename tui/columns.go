@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"slices"
+
+	"github.com/bcdxn/f1cli/domain"
+	"github.com/bcdxn/f1cli/internal/strategy"
+	"github.com/bcdxn/f1cli/tui/styles"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	ColumnPosition  = "position"
+	ColumnDriver    = "driver"
+	ColumnInterval  = "interval"
+	ColumnLeader    = "leader"
+	ColumnLastLap   = "last_lap"
+	ColumnSectors   = "sectors"
+	ColumnTire      = "tire"
+	ColumnBestLap   = "best_lap"
+	ColumnQ1        = "q1"
+	ColumnQ2        = "q2"
+	ColumnQ3        = "q3"
+	ColumnPitRejoin = "pit_rejoin"
+)
+
+var (
+	// DefaultRaceColumns are the columns of the timing table shown during races, in order.
+	DefaultRaceColumns = []string{ColumnPosition, ColumnDriver, ColumnInterval, ColumnLeader, ColumnLastLap, ColumnSectors, ColumnTire, ColumnBestLap}
+	// DefaultQualifyingColumns are the columns of the timing table shown during qualifying, in order.
+	DefaultQualifyingColumns = []string{ColumnPosition, ColumnDriver, ColumnInterval, ColumnLeader, ColumnSectors, ColumnQ1, ColumnQ2, ColumnQ3}
+)
+
+// column is a single column of the timing table.
+type column struct {
+	header     string
+	alignRight bool
+	priority   int // priority orders the columns dropped when the table is too wide; 0 is never dropped
+	render     func(d domain.Driver, t tableData) string
+}
+
+// tableData is the state shared by every row of the timing table.
+type tableData struct {
+	meeting  domain.Meeting
+	drivers  map[string]domain.Driver
+	selected string
+	rejoins  map[string]strategy.Rejoin
+	styles   *styles.Style
+}
+
+// columns is the registry of every column that can be shown on the timing table keyed by name.
+var columns = map[string]column{
+	ColumnPosition: {header: "POS", alignRight: true, render: func(d domain.Driver, t tableData) string {
+		return driverPosition(t.styles, d, t.selected)
+	}},
+	ColumnDriver: {header: "DRIVER", render: func(d domain.Driver, t tableData) string {
+		return driverName(t.styles, d, t.meeting)
+	}},
+	ColumnInterval: {header: "INT", priority: 10, render: func(d domain.Driver, t tableData) string {
+		return driverIntervalGap(t.styles, d)
+	}},
+	ColumnLeader: {header: "LEADER", priority: 8, render: func(d domain.Driver, t tableData) string {
+		return driverLeaderGap(d)
+	}},
+	ColumnLastLap: {header: "LAST", priority: 9, render: func(d domain.Driver, t tableData) string {
+		return driverLastLap(t.styles, d, t.meeting)
+	}},
+	ColumnSectors: {header: "MINI SECTORS", priority: 3, render: func(d domain.Driver, t tableData) string {
+		return driverSectors(t.styles, d, t.meeting)
+	}},
+	ColumnTire: {header: "TIRE", priority: 7, render: func(d domain.Driver, t tableData) string {
+		return driverStint(t.styles, d)
+	}},
+	ColumnBestLap: {header: "BEST", priority: 4, render: func(d domain.Driver, t tableData) string {
+		return driverBestLap(t.styles, d, t.meeting)
+	}},
+	ColumnQ1: {header: "Q1 BEST", priority: 5, render: func(d domain.Driver, t tableData) string {
+		return driverBestLapInPart(t.styles, d, 0)
+	}},
+	ColumnQ2: {header: "Q2 BEST", priority: 5, render: func(d domain.Driver, t tableData) string {
+		return driverBestLapInPart(t.styles, d, 1)
+	}},
+	ColumnQ3: {header: "Q3 BEST", priority: 5, render: func(d domain.Driver, t tableData) string {
+		return driverBestLapInPart(t.styles, d, 2)
+	}},
+	ColumnPitRejoin: {header: "PIT REJOIN", priority: 6, render: func(d domain.Driver, t tableData) string {
+		return driverPitRejoin(t.styles, d, t.rejoins, t.drivers)
+	}},
+}
+
+// Columns returns the names of every column that can be shown on the timing table.
+func Columns() []string {
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// tableColumns returns the names of the columns to show for the session in order; the pit rejoin
+// column is toggled on races, appended to the configured columns if it wasn't configured itself.
+func (m TimingTable) tableColumns() []string {
+	var names []string
+	switch m.meeting.Session.Type {
+	case domain.SessionTypeQualifying:
+		names = m.qualifyingColumns
+	case domain.SessionTypeRace:
+		names = m.raceColumns
+		if m.showPitColumn && !slices.Contains(names, ColumnPitRejoin) {
+			names = append(slices.Clone(names), ColumnPitRejoin)
+		}
+	}
+	if !m.showPitColumn {
+		names = slices.DeleteFunc(slices.Clone(names), func(name string) bool { return name == ColumnPitRejoin })
+	}
+	return names
+}
+
+// fitColumns returns the columns that fit within the given width, dropping the lowest priority
+// columns first; on qualifying sessions the best lap columns of parts other than the current one
+// are dropped before any other. The width of a table with the given columns is measured by render.
+func (m TimingTable) fitColumns(names []string, width int, render func(names []string) string) []string {
+	names = slices.Clone(names)
+	for width > 0 && lipgloss.Width(render(names)) > width {
+		drop := -1
+		for i, name := range names {
+			p := m.columnPriority(name)
+			if p > 0 && (drop < 0 || p <= m.columnPriority(names[drop])) {
+				drop = i
+			}
+		}
+		if drop < 0 {
+			break
+		}
+		names = slices.Delete(names, drop, drop+1)
+	}
+	return names
+}
+
+// columnPriority returns the priority of the column in the current session.
+func (m TimingTable) columnPriority(name string) int {
+	parts := map[string]int{ColumnQ1: 1, ColumnQ2: 2, ColumnQ3: 3}
+	if part, ok := parts[name]; ok && part != m.meeting.Session.Part {
+		return 1
+	}
+	return columns[name].priority
+}
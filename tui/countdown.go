@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bcdxn/f1cli/domain"
+	"github.com/bcdxn/f1cli/tui/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// countdown is the screen shown while waiting for the upcoming session, counting down to its start
+// with the details of the meeting and session; once the latest session has ended it shows that the
+// next session hasn't been published yet.
+type countdown struct {
+	// session state
+	meeting domain.Meeting
+	now     time.Time
+	// configuration
+	styles *styles.Style
+	// screen size
+	width  int
+	height int
+}
+
+// newCountdown returns the countdown screen.
+func newCountdown(o options) countdown {
+	return countdown{meeting: domain.NewMeeting(), now: time.Now(), styles: o.styles}
+}
+
+func (m countdown) Init() tea.Cmd {
+	return tick()
+}
+
+func (m countdown) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case MeetingMsg:
+		m.meeting = domain.Meeting(msg)
+	case tickMsg:
+		m.now = time.Time(msg)
+	}
+	return m, nil
+}
+
+func (m countdown) View() string {
+	s := m.styles
+	session := m.meeting.Session
+	lines := []string{}
+
+	if session.Status == domain.SessionStatusEnded {
+		lines = append(lines,
+			s.TitleBar.UnsetPaddingBottom().Render("No Upcoming Session"),
+			fmt.Sprintf("%s of the %s has ended", cmp.Or(session.Name, "The last session"), m.meeting.Name),
+			"",
+			s.Subtle.Render("waiting for the next session to be published"),
+		)
+	} else {
+		start := "starting soon"
+		if d := session.StartDate.Sub(m.now); d > 0 {
+			start = "in " + formatCountdown(d)
+		}
+		lines = append(lines,
+			s.TitleBar.UnsetPaddingBottom().Render("Next Session"),
+			lipgloss.NewStyle().Bold(true).Render(m.meeting.Name),
+			meetingDetails(m.meeting),
+			"",
+			fmt.Sprintf("%s  %s", session.Name, s.Subtle.Render(sessionStart(session))),
+			lipgloss.NewStyle().Bold(true).Foreground(s.Color.PersonalBest).Render(start),
+			"",
+			s.Subtle.Render("the timing board is shown once the session starts"),
+		)
+	}
+
+	return lipgloss.Place(
+		m.width,
+		m.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		s.DetailPanel.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)),
+		lipgloss.WithWhitespaceChars("."),
+		lipgloss.WithWhitespaceForeground(s.Color.Subtle),
+	)
+}
+
+// meetingDetails returns the round, circuit and country of the meeting, omitting any that are unknown.
+func meetingDetails(meeting domain.Meeting) string {
+	details := make([]string, 0, 3)
+	if meeting.RoundNumber > 0 {
+		details = append(details, fmt.Sprintf("Round %d", meeting.RoundNumber))
+	}
+	if meeting.CircuitShortName != "" {
+		details = append(details, meeting.CircuitShortName)
+	}
+	if meeting.CountryName != "" {
+		details = append(details, meeting.CountryName)
+	}
+	return strings.Join(details, " · ")
+}
+
+// sessionStart returns the start of the session in the local time zone and the time zone of the
+// track.
+func sessionStart(session domain.Session) string {
+	if session.StartDate.IsZero() {
+		return "start time unknown"
+	}
+	return fmt.Sprintf(
+		"%s (%s track time)",
+		session.StartDate.Local().Format("Mon 2 Jan 15:04 MST"),
+		session.StartDate.Format("15:04"),
+	)
+}
+
+// formatCountdown returns the duration as days, hours, minutes and seconds, e.g. "2d 03:04:05".
+func formatCountdown(d time.Duration) string {
+	d = d.Round(time.Second)
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	hms := fmt.Sprintf("%02d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+	if days > 0 {
+		return fmt.Sprintf("%dd %s", days, hms)
+	}
+	return hms
+}
@@ -0,0 +1,74 @@
+package tui_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bcdxn/f1cli/domain"
+	"github.com/bcdxn/f1cli/tui"
+	"github.com/bcdxn/f1cli/tui/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// TestEmbed drives the embeddable components using only the exported API, as another module
+// embedding them in its own Bubble Tea program would.
+func TestEmbed(t *testing.T) {
+	meeting := domain.NewMeeting()
+	meeting.Name = "Abu Dhabi Grand Prix"
+	meeting.FullName = "FORMULA 1 ETIHAD AIRWAYS ABU DHABI GRAND PRIX 2024"
+	meeting.Session.Type = domain.SessionTypeRace
+	meeting.Session.Status = domain.SessionStatusStarted
+	meeting.Session.CurrentLap = 12
+	meeting.Session.TotalLaps = 58
+
+	ver := domain.NewDriver("1")
+	ver.ShortName = "VER"
+	ver.TimingData.Position = 1
+	nor := domain.NewDriver("4")
+	nor.ShortName = "NOR"
+	nor.TimingData.Position = 2
+	nor.TimingData.IntervalGap = "+1.234"
+	drivers := map[string]domain.Driver{ver.Number: ver, nor.Number: nor}
+
+	size := tea.WindowSizeMsg{Width: 120, Height: 40}
+	opts := []tui.TUIOption{tui.WithStyles(styles.New(styles.ColourBlindTheme()))}
+
+	t.Run("Header", func(t *testing.T) {
+		view := render(tui.NewHeader(opts...), size, tui.MeetingMsg(meeting))
+		if !strings.Contains(view, meeting.FullName) || !strings.Contains(view, "Race: 12 / 58 Laps") {
+			t.Errorf("expected the meeting and lap in the header but found:\n%s", view)
+		}
+	})
+
+	t.Run("TimingTable", func(t *testing.T) {
+		view := render(tui.NewTimingTable(append(opts, tui.WithColumns([]string{tui.ColumnPosition, tui.ColumnDriver, tui.ColumnInterval}, nil))...),
+			size, tui.MeetingMsg(meeting), tui.DriversMsg(drivers))
+		ver, nor := strings.Index(view, "VER"), strings.Index(view, "NOR")
+		if ver < 0 || nor < ver {
+			t.Errorf("expected VER above NOR on the timing table but found:\n%s", view)
+		}
+		if !strings.Contains(view, "+1.234") {
+			t.Errorf("expected the interval '%s' on the timing table but found:\n%s", "+1.234", view)
+		}
+	})
+
+	t.Run("RaceCtrlToast", func(t *testing.T) {
+		toast := tui.NewRaceCtrlToast(opts...)
+		if view := render(toast, size); view != "" {
+			t.Errorf("expected nothing until a message is received but found:\n%s", view)
+		}
+		msg := domain.RaceCtrlMsg{Category: domain.RaceCtrlMsgCategoryOther, Title: "PIT EXIT", Body: "GREEN LIGHT - PIT EXIT OPEN"}
+		if view := render(toast, size, tui.RaceCtrlMsg(msg)); !strings.Contains(view, msg.Body) {
+			t.Errorf("expected the message '%s' but found:\n%s", msg.Body, view)
+		}
+	})
+}
+
+// render sends the messages to the component in order, returning its view stripped of styling.
+func render(m tea.Model, msgs ...tea.Msg) string {
+	for _, msg := range msgs {
+		m, _ = m.Update(msg)
+	}
+	return ansi.Strip(m.View())
+}
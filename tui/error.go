@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/bcdxn/f1cli/tui/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/wordwrap"
+)
+
+// errorScreen is shown in place of the screens once the client exits with an error, describing the
+// cause and the keys to retry or quit; retrying is only offered when the program is configured to.
+type errorScreen struct {
+	err      error
+	keys     KeyMap
+	canRetry bool
+	styles   *styles.Style
+	// screen size
+	width  int
+	height int
+}
+
+// newErrorScreen returns the error screen built from the configuration.
+func newErrorScreen(o options) errorScreen {
+	return errorScreen{keys: o.keys, canRetry: o.retry != nil, styles: o.styles}
+}
+
+func (m errorScreen) Init() tea.Cmd {
+	return nil
+}
+
+func (m errorScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case ErrorMsg:
+		m.err = msg.Err
+	}
+	return m, nil
+}
+
+func (m errorScreen) View() string {
+	s := m.styles
+	if m.err == nil {
+		return ""
+	}
+	actions := fmt.Sprintf("%s to quit", m.keys.Quit.Help().Key)
+	if m.canRetry {
+		actions = fmt.Sprintf("%s to retry · %s", m.keys.Retry.Help().Key, actions)
+	}
+	title := lipgloss.NewStyle().Bold(true).Padding(0, 1).Background(s.Color.Red).Foreground(s.Color.Light)
+	panel := lipgloss.JoinVertical(
+		lipgloss.Left,
+		title.Render("DISCONNECTED"),
+		"",
+		wordwrap.String(m.err.Error(), 60),
+		"",
+		s.Subtle.Render(actions),
+	)
+	return lipgloss.Place(
+		m.width,
+		m.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		s.DetailPanel.BorderForeground(s.Color.Red).Render(panel),
+	)
+}
+
+// isShown indicates that the client exited with an error that hasn't been retried.
+func (m errorScreen) isShown() bool {
+	return m.err != nil
+}
+
+// dismiss hides the error screen, e.g. once the user retries.
+func (m errorScreen) dismiss() errorScreen {
+	m.err = nil
+	return m
+}
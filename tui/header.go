@@ -0,0 +1,143 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bcdxn/f1cli/domain"
+	"github.com/bcdxn/f1cli/tui/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// the data delay shown in the header is colored as it exceeds these thresholds
+const (
+	feedDelayWarning = 2 * time.Second
+	feedDelayAlert   = 5 * time.Second
+)
+
+// delayStep is how much the broadcast delay is adjusted by each key press.
+const delayStep = time.Second
+
+// Header is the component showing the name of the meeting and the progress of its current session,
+// e.g. the lap of a race or the part of a qualifying session, along with the delay of the live data
+// and the broadcast delay it is shown with. It is updated by MeetingMsg and sized to the width of a
+// tea.WindowSizeMsg.
+type Header struct {
+	meeting domain.Meeting
+	delay   Delayer // delay is the broadcast delay of the data; nil if the data isn't delayed
+	syncErr error   // syncErr is why the delay couldn't be synced, shown until the delay is next adjusted
+	styles  *styles.Style
+	width   int
+}
+
+// NewHeader returns the header component configured by the given options.
+func NewHeader(opts ...TUIOption) Header {
+	return newHeader(newOptions(opts))
+}
+
+// newHeader returns the header built from the configuration.
+func newHeader(o options) Header {
+	return Header{meeting: domain.NewMeeting(), delay: o.delay, styles: o.styles}
+}
+
+func (m Header) Init() tea.Cmd {
+	return nil
+}
+
+func (m Header) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+	case MeetingMsg:
+		m.meeting = domain.Meeting(msg)
+	}
+	return m, nil
+}
+
+func (m Header) View() string {
+	s := m.styles
+	subtitleContent := m.meeting.Name
+	if m.meeting.Session.Type == domain.SessionTypeRace {
+		subtitleContent = fmt.Sprintf("Race: %d / %d Laps", m.meeting.Session.CurrentLap, m.meeting.Session.TotalLaps)
+	} else if m.meeting.Session.Type == domain.SessionTypeQualifying {
+		subtitleContent = fmt.Sprintf("Qualifying %d", m.meeting.Session.Part)
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Center,
+		s.TitleBar.Width(m.width).Render(m.meeting.FullName),
+		s.SubtitleBar.Width(m.width).Render(joinNonEmpty(
+			" · ",
+			subtitleContent,
+			classificationLabel(s, m.meeting.Session),
+			feedLabel(s, m.meeting.Feed),
+			m.delayLabel(),
+		)),
+	)
+}
+
+// hasDelay indicates that the data is shown with a broadcast delay that can be adjusted.
+func (m Header) hasDelay() bool {
+	return m.delay != nil
+}
+
+// adjustDelay lengthens or shortens the broadcast delay by delta.
+func (m Header) adjustDelay(delta time.Duration) Header {
+	m.delay.Adjust(delta)
+	m.syncErr = nil
+	return m
+}
+
+// syncDelay sets the broadcast delay so the latest lap counter change is shown now, i.e. as it is
+// shown on the broadcast.
+func (m Header) syncDelay() Header {
+	_, m.syncErr = m.delay.Sync()
+	return m
+}
+
+// delayLabel returns the label of the broadcast delay, or of the reason it couldn't be synced; it is
+// empty while the data isn't delayed.
+func (m Header) delayLabel() string {
+	s := m.styles
+	switch {
+	case m.delay == nil:
+		return ""
+	case m.syncErr != nil:
+		return lipgloss.NewStyle().Foreground(s.Color.Red).Render("sync failed: " + m.syncErr.Error())
+	case m.delay.Duration() == 0:
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(s.Color.Blue).Render(fmt.Sprintf("%s broadcast delay", m.delay.Duration().Round(time.Second)))
+}
+
+// isStale indicates that the live data is stale as the connection is being re-established.
+func (m Header) isStale() bool {
+	return m.meeting.Feed.Stale
+}
+
+// feedLabel returns the label of the delay of the live data, colored by how far behind the server
+// it is, or of the data being stale; it is empty unless the data is live.
+func feedLabel(s *styles.Style, feed domain.Feed) string {
+	switch {
+	case feed.Stale:
+		return lipgloss.NewStyle().Foreground(s.Color.Red).Render("● stale · reconnecting")
+	case !feed.IsLive():
+		return ""
+	}
+	color := s.Color.Green
+	if feed.Delay >= feedDelayAlert {
+		color = s.Color.Red
+	} else if feed.Delay >= feedDelayWarning {
+		color = s.Color.Yellow
+	}
+	// the clocks of the client and server may be slightly out of sync
+	delay := max(feed.Delay, 0).Round(100 * time.Millisecond)
+	return lipgloss.NewStyle().Foreground(color).Render(fmt.Sprintf("● %s delay", delay))
+}
+
+// viewStale returns the view greyed out, e.g. so stale data isn't mistaken for live data.
+func viewStale(s *styles.Style, view string) string {
+	return s.Subtle.Render(ansi.Strip(view))
+}
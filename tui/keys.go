@@ -0,0 +1,163 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+const (
+	ActionQuit          = "quit"
+	ActionUp            = "up"
+	ActionDown          = "down"
+	ActionDetail        = "detail"
+	ActionClose         = "close"
+	ActionPitColumn     = "pit_column"
+	ActionCompact       = "compact"
+	ActionHelp          = "help"
+	ActionNextScreen    = "next_screen"
+	ActionPrevScreen    = "prev_screen"
+	ActionTiming        = "timing"
+	ActionStrategy      = "strategy"
+	ActionRaceControl   = "race_control"
+	ActionWeather       = "weather"
+	ActionTelemetry     = "telemetry"
+	ActionRetry         = "retry"
+	ActionDelayIncrease = "delay_increase"
+	ActionDelayDecrease = "delay_decrease"
+	ActionDelaySync     = "delay_sync"
+)
+
+// KeyMap contains the key bindings of each action in the TUI.
+type KeyMap struct {
+	Quit      key.Binding
+	Up        key.Binding
+	Down      key.Binding
+	Detail    key.Binding
+	Close     key.Binding
+	PitColumn key.Binding
+	Compact   key.Binding
+	Help      key.Binding
+	// screen navigation
+	NextScreen  key.Binding
+	PrevScreen  key.Binding
+	Timing      key.Binding
+	Strategy    key.Binding
+	RaceControl key.Binding
+	Weather     key.Binding
+	Telemetry   key.Binding
+	// error screen
+	Retry key.Binding
+	// broadcast delay
+	DelayIncrease key.Binding
+	DelayDecrease key.Binding
+	DelaySync     key.Binding
+}
+
+// DefaultKeyMap returns the default key bindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Quit:        key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
+		Up:          key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "select previous driver")),
+		Down:        key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "select next driver")),
+		Detail:      key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "toggle driver detail")),
+		Close:       key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "close driver detail")),
+		PitColumn:   key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "toggle pit rejoin column")),
+		Compact:     key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "toggle compact timing table")),
+		Help:        key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "toggle help")),
+		NextScreen:  key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "next screen")),
+		PrevScreen:  key.NewBinding(key.WithKeys("shift+tab"), key.WithHelp("shift+tab", "previous screen")),
+		Timing:      key.NewBinding(key.WithKeys("1"), key.WithHelp("1", "timing")),
+		Strategy:    key.NewBinding(key.WithKeys("2"), key.WithHelp("2", "strategy")),
+		RaceControl: key.NewBinding(key.WithKeys("3"), key.WithHelp("3", "race control")),
+		Weather:     key.NewBinding(key.WithKeys("4"), key.WithHelp("4", "weather")),
+		Telemetry:   key.NewBinding(key.WithKeys("5"), key.WithHelp("5", "telemetry")),
+		Retry:       key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "retry")),
+		// = and _ share the keys of + and - so the delay can be adjusted without shift
+		DelayIncrease: key.NewBinding(key.WithKeys("+", "="), key.WithHelp("+", "increase broadcast delay")),
+		DelayDecrease: key.NewBinding(key.WithKeys("-", "_"), key.WithHelp("-", "decrease broadcast delay")),
+		DelaySync:     key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "sync delay as the lap counter ticks")),
+	}
+}
+
+// KeyActions returns the names of every action that can be bound to keys.
+func KeyActions() []string {
+	actions := []string{
+		ActionQuit, ActionUp, ActionDown, ActionDetail, ActionClose, ActionPitColumn, ActionCompact, ActionHelp,
+		ActionNextScreen, ActionPrevScreen, ActionTiming, ActionStrategy, ActionRaceControl, ActionWeather, ActionTelemetry,
+		ActionRetry, ActionDelayIncrease, ActionDelayDecrease, ActionDelaySync,
+	}
+	slices.Sort(actions)
+	return actions
+}
+
+// WithKeys returns a copy of the key map with the given actions bound to the given keys instead of
+// their defaults; unknown actions are ignored.
+func (k KeyMap) WithKeys(bindings map[string][]string) KeyMap {
+	for action, keys := range bindings {
+		if b := k.binding(action); b != nil {
+			b.SetKeys(keys...)
+			b.SetHelp(strings.Join(keys, "/"), b.Help().Desc)
+		}
+	}
+	return k
+}
+
+// screens returns the bindings that switch directly to each screen in the order of the screens.
+func (k KeyMap) screens() []key.Binding {
+	return []key.Binding{k.Timing, k.Strategy, k.RaceControl, k.Weather, k.Telemetry}
+}
+
+// binding returns the binding of the given action.
+func (k *KeyMap) binding(action string) *key.Binding {
+	switch action {
+	case ActionQuit:
+		return &k.Quit
+	case ActionUp:
+		return &k.Up
+	case ActionDown:
+		return &k.Down
+	case ActionDetail:
+		return &k.Detail
+	case ActionClose:
+		return &k.Close
+	case ActionPitColumn:
+		return &k.PitColumn
+	case ActionCompact:
+		return &k.Compact
+	case ActionHelp:
+		return &k.Help
+	case ActionNextScreen:
+		return &k.NextScreen
+	case ActionPrevScreen:
+		return &k.PrevScreen
+	case ActionTiming:
+		return &k.Timing
+	case ActionStrategy:
+		return &k.Strategy
+	case ActionRaceControl:
+		return &k.RaceControl
+	case ActionWeather:
+		return &k.Weather
+	case ActionTelemetry:
+		return &k.Telemetry
+	case ActionRetry:
+		return &k.Retry
+	case ActionDelayIncrease:
+		return &k.DelayIncrease
+	case ActionDelayDecrease:
+		return &k.DelayDecrease
+	case ActionDelaySync:
+		return &k.DelaySync
+	default:
+		return nil
+	}
+}
+
+// withHelp returns a copy of the binding described by the given help text, e.g. for screens on
+// which the action has a different meaning.
+func withHelp(b key.Binding, desc string) key.Binding {
+	b.SetHelp(b.Help().Key, desc)
+	return b
+}
@@ -0,0 +1,471 @@
+package tui
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/bcdxn/f1cli/domain"
+	"github.com/bcdxn/f1cli/tui/styles"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// NewLeaderboard returns the full screen TUI program hosting the header, the race control toast and
+// every screen, e.g. the timing table, switchable by number keys or tab.
+func NewLeaderboard(opts ...TUIOption) *tea.Program {
+	o := newOptions(opts)
+	return tea.NewProgram(newLeaderboard(o), tea.WithContext(o.ctx), tea.WithAltScreen())
+}
+
+// newLeaderboard returns the root model of the TUI program built from the configuration.
+func newLeaderboard(o options) Leaderboard {
+	sp := spinner.New()
+	sp.Spinner = spinner.MiniDot
+
+	return Leaderboard{
+		header:    newHeader(o),
+		toast:     newRaceCtrlToast(o),
+		countdown: newCountdown(o),
+		session:   newSessionOverlay(o),
+		errScreen: newErrorScreen(o),
+		screens: []screen{
+			newTimingTable(o),
+			newStrategyScreen(o),
+			newRaceControlScreen(o),
+			newWeatherScreen(o),
+			newTelemetryScreen(o),
+		},
+		keys:    o.keys,
+		retry:   o.retry,
+		styles:  o.styles,
+		logger:  o.logger,
+		spinner: sp,
+		help:    newHelp(o.styles),
+		ticking: true, // the countdown is ticking until the session starts
+	}
+}
+
+/* Bubbletea Interface Implementation
+------------------------------------------------------------------------------------------------- */
+
+func (l Leaderboard) Init() tea.Cmd {
+	return tea.Batch(l.spinner.Tick, l.countdown.Init())
+}
+
+func (l Leaderboard) View() string {
+	s := l.styles
+	var v string
+
+	if l.errScreen.isShown() {
+		v = l.errScreen.View()
+	} else if !l.isLoaded {
+		v = l.spinner.View() + " loading..."
+	} else if l.isWaiting() {
+		v = lipgloss.JoinVertical(lipgloss.Center, l.header.View(), viewPadding(s, l.width), l.countdown.View())
+	} else {
+		v = lipgloss.JoinVertical(lipgloss.Center, l.viewSections(l.viewScreen())...)
+	}
+
+	return s.Doc.Width(l.width).Render(v)
+}
+
+func (l Leaderboard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		l, cmd = handleKeyMsg(l, msg)
+	case tea.WindowSizeMsg:
+		l, cmd = handleWindowSizeMsg(l, msg)
+	case MeetingMsg:
+		l.header = update(l.header, msg)
+		l.countdown = update(l.countdown, msg)
+		l.session = update(l.session, msg)
+		l.isLoaded = true
+		cmd = l.updateScreens(msg)
+		// the suspension timer starts ticking when the session is suspended
+		if !l.ticking && l.hasTimer() {
+			l.ticking = true
+			cmd = tea.Batch(cmd, tick())
+		}
+	case DriversMsg:
+		l.session = update(l.session, msg)
+		l.isLoaded = true
+		l.hasTiming = hasTimingData(msg)
+		cmd = l.updateScreens(msg)
+	case tickMsg:
+		l.countdown = update(l.countdown, msg)
+		l.session = update(l.session, msg)
+		if l.ticking = l.hasTimer(); l.ticking {
+			cmd = tick()
+		}
+	case RaceCtrlMsg:
+		l.toast = update(l.toast, msg)
+		l.session = update(l.session, msg)
+		cmd = l.updateScreens(msg)
+	case ErrorMsg:
+		l.logger.Debug("received error tea message", "err", msg.Err)
+		l.errScreen = update(l.errScreen, msg)
+		l.showHelp = false
+	default:
+		if !l.isLoaded {
+			l.spinner, cmd = l.spinner.Update(msg)
+		}
+	}
+
+	return l.resizeScreens(), cmd
+}
+
+/* Screens
+------------------------------------------------------------------------------------------------- */
+
+// screen is one of the screens of the TUI. Every screen is a Bubble Tea model fed the same drivers,
+// meeting and race control messages, sized to the space left by the header, tabs and toast, and
+// describes its key bindings for the help overlay. Only the active screen receives key messages.
+type screen interface {
+	tea.Model
+	help.KeyMap
+	// title is the name of the screen shown on its tab
+	title() string
+}
+
+// update forwards the message to a component that never returns a command.
+func update[M tea.Model](m M, msg tea.Msg) M {
+	updated, _ := m.Update(msg)
+	return updated.(M)
+}
+
+// updateScreens forwards the message to every screen.
+func (l Leaderboard) updateScreens(msg tea.Msg) tea.Cmd {
+	cmds := make([]tea.Cmd, 0, len(l.screens))
+	for i, sc := range l.screens {
+		m, cmd := sc.Update(msg)
+		l.screens[i] = m.(screen)
+		cmds = append(cmds, cmd)
+	}
+	return tea.Batch(cmds...)
+}
+
+// updateActiveScreen forwards the message to the screen that is shown.
+func (l Leaderboard) updateActiveScreen(msg tea.Msg) tea.Cmd {
+	m, cmd := l.screens[l.active].Update(msg)
+	l.screens[l.active] = m.(screen)
+	return cmd
+}
+
+// resizeScreens returns the leaderboard with every screen resized to the space left by the other
+// sections of the view whenever it changes, e.g. when a longer race control message is shown.
+func (l Leaderboard) resizeScreens() Leaderboard {
+	// the error screen replaces every other section of the view
+	l.errScreen = update(l.errScreen, tea.WindowSizeMsg{Width: l.width, Height: l.height})
+	if l.width != l.screenWidth {
+		l.header = update(l.header, tea.WindowSizeMsg{Width: l.width})
+		l.toast = update(l.toast, tea.WindowSizeMsg{Width: l.width})
+	}
+	if l.isWaiting() {
+		l.countdown = update(l.countdown, tea.WindowSizeMsg{
+			Width:  l.width,
+			Height: l.height - lipgloss.Height(l.header.View()) - lipgloss.Height(viewPadding(l.styles, l.width)),
+		})
+	}
+	h := l.height
+	for _, section := range l.viewSections("") {
+		h -= lipgloss.Height(section)
+	}
+	if l.width == l.screenWidth && h == l.screenHeight {
+		return l
+	}
+	l.screenWidth, l.screenHeight = l.width, h
+	l.session = update(l.session, tea.WindowSizeMsg{Width: l.width, Height: h})
+	l.updateScreens(tea.WindowSizeMsg{Width: l.width, Height: h})
+	return l
+}
+
+// isWaiting indicates that the session hasn't started yet, or the latest session has ended, and no
+// timing data has been received, in which case the countdown to the next session is shown instead
+// of the screens. The drivers are usually listed well before the session starts, so they don't end
+// the wait.
+func (l Leaderboard) isWaiting() bool {
+	status := l.countdown.meeting.Session.Status
+	return !l.hasTiming && (status == domain.SessionStatusPending || status == domain.SessionStatusEnded)
+}
+
+// hasTimingData indicates that any of the drivers has set a lap or sector time.
+func hasTimingData(drivers DriversMsg) bool {
+	for _, driver := range drivers {
+		timing := driver.TimingData
+		if timing.NumberOfLaps > 0 || timing.LastLap.Time != "" || timing.BestLapTime != "" {
+			return true
+		}
+		for _, sector := range timing.Sectors {
+			if sector.Time != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasTimer indicates that a timer is shown which is refreshed every second, i.e. the countdown to
+// the next session or the duration of a red flag suspension.
+func (l Leaderboard) hasTimer() bool {
+	return l.isWaiting() || l.session.meeting.Session.Status == domain.SessionStatusSuspended
+}
+
+/* View Helpers
+------------------------------------------------------------------------------------------------- */
+
+// viewSections returns every section of the view around the given content of the active screen.
+func (l Leaderboard) viewSections(content string) []string {
+	s := l.styles
+	sections := []string{
+		l.header.View(),
+		viewTabs(l),
+		viewPadding(s, l.width),
+		content,
+		viewPadding(s, l.width),
+	}
+	// the race control screen lists every message so the toast would be redundant
+	if _, ok := l.screens[l.active].(raceControlScreen); !ok && l.toast.View() != "" {
+		sections = append(sections, l.toast.View(), viewPadding(s, l.width))
+	}
+	return append(sections, viewFooter(l))
+}
+
+// viewScreen returns the view of the active screen, greyed out while the data is stale, or the help
+// or session overlay when shown.
+func (l Leaderboard) viewScreen() string {
+	s := l.styles
+	if !l.showHelp && l.session.isShown() {
+		return l.session.View()
+	}
+	if !l.showHelp {
+		v := l.screens[l.active].View()
+		if l.header.isStale() {
+			v = viewStale(s, v)
+		}
+		return v
+	}
+	return lipgloss.Place(
+		l.width,
+		l.screenHeight,
+		lipgloss.Center,
+		lipgloss.Center,
+		s.DetailPanel.Render(lipgloss.JoinVertical(
+			lipgloss.Left,
+			s.TitleBar.UnsetPaddingBottom().Render(l.screens[l.active].title()+" Keys"),
+			l.help.FullHelpView(l.FullHelp()),
+		)),
+	)
+}
+
+// getPadding returns the padding view component
+func viewPadding(s *styles.Style, width int) string {
+	return lipgloss.PlaceHorizontal(
+		width,
+		lipgloss.Center,
+		"",
+		lipgloss.WithWhitespaceChars("."),
+		lipgloss.WithWhitespaceForeground(s.Color.Subtle),
+	)
+}
+
+// viewScrollIndicator returns the padding below a scrolled list, indicating how many items are
+// hidden above and below the scroll window.
+func viewScrollIndicator(s *styles.Style, width, above, below int) string {
+	if above+below <= 0 {
+		return viewPadding(s, width)
+	}
+	return lipgloss.PlaceHorizontal(
+		width,
+		lipgloss.Center,
+		s.Subtle.Render(fmt.Sprintf(" ↑ %d  ↓ %d ", above, below)),
+		lipgloss.WithWhitespaceChars("."),
+		lipgloss.WithWhitespaceForeground(s.Color.Subtle),
+	)
+}
+
+// viewTabs returns the tab bar listing every screen and the key that switches to it, highlighting
+// the active screen.
+func viewTabs(l Leaderboard) string {
+	s := l.styles
+	keys := l.keys.screens()
+	tabs := make([]string, 0, len(l.screens))
+	for i, sc := range l.screens {
+		style := s.Tab
+		if i == l.active {
+			style = s.ActiveTab
+		}
+		tabs = append(tabs, style.Render(fmt.Sprintf("%s %s", keys[i].Help().Key, sc.title())))
+	}
+	return lipgloss.PlaceHorizontal(l.width, lipgloss.Center, lipgloss.JoinHorizontal(lipgloss.Top, tabs...))
+}
+
+// viewFooter returns the short help of the active screen.
+func viewFooter(l Leaderboard) string {
+	h := l.help
+	h.Width = l.width
+	return lipgloss.PlaceHorizontal(l.width, lipgloss.Center, h.ShortHelpView(l.ShortHelp()))
+}
+
+// scrollOffset returns the offset of a scroll window showing the given number of visible items out
+// of the total, moved so that the item at the given index is visible; a negative index keeps the
+// offset where it is.
+func scrollOffset(offset, index, visible, total int) int {
+	if index >= 0 {
+		if index < offset {
+			offset = index
+		} else if index >= offset+visible {
+			offset = index - visible + 1
+		}
+	}
+	return max(0, min(offset, total-visible))
+}
+
+/* Help
+------------------------------------------------------------------------------------------------- */
+
+// newHelp returns the help model styled by the configured theme.
+func newHelp(s *styles.Style) help.Model {
+	h := help.New()
+	h.Styles.ShortKey = lipgloss.NewStyle().Foreground(s.Color.PrimaryForeground)
+	h.Styles.ShortDesc = s.Subtle
+	h.Styles.ShortSeparator = s.Subtle
+	h.Styles.Ellipsis = s.Subtle
+	h.Styles.FullKey = lipgloss.NewStyle().Bold(true).Foreground(s.Color.PrimaryForeground)
+	h.Styles.FullDesc = lipgloss.NewStyle().Foreground(s.Color.PrimaryForeground)
+	h.Styles.FullSeparator = s.Subtle
+	return h
+}
+
+// ShortHelp returns the key bindings of the active screen followed by the global key bindings.
+func (l Leaderboard) ShortHelp() []key.Binding {
+	if l.session.isShown() {
+		return []key.Binding{withHelp(l.keys.Close, "show board"), l.keys.NextScreen, l.keys.Help, l.keys.Quit}
+	}
+	return append(l.screens[l.active].ShortHelp(), l.keys.NextScreen, l.keys.Help, l.keys.Quit)
+}
+
+// FullHelp returns the key bindings of the active screen followed by the global key bindings,
+// grouped in columns.
+func (l Leaderboard) FullHelp() [][]key.Binding {
+	help := append(
+		l.screens[l.active].FullHelp(),
+		l.keys.screens(),
+		[]key.Binding{l.keys.NextScreen, l.keys.PrevScreen, l.keys.Help, l.keys.Quit},
+	)
+	if l.header.hasDelay() {
+		help = append(help, []key.Binding{l.keys.DelayIncrease, l.keys.DelayDecrease, l.keys.DelaySync})
+	}
+	return help
+}
+
+/* Tea Mesage handlers
+------------------------------------------------------------------------------------------------- */
+
+// handleKeyMsg is a tea.Msg handler that handles key press messages including ctrl+c and q to quit
+// the TUI application, switching screens and adjusting the broadcast delay; any other key is handled
+// by the active screen. Only quitting and retrying are handled while the error screen is shown.
+func handleKeyMsg(m Leaderboard, msg tea.KeyMsg) (Leaderboard, tea.Cmd) {
+	if m.errScreen.isShown() {
+		return handleErrorKeyMsg(m, msg)
+	}
+	switch {
+	// ctrl+c always quits regardless of the configured key bindings
+	case msg.String() == "ctrl+c", key.Matches(msg, m.keys.Quit):
+		m.logger.Debug("received quit tea message")
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Help):
+		m.showHelp = !m.showHelp
+	case m.showHelp && key.Matches(msg, m.keys.Close):
+		m.showHelp = false
+	case m.session.isShown() && key.Matches(msg, m.keys.Close):
+		m.session = m.session.dismiss()
+	case key.Matches(msg, m.keys.NextScreen):
+		m.active = (m.active + 1) % len(m.screens)
+	case key.Matches(msg, m.keys.PrevScreen):
+		m.active = (m.active + len(m.screens) - 1) % len(m.screens)
+	case m.header.hasDelay() && key.Matches(msg, m.keys.DelayIncrease):
+		m.header = m.header.adjustDelay(delayStep)
+	case m.header.hasDelay() && key.Matches(msg, m.keys.DelayDecrease):
+		m.header = m.header.adjustDelay(-delayStep)
+	case m.header.hasDelay() && key.Matches(msg, m.keys.DelaySync):
+		m.header = m.header.syncDelay()
+	default:
+		for i, b := range m.keys.screens() {
+			if key.Matches(msg, b) {
+				m.active = i
+				return m, nil
+			}
+		}
+		return m, m.updateActiveScreen(msg)
+	}
+	return m, nil
+}
+
+// handleErrorKeyMsg is a tea.Msg handler that handles key press messages while the error screen is
+// shown, quitting the TUI application or dismissing the error screen and retrying; any other key is
+// ignored.
+func handleErrorKeyMsg(m Leaderboard, msg tea.KeyMsg) (Leaderboard, tea.Cmd) {
+	switch {
+	case msg.String() == "ctrl+c", key.Matches(msg, m.keys.Quit):
+		m.logger.Debug("received quit tea message")
+		return m, tea.Quit
+	case m.retry != nil && key.Matches(msg, m.keys.Retry):
+		m.logger.Debug("retrying after error")
+		m.errScreen = m.errScreen.dismiss()
+		retry := m.retry
+		return m, func() tea.Msg {
+			retry()
+			return nil
+		}
+	}
+	return m, nil
+}
+
+// handleWindowSizeMsg is a tea.Msg handler that handles window resize events and stores the current
+// window size of the terminal in the tea model.
+func handleWindowSizeMsg(l Leaderboard, msg tea.WindowSizeMsg) (Leaderboard, tea.Cmd) {
+	h, v := l.styles.Doc.GetFrameSize()
+	l.width = msg.Width - h
+	l.height = msg.Height - v
+	return l, nil
+}
+
+/* Type Definitions
+------------------------------------------------------------------------------------------------- */
+
+// Leaderboard is the root model of the TUI program, composed of the header, the race control toast
+// and the screens; only the screen that is shown receives key messages.
+type Leaderboard struct {
+	// components
+	header    Header
+	toast     RaceCtrlToast
+	countdown countdown
+	session   sessionOverlay
+	errScreen errorScreen
+	isLoaded  bool
+	hasTiming bool // hasTiming indicates that the drivers have set a lap or sector time in the session
+	ticking   bool // ticking indicates that a tickMsg is scheduled to refresh the timers
+	// screens
+	screens      []screen
+	active       int // active is the index of the screen that is shown
+	showHelp     bool
+	screenWidth  int // screenWidth is the width last sent to the components
+	screenHeight int // screenHeight is the height last sent to the screens
+	// configuration
+	keys   KeyMap
+	retry  func() // retry is called when the user chooses to retry from the error screen
+	styles *styles.Style
+	// metadata
+	logger *slog.Logger
+	// bubbles
+	spinner spinner.Model
+	help    help.Model
+	// window size
+	width  int
+	height int
+}
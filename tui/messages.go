@@ -0,0 +1,38 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/bcdxn/f1cli/domain"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+/* Tea Mesage Types
+------------------------------------------------------------------------------------------------- */
+
+// DriversMsg carries the latest state of every driver keyed by racing number. It is handled by the
+// timing table and every screen of the TUI program; each message replaces the previous state.
+type DriversMsg map[string]domain.Driver
+
+// MeetingMsg carries the latest state of the meeting and its current session. It is handled by the
+// header, the timing table and every screen of the TUI program.
+type MeetingMsg domain.Meeting
+
+// RaceCtrlMsg carries the latest race control message. It is handled by the race control toast and
+// the race control screen of the TUI program.
+type RaceCtrlMsg domain.RaceCtrlMsg
+
+// ErrorMsg carries the error the client exited with. It is handled by the TUI program, which shows
+// the error screen in place of the screens until the user retries or quits.
+type ErrorMsg struct {
+	Err error
+}
+
+// tickMsg is sent every second while a timer is shown, e.g. the countdown to the next session or
+// the duration of a red flag suspension.
+type tickMsg time.Time
+
+// tick returns the command sending the next tickMsg.
+func tick() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
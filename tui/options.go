@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bcdxn/f1cli/internal/strategy"
+	"github.com/bcdxn/f1cli/tui/styles"
+)
+
+// TUIOption configures the TUI program or any of its components; options that do not apply to a
+// component are ignored by it.
+type TUIOption = func(o *options)
+
+// options is the configuration shared by the TUI program and its components.
+type options struct {
+	keys              KeyMap
+	pitLoss           *strategy.PitLoss
+	raceColumns       []string
+	qualifyingColumns []string
+	favourites        map[string]bool // favourites contains racing numbers, abbreviations and `TEAM:` prefixed team names
+	showPitColumn     bool
+	compact           bool
+	retry             func()  // retry is called when the user chooses to retry from the error screen
+	delay             Delayer // delay is the broadcast delay adjusted from the keyboard; nil if the data isn't delayed
+	styles            *styles.Style
+	ctx               context.Context
+	logger            *slog.Logger
+}
+
+// newOptions returns the default configuration with the given options applied.
+func newOptions(opts []TUIOption) options {
+	o := options{
+		pitLoss:           strategy.NewPitLoss(0),
+		keys:              DefaultKeyMap(),
+		raceColumns:       DefaultRaceColumns,
+		qualifyingColumns: DefaultQualifyingColumns,
+		favourites:        make(map[string]bool),
+		styles:            styles.Default(),
+		logger:            slog.Default(),
+		ctx:               context.Background(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithLogger configures the logger to use within the TUI program
+func WithLogger(l *slog.Logger) TUIOption {
+	return func(o *options) { o.logger = l }
+}
+
+// WithLogger configures the context to use within the TUI program
+func WithContext(ctx context.Context) TUIOption {
+	return func(o *options) { o.ctx = ctx }
+}
+
+// WithRetry configures the function called when the user chooses to retry from the error screen,
+// e.g. to reconnect the client; the error screen only offers to quit when not configured.
+func WithRetry(retry func()) TUIOption {
+	return func(o *options) { o.retry = retry }
+}
+
+// Delayer is the broadcast delay of the data shown, e.g. so the timing board is in sync with a TV
+// broadcast; it is adjusted from the keyboard while the TUI program runs.
+type Delayer interface {
+	// Duration returns the current delay.
+	Duration() time.Duration
+	// Adjust lengthens or shortens the delay by delta, returning the new delay.
+	Adjust(delta time.Duration) time.Duration
+	// Sync sets the delay so the latest lap counter change is shown now, returning the new delay.
+	Sync() (time.Duration, error)
+}
+
+// WithDelay configures the broadcast delay shown in the header and adjusted from the keyboard; the
+// delay keys do nothing when not configured.
+func WithDelay(d Delayer) TUIOption {
+	return func(o *options) { o.delay = d }
+}
+
+// WithPitLoss configures the time lost making a pit stop (in seconds) used to predict where drivers
+// would rejoin the race; when not configured the pit loss is learned from observed pit stops.
+func WithPitLoss(seconds float64) TUIOption {
+	return func(o *options) { o.pitLoss = strategy.NewPitLoss(seconds) }
+}
+
+// WithKeyMap configures the key bindings of the TUI program.
+func WithKeyMap(k KeyMap) TUIOption {
+	return func(o *options) { o.keys = k }
+}
+
+// WithColumns configures the columns of the timing table, in order, for races and qualifying
+// sessions; the defaults are kept for any session type that is given no columns. Showing the pit
+// rejoin column enables it by default.
+func WithColumns(race, qualifying []string) TUIOption {
+	return func(o *options) {
+		if len(race) > 0 {
+			o.raceColumns = race
+			o.showPitColumn = slices.Contains(race, ColumnPitRejoin)
+		}
+		if len(qualifying) > 0 {
+			o.qualifyingColumns = qualifying
+		}
+	}
+}
+
+// WithFavourites configures the drivers, by racing number or abbreviation, and the teams whose rows
+// are highlighted on the timing table.
+func WithFavourites(drivers, teams []string) TUIOption {
+	return func(o *options) {
+		for _, d := range drivers {
+			o.favourites[strings.ToUpper(d)] = true
+		}
+		for _, t := range teams {
+			o.favourites["TEAM:"+strings.ToUpper(t)] = true
+		}
+	}
+}
+
+// WithCompact configures the timing table to always draw one line per driver; otherwise rows are
+// only compacted when the terminal is too short to fit every driver.
+func WithCompact(compact bool) TUIOption {
+	return func(o *options) { o.compact = compact }
+}
+
+// WithStyles configures the theme used to render the TUI program or component; components
+// configured with different themes can be shown side by side.
+func WithStyles(st *styles.Style) TUIOption {
+	return func(o *options) { o.styles = st }
+}
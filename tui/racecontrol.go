@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bcdxn/f1cli/domain"
+	"github.com/bcdxn/f1cli/tui/styles"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/wordwrap"
+)
+
+const (
+	// maxRaceCtrlMsgs is the number of race control messages kept in the history
+	maxRaceCtrlMsgs = 500
+)
+
+// raceControlScreen is the screen listing every race control message received during the session,
+// newest first.
+type raceControlScreen struct {
+	// session state
+	msgs []domain.RaceCtrlMsg // msgs are the race control messages received, oldest first
+	// view state
+	offset int // offset is the index of the first message shown in the scroll window, newest first
+	// configuration
+	keys   KeyMap
+	styles *styles.Style
+	// screen size
+	width  int
+	height int
+}
+
+// newRaceControlScreen returns the race control screen built from the configuration.
+func newRaceControlScreen(o options) raceControlScreen {
+	return raceControlScreen{
+		msgs:   make([]domain.RaceCtrlMsg, 0),
+		keys:   o.keys,
+		styles: o.styles,
+	}
+}
+
+func (m raceControlScreen) Init() tea.Cmd {
+	return nil
+}
+
+func (m raceControlScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Up):
+			m.offset--
+		case key.Matches(msg, m.keys.Down):
+			m.offset++
+		}
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case RaceCtrlMsg:
+		rcm := domain.RaceCtrlMsg(msg)
+		// the latest message is sent again whenever a connection is (re-)established
+		if n := len(m.msgs); n == 0 || m.msgs[n-1] != rcm {
+			m.msgs = append(m.msgs, rcm)
+			if len(m.msgs) > maxRaceCtrlMsgs {
+				m.msgs = m.msgs[1:]
+			}
+			// keep the messages in view while scrolled back through the history
+			if m.offset > 0 {
+				m.offset++
+			}
+		}
+	}
+	m.offset = max(0, min(m.offset, len(m.msgs)-1))
+	return m, nil
+}
+
+func (m raceControlScreen) View() string {
+	s := m.styles
+	if len(m.msgs) == 0 {
+		return lipgloss.PlaceHorizontal(m.width, lipgloss.Center, s.Subtle.Render("no race control messages yet"))
+	}
+	rows := make([]string, 0)
+	h := 0
+	shown := 0
+	for i := len(m.msgs) - 1 - m.offset; i >= 0; i-- {
+		row := viewRaceCtrlMsgRow(s, m.msgs[i], min(m.width, 100))
+		if m.height > 0 && h+lipgloss.Height(row) > m.height-1 && shown > 0 {
+			break
+		}
+		rows = append(rows, row)
+		h += lipgloss.Height(row)
+		shown++
+	}
+	return lipgloss.JoinVertical(
+		lipgloss.Center,
+		lipgloss.PlaceHorizontal(m.width, lipgloss.Center, lipgloss.JoinVertical(lipgloss.Left, rows...)),
+		viewScrollIndicator(s, m.width, m.offset, len(m.msgs)-m.offset-shown),
+	)
+}
+
+func (m raceControlScreen) ShortHelp() []key.Binding {
+	return []key.Binding{withHelp(m.keys.Up, "newer messages"), withHelp(m.keys.Down, "older messages")}
+}
+
+func (m raceControlScreen) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
+
+func (m raceControlScreen) title() string {
+	return "Race Control"
+}
+
+// viewRaceCtrlMsgRow returns a single race control message of the history with the lap and time it
+// was issued, wrapped to the given width.
+func viewRaceCtrlMsgRow(s *styles.Style, msg domain.RaceCtrlMsg, width int) string {
+	titleStyle, _ := raceCtrlMsgStyles(s, msg)
+	when := msg.Time.Format("15:04:05")
+	if msg.Lap > 0 {
+		when = fmt.Sprintf("LAP %-3d %s", msg.Lap, when)
+	}
+	meta := s.Subtle.Render(fmt.Sprintf("%16s", when))
+	title := titleStyle.Padding(0, 1).Width(16).Render(strings.ReplaceAll(msg.Title, "\n", " "))
+	bodyWidth := max(10, width-lipgloss.Width(meta)-lipgloss.Width(title)-2)
+	body := lipgloss.NewStyle().Width(bodyWidth).Render(wordwrap.String(msg.Body, bodyWidth))
+	return lipgloss.JoinHorizontal(lipgloss.Top, meta, " ", title, " ", body)
+}
@@ -0,0 +1,210 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bcdxn/f1cli/domain"
+	"github.com/bcdxn/f1cli/tui/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/wordwrap"
+)
+
+// sessionSummaryDrivers is the number of drivers at the top of the classification shown in the
+// session summary
+const sessionSummaryDrivers = 3
+
+// sessionOverlay is shown over the active screen when the session is suspended by a red flag,
+// timing the suspension, and once the chequered flag is shown, summarising the session. Each can be
+// dismissed until the session is suspended or finishes again.
+type sessionOverlay struct {
+	// session state
+	meeting domain.Meeting
+	drivers map[string]domain.Driver
+	redFlag domain.RaceCtrlMsg // redFlag is the latest red flag race control message
+	now     time.Time
+	// view state
+	dismissed time.Time // dismissed is when the session moved to the status of the dismissed overlay
+	// configuration
+	styles *styles.Style
+	// screen size
+	width  int
+	height int
+}
+
+// newSessionOverlay returns the session overlay.
+func newSessionOverlay(o options) sessionOverlay {
+	return sessionOverlay{
+		meeting: domain.NewMeeting(),
+		drivers: make(map[string]domain.Driver),
+		now:     time.Now(),
+		styles:  o.styles,
+	}
+}
+
+func (m sessionOverlay) Init() tea.Cmd {
+	return nil
+}
+
+func (m sessionOverlay) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case MeetingMsg:
+		m.meeting = domain.Meeting(msg)
+	case DriversMsg:
+		m.drivers = map[string]domain.Driver(msg)
+	case RaceCtrlMsg:
+		if msg.Title == domain.RaceCtrlMsgTitleFlagRed {
+			m.redFlag = domain.RaceCtrlMsg(msg)
+		}
+	case tickMsg:
+		m.now = time.Time(msg)
+	}
+	return m, nil
+}
+
+func (m sessionOverlay) View() string {
+	var panel string
+	switch {
+	case !m.isShown():
+		return ""
+	case m.meeting.Session.Status == domain.SessionStatusSuspended:
+		panel = m.viewSuspension()
+	default:
+		panel = m.viewSummary()
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, panel)
+}
+
+// since returns when the session was suspended or finished, i.e. when the overlay was first shown;
+// the zero time is returned when no overlay applies to the session status.
+func (m sessionOverlay) since() time.Time {
+	session := m.meeting.Session
+	if session.Status == domain.SessionStatusSuspended {
+		return session.StatusSince()
+	}
+	if !session.HasFinished() {
+		return time.Time{}
+	}
+	// the summary stays dismissed while the classification is finalised
+	for i := len(session.Transitions) - 1; i >= 0; i-- {
+		if session.Transitions[i].To == domain.SessionStatusFinished {
+			return session.Transitions[i].Time
+		}
+	}
+	return session.StatusSince()
+}
+
+// isShown indicates that the session is suspended or has finished and the overlay hasn't been
+// dismissed.
+func (m sessionOverlay) isShown() bool {
+	session := m.meeting.Session
+	applies := session.Status == domain.SessionStatusSuspended || session.HasFinished()
+	return applies && len(m.drivers) > 0 && !m.since().Equal(m.dismissed)
+}
+
+// dismiss hides the overlay until the session is suspended or finishes again.
+func (m sessionOverlay) dismiss() sessionOverlay {
+	m.dismissed = m.since()
+	return m
+}
+
+// viewSuspension returns the panel timing the red flag suspension of the session.
+func (m sessionOverlay) viewSuspension() string {
+	s := m.styles
+	session := m.meeting.Session
+	title := lipgloss.NewStyle().Bold(true).Padding(0, 1).Background(s.Color.Red).Foreground(s.Color.Light)
+	lines := []string{
+		title.Render("RED FLAG · SESSION SUSPENDED"),
+		"",
+		fmt.Sprintf("Suspended for %s", lipgloss.NewStyle().Bold(true).Render(formatCountdown(max(0, m.now.Sub(session.StatusSince()))))),
+	}
+	if session.Type == domain.SessionTypeRace && session.CurrentLap > 0 {
+		lines = append(lines, fmt.Sprintf("Lap %d / %d", session.CurrentLap, session.TotalLaps))
+	}
+	if n := session.Suspensions(); n > 1 {
+		lines = append(lines, s.Subtle.Render(fmt.Sprintf("red flag %d of the session", n)))
+	}
+	if m.redFlag.Body != "" && !m.redFlag.Time.Before(session.StatusSince().Add(-time.Minute)) {
+		lines = append(lines, "", wordwrap.String(m.redFlag.Body, 50))
+	}
+	lines = append(lines, "", s.Subtle.Render("esc to show the timing board"))
+	return s.DetailPanel.BorderForeground(s.Color.Red).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+// viewSummary returns the panel summarising the session once the chequered flag has been shown.
+func (m sessionOverlay) viewSummary() string {
+	s := m.styles
+	session := m.meeting.Session
+	lines := []string{
+		s.TitleBar.UnsetPaddingBottom().Render("Session Complete"),
+		lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("%s · %s", m.meeting.Name, session.Name)),
+		classificationLabel(s, session),
+		"",
+	}
+	for _, d := range sortDrivers(m.drivers)[:min(sessionSummaryDrivers, len(m.drivers))] {
+		result := driverBestLap(s, d, m.meeting)
+		if session.Type == domain.SessionTypeRace {
+			result = driverLeaderGap(d)
+		}
+		lines = append(lines, fmt.Sprintf("P%-2d %s %s", d.TimingData.Position, driverName(s, d, m.meeting), result))
+	}
+	lines = append(lines, "")
+	if d, ok := m.drivers[session.FastestLapOwner]; ok && session.FastestLapTime != "" {
+		lines = append(lines, fmt.Sprintf("Fastest lap: %s %s", d.ShortName, s.Fastest.Render(session.FastestLapTime)))
+	}
+	if d := sessionDuration(session); d > 0 {
+		lines = append(lines, fmt.Sprintf("Duration:    %s", formatCountdown(d)))
+	}
+	if n := session.Suspensions(); n > 0 {
+		lines = append(lines, fmt.Sprintf("Red flags:   %d", n))
+	}
+	lines = append(lines, "", s.Subtle.Render("esc to show the timing board"))
+	return s.DetailPanel.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+// classificationLabel returns whether the classification of a finished session is provisional or
+// final; nothing is returned until the session has finished.
+func classificationLabel(s *styles.Style, session domain.Session) string {
+	switch {
+	case session.IsClassificationFinal():
+		return s.PersonalBest.Render("Final Classification")
+	case session.HasFinished():
+		return s.NoImprovement.Render("Provisional Classification")
+	default:
+		return ""
+	}
+}
+
+// sessionDuration returns the time from the first start of the session until the chequered flag;
+// zero is returned if either is unknown.
+func sessionDuration(session domain.Session) time.Duration {
+	var start, end time.Time
+	for _, t := range session.Transitions {
+		if t.To == domain.SessionStatusStarted && start.IsZero() {
+			start = t.Time
+		}
+		if t.To == domain.SessionStatusFinished {
+			end = t.Time
+		}
+	}
+	if start.IsZero() || end.IsZero() {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// joinNonEmpty joins the non empty values with the separator.
+func joinNonEmpty(sep string, values ...string) string {
+	nonEmpty := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != "" {
+			nonEmpty = append(nonEmpty, v)
+		}
+	}
+	return strings.Join(nonEmpty, sep)
+}
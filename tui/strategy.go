@@ -0,0 +1,172 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bcdxn/f1cli/domain"
+	"github.com/bcdxn/f1cli/internal/strategy"
+	"github.com/bcdxn/f1cli/tui/styles"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+)
+
+// strategyScreen is the screen comparing the pace and degradation of each tire compound and listing
+// the fitted stints of each driver.
+type strategyScreen struct {
+	// session state
+	meeting domain.Meeting
+	drivers map[string]domain.Driver
+	laps    *strategy.LapHistory
+	// view state
+	offset int // offset is the index of the first driver shown in the scroll window of the stints table
+	// configuration
+	keys   KeyMap
+	styles *styles.Style
+	// screen size
+	width  int
+	height int
+}
+
+// newStrategyScreen returns the strategy screen built from the configuration.
+func newStrategyScreen(o options) strategyScreen {
+	return strategyScreen{
+		meeting: domain.NewMeeting(),
+		drivers: make(map[string]domain.Driver),
+		laps:    strategy.NewLapHistory(),
+		keys:    o.keys,
+		styles:  o.styles,
+	}
+}
+
+func (m strategyScreen) Init() tea.Cmd {
+	return nil
+}
+
+func (m strategyScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Up):
+			m.offset--
+		case key.Matches(msg, m.keys.Down):
+			m.offset++
+		}
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case MeetingMsg:
+		m.meeting = domain.Meeting(msg)
+	case DriversMsg:
+		m.drivers = map[string]domain.Driver(msg)
+		m.laps.Observe(m.drivers, m.meeting)
+	}
+	m.offset = scrollOffset(m.offset, -1, m.visibleRows(), len(m.drivers))
+	return m, nil
+}
+
+func (m strategyScreen) View() string {
+	s := m.styles
+	drivers := sortDrivers(m.drivers)
+	visible := drivers[m.offset:min(len(drivers), m.offset+m.visibleRows())]
+	return lipgloss.JoinVertical(
+		lipgloss.Center,
+		lipgloss.PlaceHorizontal(
+			m.width,
+			lipgloss.Center,
+			lipgloss.JoinVertical(lipgloss.Center, viewCompounds(m), viewStints(m, visible)),
+			lipgloss.WithWhitespaceChars("."),
+			lipgloss.WithWhitespaceForeground(s.Color.Subtle),
+		),
+		viewScrollIndicator(s, m.width, m.offset, len(drivers)-m.offset-len(visible)),
+	)
+}
+
+func (m strategyScreen) ShortHelp() []key.Binding {
+	return []key.Binding{withHelp(m.keys.Up, "scroll up"), withHelp(m.keys.Down, "scroll down")}
+}
+
+func (m strategyScreen) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
+
+func (m strategyScreen) title() string {
+	return "Strategy"
+}
+
+// visibleRows returns the number of drivers that fit in the stints table below the compounds table
+// and above the scroll indicator; at least one driver is always shown.
+func (m strategyScreen) visibleRows() int {
+	if m.height <= 0 {
+		return len(m.drivers)
+	}
+	return max(1, m.height-lipgloss.Height(viewCompounds(m))-tableFrameHeight-1)
+}
+
+// viewCompounds returns the table comparing the pace and degradation of each tire compound.
+func viewCompounds(m strategyScreen) string {
+	s := m.styles
+	compoundRows := make([][]string, 0)
+	for _, cp := range strategy.CompareCompounds(m.laps) {
+		deg := "-"
+		if cp.Stints > 0 {
+			deg = fmt.Sprintf("%+.3fs", cp.Degradation)
+		}
+		compoundRows = append(compoundRows, []string{
+			tireCompound(s, cp.Compound) + " " + string(cp.Compound),
+			fmt.Sprintf("%+.3fs", cp.Delta),
+			deg,
+			strconv.Itoa(cp.Stints),
+			strconv.Itoa(cp.CleanLaps),
+		})
+	}
+	if len(compoundRows) == 0 {
+		compoundRows = append(compoundRows, []string{s.Subtle.Render("waiting for clean laps"), "", "", "", ""})
+	}
+	return table.New().
+		Border(lipgloss.RoundedBorder()).
+		StyleFunc(func(row, col int) lipgloss.Style { return s.TableRow.UnsetPaddingBottom() }).
+		Headers("COMPOUND", "PACE DELTA", "DEG / LAP", "STINTS", "CLEAN LAPS").
+		Rows(compoundRows...).
+		Render()
+}
+
+// viewStints returns the table listing the fitted stints of the given drivers.
+func viewStints(m strategyScreen, drivers []domain.Driver) string {
+	s := m.styles
+	stintRows := make([][]string, 0, len(drivers))
+	for _, d := range drivers {
+		stints := make([]string, 0)
+		for _, sp := range strategy.FitStints(m.laps, d.Number) {
+			v := fmt.Sprintf("%s %dL", tireCompound(s, sp.Compound), sp.Laps)
+			if sp.HasFit {
+				v += fmt.Sprintf(" %+.3fs", sp.Degradation)
+			} else {
+				v += s.Subtle.Render(" -")
+			}
+			stints = append(stints, v)
+		}
+		if len(stints) == 0 {
+			stints = append(stints, s.Subtle.Render("-"))
+		}
+		stintRows = append(stintRows, []string{
+			driverPosition(s, d, ""),
+			driverName(s, d, m.meeting),
+			strings.Join(stints, "   "),
+		})
+	}
+	return table.New().
+		Border(lipgloss.RoundedBorder()).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			if col == 0 {
+				return s.TableRow.UnsetPaddingBottom().Align(lipgloss.Right)
+			}
+			return s.TableRow.UnsetPaddingBottom()
+		}).
+		Headers("POS", "DRIVER", "STINTS (LAPS, DEG / LAP)").
+		Rows(stintRows...).
+		Render()
+}
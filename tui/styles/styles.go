@@ -0,0 +1,128 @@
+package styles
+
+import "github.com/charmbracelet/lipgloss"
+
+type Style struct {
+	Color         Color
+	Glyphs        Glyphs
+	Doc           lipgloss.Style
+	TitleBar      lipgloss.Style
+	SubtitleBar   lipgloss.Style
+	Tab           lipgloss.Style
+	ActiveTab     lipgloss.Style
+	ToastMsgTitle lipgloss.Style
+	ToastMsgBody  lipgloss.Style
+	DetailPanel   lipgloss.Style
+	TableRow      lipgloss.Style
+	FavouriteRow  lipgloss.Style
+	// lap and sector time states
+	Fastest       lipgloss.Style // Fastest is the overall best time in the session (purple on the broadcast)
+	PersonalBest  lipgloss.Style // PersonalBest is the driver's personal best time (green on the broadcast)
+	NoImprovement lipgloss.Style // NoImprovement is a time slower than the driver's personal best (yellow on the broadcast)
+	Subtle        lipgloss.Style
+}
+
+type Color struct {
+	Red               lipgloss.Color
+	Yellow            lipgloss.Color
+	Blue              lipgloss.Color
+	Green             lipgloss.Color
+	Purple            lipgloss.Color
+	Orange            lipgloss.Color
+	WetTire           lipgloss.Color
+	IntermediateTire  lipgloss.Color
+	HardTire          lipgloss.Color
+	MediumTire        lipgloss.Color
+	SoftTire          lipgloss.Color
+	FiaBlue           lipgloss.Color
+	Fastest           lipgloss.Color
+	PersonalBest      lipgloss.Color
+	NoImprovement     lipgloss.Color
+	Favourite         lipgloss.Color
+	Light             lipgloss.Color
+	Dark              lipgloss.Color
+	Subtle            lipgloss.AdaptiveColor
+	PrimaryForeground lipgloss.AdaptiveColor
+}
+
+// Glyphs are the characters used to draw states on the timing board; themes that don't rely on
+// color alone use a distinct glyph for each state.
+type Glyphs struct {
+	Sector              string // Sector is a mini sector that hasn't been completed yet
+	SectorFastest       string // SectorFastest is a mini sector completed with the overall best time
+	SectorPersonalBest  string // SectorPersonalBest is a mini sector completed with a personal best time
+	SectorNoImprovement string // SectorNoImprovement is a mini sector completed slower than the personal best
+	FastestLap          string // FastestLap marks the driver holding the fastest lap of the session
+}
+
+// Theme is the definition of a palette from which all of the styles of the TUI are built.
+type Theme struct {
+	Color  Color
+	Glyphs Glyphs
+	// Emphasis marks lap and sector states with bold and underline in addition to color
+	Emphasis bool
+}
+
+// Default returns the styles of the default theme.
+func Default() *Style {
+	return New(DefaultTheme())
+}
+
+// New returns the styles built from the given theme.
+func New(t Theme) *Style {
+	c := t.Color
+
+	fastest := lipgloss.NewStyle().Foreground(c.Fastest)
+	personalBest := lipgloss.NewStyle().Foreground(c.PersonalBest)
+	if t.Emphasis {
+		fastest = fastest.Bold(true).Underline(true)
+		personalBest = personalBest.Underline(true)
+	}
+
+	return &Style{
+		Color:  c,
+		Glyphs: t.Glyphs,
+		Doc:    lipgloss.NewStyle().Margin(1, 1),
+		// header styles
+		TitleBar: lipgloss.NewStyle().
+			Align(lipgloss.Center).
+			Bold(true).
+			Border(lipgloss.NormalBorder(), false, false, true, false).
+			BorderForeground(c.PrimaryForeground).
+			Foreground(c.PrimaryForeground).
+			PaddingBottom(1),
+		SubtitleBar: lipgloss.NewStyle().
+			Align(lipgloss.Center).
+			Border(lipgloss.NormalBorder(), false, false, true, false).
+			BorderForeground(c.PrimaryForeground).
+			Foreground(c.PrimaryForeground),
+		// screen tab styles
+		Tab:       lipgloss.NewStyle().Foreground(c.Subtle).Padding(0, 1),
+		ActiveTab: lipgloss.NewStyle().Bold(true).Foreground(c.PrimaryForeground).Underline(true).Padding(0, 1),
+		// toast message (i.e. race control messages) style
+		ToastMsgTitle: lipgloss.NewStyle().
+			AlignVertical(lipgloss.Center).
+			Background(c.Dark).
+			Bold(true).
+			Foreground(c.Light).
+			Padding(1, 2),
+		ToastMsgBody: lipgloss.NewStyle().
+			AlignVertical(lipgloss.Center).
+			Background(c.Light).
+			Foreground(c.Dark).
+			MaxWidth(76).
+			Padding(1, 2),
+		// driver detail panel style
+		DetailPanel: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(c.PrimaryForeground).
+			Padding(0, 2),
+		TableRow: lipgloss.NewStyle().Padding(0, 1, 1, 1),
+		// rows of favourite drivers and teams on the timing table
+		FavouriteRow:  lipgloss.NewStyle().Bold(true).Foreground(c.Favourite),
+		Fastest:       fastest,
+		PersonalBest:  personalBest,
+		NoImprovement: lipgloss.NewStyle().Foreground(c.NoImprovement),
+		Subtle:        lipgloss.NewStyle().Foreground(c.Subtle),
+	}
+}
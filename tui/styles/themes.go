@@ -0,0 +1,217 @@
+package styles
+
+import (
+	"slices"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	ThemeDefault      = "default"
+	ThemeLight        = "light"
+	ThemeHighContrast = "high-contrast"
+	ThemeColourBlind  = "colour-blind"
+)
+
+// Themes returns the names of the built-in themes.
+func Themes() []string {
+	return []string{ThemeDefault, ThemeLight, ThemeHighContrast, ThemeColourBlind}
+}
+
+// Builtin returns the definition of the built-in theme with the given name.
+func Builtin(name string) (Theme, bool) {
+	switch name {
+	case "", ThemeDefault:
+		return DefaultTheme(), true
+	case ThemeLight:
+		return LightTheme(), true
+	case ThemeHighContrast:
+		return HighContrastTheme(), true
+	case ThemeColourBlind:
+		return ColourBlindTheme(), true
+	default:
+		return Theme{}, false
+	}
+}
+
+// DefaultTheme is designed for dark terminal backgrounds using the colors of the TV broadcast.
+func DefaultTheme() Theme {
+	return Theme{
+		Color: Color{
+			// F1 colors
+			Red:              lipgloss.Color("#CF040E"),
+			Yellow:           lipgloss.Color("#FAD105"),
+			Blue:             lipgloss.Color("#41B6E6"),
+			Green:            lipgloss.Color("#17C81D"),
+			Purple:           lipgloss.Color("#DA0ED3"),
+			Orange:           lipgloss.Color("#F77C14"),
+			WetTire:          lipgloss.Color("#1277EF"),
+			IntermediateTire: lipgloss.Color("#2EA43F"),
+			HardTire:         lipgloss.Color("#D4DFE8"),
+			MediumTire:       lipgloss.Color("#E4E344"),
+			SoftTire:         lipgloss.Color("#FA5A55"),
+			FiaBlue:          lipgloss.Color("#0B203B"),
+			// Timing colors
+			Fastest:       lipgloss.Color("#DA0ED3"),
+			PersonalBest:  lipgloss.Color("#17C81D"),
+			NoImprovement: lipgloss.Color("#FAD105"),
+			Favourite:     lipgloss.Color("#41B6E6"),
+			// Thematic colors
+			Light:             lipgloss.Color("#D1D4DD"),
+			Dark:              lipgloss.Color("#383838"),
+			Subtle:            lipgloss.AdaptiveColor{Light: "#D9DCCF", Dark: "#383838"},
+			PrimaryForeground: lipgloss.AdaptiveColor{Light: "#383838", Dark: "#D9DCCF"},
+		},
+		Glyphs: Glyphs{
+			Sector:              "▍",
+			SectorFastest:       "▍",
+			SectorPersonalBest:  "▍",
+			SectorNoImprovement: "▍",
+			FastestLap:          "⏱",
+		},
+	}
+}
+
+// LightTheme is designed for light terminal backgrounds; the timing colors are darkened so that
+// they remain readable on white.
+func LightTheme() Theme {
+	t := DefaultTheme()
+	t.Color.Yellow = lipgloss.Color("#B58900")
+	t.Color.Green = lipgloss.Color("#0A8F0F")
+	t.Color.Purple = lipgloss.Color("#A0009A")
+	t.Color.HardTire = lipgloss.Color("#6C7A86")
+	t.Color.MediumTire = lipgloss.Color("#A39E00")
+	t.Color.Fastest = t.Color.Purple
+	t.Color.PersonalBest = t.Color.Green
+	t.Color.NoImprovement = t.Color.Yellow
+	t.Color.Favourite = lipgloss.Color("#0069A8")
+	t.Color.Subtle = lipgloss.AdaptiveColor{Light: "#A8ABA0", Dark: "#A8ABA0"}
+	t.Color.PrimaryForeground = lipgloss.AdaptiveColor{Light: "#202020", Dark: "#202020"}
+	return t
+}
+
+// HighContrastTheme uses saturated colors on a black and white base and emphasises lap and sector
+// states for low vision or bright environments.
+func HighContrastTheme() Theme {
+	t := DefaultTheme()
+	t.Color.Fastest = lipgloss.Color("#FF00FF")
+	t.Color.PersonalBest = lipgloss.Color("#00FF00")
+	t.Color.NoImprovement = lipgloss.Color("#FFFF00")
+	t.Color.Favourite = lipgloss.Color("#00FFFF")
+	t.Color.Light = lipgloss.Color("#FFFFFF")
+	t.Color.Dark = lipgloss.Color("#000000")
+	t.Color.Subtle = lipgloss.AdaptiveColor{Light: "#767676", Dark: "#8A8A8A"}
+	t.Color.PrimaryForeground = lipgloss.AdaptiveColor{Light: "#000000", Dark: "#FFFFFF"}
+	t.Emphasis = true
+	return t
+}
+
+// ColourBlindTheme uses the Okabe-Ito palette which remains distinguishable with the common forms
+// of color vision deficiency; lap states are underlined and mini sector states are drawn with bars
+// of different heights so that no state is conveyed by color alone.
+func ColourBlindTheme() Theme {
+	t := DefaultTheme()
+	t.Color.Fastest = lipgloss.Color("#56B4E9")
+	t.Color.PersonalBest = lipgloss.Color("#009E73")
+	t.Color.NoImprovement = lipgloss.Color("#E69F00")
+	t.Color.Favourite = lipgloss.Color("#CC79A7")
+	t.Color.SoftTire = lipgloss.Color("#D55E00")
+	t.Color.MediumTire = lipgloss.Color("#F0E442")
+	t.Color.IntermediateTire = lipgloss.Color("#009E73")
+	t.Color.WetTire = lipgloss.Color("#0072B2")
+	t.Glyphs = Glyphs{
+		Sector:              "·",
+		SectorFastest:       "█",
+		SectorPersonalBest:  "▆",
+		SectorNoImprovement: "▂",
+		FastestLap:          "⏱",
+	}
+	t.Emphasis = true
+	return t
+}
+
+/* User Defined Themes
+------------------------------------------------------------------------------------------------- */
+
+// ColorNames returns the names of the colors that can be set by user defined themes.
+func ColorNames() []string {
+	names := make([]string, 0)
+	for name := range (&Theme{}).colors() {
+		names = append(names, name)
+	}
+	names = append(names, "subtle", "primary_foreground")
+	slices.Sort(names)
+	return names
+}
+
+// GlyphNames returns the names of the glyphs that can be set by user defined themes.
+func GlyphNames() []string {
+	names := make([]string, 0)
+	for name := range (&Theme{}).glyphs() {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// SetColor sets the named color of the theme, returning false if there is no such color. Adaptive
+// colors are set to the same value on light and dark backgrounds.
+func (t *Theme) SetColor(name, value string) bool {
+	if c, ok := t.colors()[name]; ok {
+		*c = lipgloss.Color(value)
+		return true
+	}
+	switch name {
+	case "subtle":
+		t.Color.Subtle = lipgloss.AdaptiveColor{Light: value, Dark: value}
+	case "primary_foreground":
+		t.Color.PrimaryForeground = lipgloss.AdaptiveColor{Light: value, Dark: value}
+	default:
+		return false
+	}
+	return true
+}
+
+// SetGlyph sets the named glyph of the theme, returning false if there is no such glyph.
+func (t *Theme) SetGlyph(name, value string) bool {
+	g, ok := t.glyphs()[name]
+	if ok {
+		*g = value
+	}
+	return ok
+}
+
+// colors returns the (non-adaptive) colors of the theme keyed by their name in user defined themes.
+func (t *Theme) colors() map[string]*lipgloss.Color {
+	return map[string]*lipgloss.Color{
+		"red":               &t.Color.Red,
+		"yellow":            &t.Color.Yellow,
+		"blue":              &t.Color.Blue,
+		"green":             &t.Color.Green,
+		"purple":            &t.Color.Purple,
+		"orange":            &t.Color.Orange,
+		"wet_tire":          &t.Color.WetTire,
+		"intermediate_tire": &t.Color.IntermediateTire,
+		"hard_tire":         &t.Color.HardTire,
+		"medium_tire":       &t.Color.MediumTire,
+		"soft_tire":         &t.Color.SoftTire,
+		"fia_blue":          &t.Color.FiaBlue,
+		"fastest":           &t.Color.Fastest,
+		"personal_best":     &t.Color.PersonalBest,
+		"no_improvement":    &t.Color.NoImprovement,
+		"favourite":         &t.Color.Favourite,
+		"light":             &t.Color.Light,
+		"dark":              &t.Color.Dark,
+	}
+}
+
+// glyphs returns the glyphs of the theme keyed by their name in user defined themes.
+func (t *Theme) glyphs() map[string]*string {
+	return map[string]*string{
+		"sector":                &t.Glyphs.Sector,
+		"sector_fastest":        &t.Glyphs.SectorFastest,
+		"sector_personal_best":  &t.Glyphs.SectorPersonalBest,
+		"sector_no_improvement": &t.Glyphs.SectorNoImprovement,
+		"fastest_lap":           &t.Glyphs.FastestLap,
+	}
+}
@@ -0,0 +1,170 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bcdxn/f1cli/domain"
+	"github.com/bcdxn/f1cli/tui/styles"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+)
+
+const (
+	// maxRPM is the engine speed drawn as a full RPM bar
+	maxRPM = 13000
+	// barWidth is the width of the RPM and throttle bars
+	barWidth = 10
+)
+
+// telemetryScreen is the screen showing the latest car data of every driver, e.g. speed, gear,
+// throttle and brake application.
+type telemetryScreen struct {
+	// session state
+	meeting domain.Meeting
+	drivers map[string]domain.Driver
+	// view state
+	offset int // offset is the index of the first driver shown in the scroll window of the table
+	// configuration
+	keys   KeyMap
+	styles *styles.Style
+	// screen size
+	width  int
+	height int
+}
+
+// newTelemetryScreen returns the telemetry screen built from the configuration.
+func newTelemetryScreen(o options) telemetryScreen {
+	return telemetryScreen{
+		meeting: domain.NewMeeting(),
+		drivers: make(map[string]domain.Driver),
+		keys:    o.keys,
+		styles:  o.styles,
+	}
+}
+
+func (m telemetryScreen) Init() tea.Cmd {
+	return nil
+}
+
+func (m telemetryScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Up):
+			m.offset--
+		case key.Matches(msg, m.keys.Down):
+			m.offset++
+		}
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case MeetingMsg:
+		m.meeting = domain.Meeting(msg)
+	case DriversMsg:
+		m.drivers = map[string]domain.Driver(msg)
+	}
+	m.offset = scrollOffset(m.offset, -1, m.visibleRows(), len(m.drivers))
+	return m, nil
+}
+
+func (m telemetryScreen) View() string {
+	s := m.styles
+	drivers := sortDrivers(m.drivers)
+	visible := drivers[m.offset:min(len(drivers), m.offset+m.visibleRows())]
+
+	rows := make([][]string, 0, len(visible))
+	for _, d := range visible {
+		tel := d.Telemetry
+		rows = append(rows, []string{
+			driverPosition(s, d, ""),
+			driverName(s, d, m.meeting),
+			strconv.Itoa(tel.Speed),
+			telemetryGear(s, tel.Gear),
+			bar(s, tel.RPM, maxRPM, s.NoImprovement) + fmt.Sprintf(" %5d", tel.RPM),
+			bar(s, tel.Throttle, 100, s.PersonalBest) + fmt.Sprintf(" %3d%%", tel.Throttle),
+			telemetryBrake(s, tel.Brake),
+			telemetryDRS(s, tel),
+		})
+	}
+	t := table.New().
+		Border(lipgloss.RoundedBorder()).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			if col == 0 || col == 2 || col == 3 {
+				return s.TableRow.UnsetPaddingBottom().Align(lipgloss.Right)
+			}
+			return s.TableRow.UnsetPaddingBottom()
+		}).
+		Headers("POS", "DRIVER", "KM/H", "GEAR", "RPM", "THROTTLE", "BRAKE", "DRS").
+		Rows(rows...)
+
+	return lipgloss.JoinVertical(
+		lipgloss.Center,
+		lipgloss.PlaceHorizontal(
+			m.width,
+			lipgloss.Center,
+			t.Render(),
+			lipgloss.WithWhitespaceChars("."),
+			lipgloss.WithWhitespaceForeground(s.Color.Subtle),
+		),
+		viewScrollIndicator(s, m.width, m.offset, len(drivers)-m.offset-len(visible)),
+	)
+}
+
+func (m telemetryScreen) ShortHelp() []key.Binding {
+	return []key.Binding{withHelp(m.keys.Up, "scroll up"), withHelp(m.keys.Down, "scroll down")}
+}
+
+func (m telemetryScreen) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
+
+func (m telemetryScreen) title() string {
+	return "Telemetry"
+}
+
+// visibleRows returns the number of drivers that fit in the telemetry table above the scroll
+// indicator; at least one driver is always shown.
+func (m telemetryScreen) visibleRows() int {
+	if m.height <= 0 {
+		return len(m.drivers)
+	}
+	return max(1, m.height-tableFrameHeight-1)
+}
+
+// bar returns a horizontal bar filled in proportion to the value of the total.
+func bar(s *styles.Style, value, total int, style lipgloss.Style) string {
+	n := max(0, min(barWidth, value*barWidth/total))
+	return style.Render(strings.Repeat("█", n)) + s.Subtle.Render(strings.Repeat("░", barWidth-n))
+}
+
+// telemetryGear returns the selected gear formatted for the telemetry table.
+func telemetryGear(s *styles.Style, gear int) string {
+	if gear == 0 {
+		return s.Subtle.Render("N")
+	}
+	return strconv.Itoa(gear)
+}
+
+// telemetryBrake returns the brake application formatted for the telemetry table.
+func telemetryBrake(s *styles.Style, brake bool) string {
+	if !brake {
+		return s.Subtle.Render("-")
+	}
+	return lipgloss.NewStyle().Bold(true).Foreground(s.Color.Red).Render("BRAKE")
+}
+
+// telemetryDRS returns the DRS state formatted for the telemetry table.
+func telemetryDRS(s *styles.Style, tel domain.Telemetry) string {
+	switch {
+	case tel.DRSOpen:
+		return s.Fastest.Render("OPEN")
+	case tel.DRSAvailable:
+		return s.PersonalBest.Render("READY")
+	default:
+		return s.Subtle.Render("-")
+	}
+}
@@ -0,0 +1,583 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bcdxn/f1cli/domain"
+	"github.com/bcdxn/f1cli/internal/strategy"
+	"github.com/bcdxn/f1cli/tui/styles"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+)
+
+// TimingTable is the component showing the timing table of the session, the detail of the selected
+// driver and where they would rejoin after a pit stop. It is updated by DriversMsg and MeetingMsg,
+// handles the up, down, detail, close, pit column and compact key bindings and fits the table to the
+// size of a tea.WindowSizeMsg.
+type TimingTable struct {
+	// session state
+	meeting domain.Meeting
+	drivers map[string]domain.Driver
+	pitLoss *strategy.PitLoss
+	// view state
+	selected      string // selected is the number of the driver selected on the timing board
+	showDetail    bool
+	showPitColumn bool
+	compact       bool // compact draws the timing table with a single line per driver
+	offset        int  // offset is the index of the first driver shown in the scroll window of the timing table
+	// configuration
+	keys              KeyMap
+	raceColumns       []string
+	qualifyingColumns []string
+	favourites        map[string]bool // favourites contains racing numbers, abbreviations and `TEAM:` prefixed team names
+	styles            *styles.Style
+	// screen size
+	width  int
+	height int
+}
+
+// NewTimingTable returns the timing table component configured by the given options.
+func NewTimingTable(opts ...TUIOption) TimingTable {
+	return newTimingTable(newOptions(opts))
+}
+
+// newTimingTable returns the timing table built from the configuration.
+func newTimingTable(o options) TimingTable {
+	return TimingTable{
+		meeting:           domain.NewMeeting(),
+		drivers:           make(map[string]domain.Driver),
+		pitLoss:           o.pitLoss,
+		showPitColumn:     o.showPitColumn,
+		compact:           o.compact,
+		keys:              o.keys,
+		raceColumns:       o.raceColumns,
+		qualifyingColumns: o.qualifyingColumns,
+		favourites:        o.favourites,
+		styles:            o.styles,
+	}
+}
+
+func (m TimingTable) Init() tea.Cmd {
+	return nil
+}
+
+func (m TimingTable) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Up):
+			m.selected = moveSelection(m.drivers, m.selected, -1)
+		case key.Matches(msg, m.keys.Down):
+			m.selected = moveSelection(m.drivers, m.selected, 1)
+		case key.Matches(msg, m.keys.Detail):
+			if m.selected == "" {
+				m.selected = moveSelection(m.drivers, m.selected, 1)
+			}
+			m.showDetail = !m.showDetail
+		case key.Matches(msg, m.keys.Close):
+			m.showDetail = false
+		case key.Matches(msg, m.keys.PitColumn):
+			m.showPitColumn = !m.showPitColumn
+		case key.Matches(msg, m.keys.Compact):
+			m.compact = !m.compact
+		}
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case MeetingMsg:
+		m.meeting = domain.Meeting(msg)
+	case DriversMsg:
+		m.drivers = map[string]domain.Driver(msg)
+		m.pitLoss.Observe(m.drivers)
+	}
+	return m.scroll(), nil
+}
+
+func (m TimingTable) View() string {
+	sections := []string{viewTable(m), viewScrollPadding(m)}
+	if m.showDetail {
+		sections = append(sections, viewDriverDetail(m), viewPadding(m.styles, m.width))
+	}
+	return lipgloss.JoinVertical(lipgloss.Center, sections...)
+}
+
+func (m TimingTable) ShortHelp() []key.Binding {
+	return []key.Binding{m.keys.Up, m.keys.Down, m.keys.Detail}
+}
+
+func (m TimingTable) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{m.keys.Up, m.keys.Down, m.keys.Detail, m.keys.Close},
+		{m.keys.PitColumn, m.keys.Compact},
+	}
+}
+
+func (m TimingTable) title() string {
+	return "Timing"
+}
+
+/* Timing Table
+------------------------------------------------------------------------------------------------- */
+
+// viewTable returns the timing table of the current session centered on the screen.
+func viewTable(m TimingTable) string {
+	t := ""
+	switch m.meeting.Session.Type {
+	case domain.SessionTypeQualifying, domain.SessionTypeRace:
+		t = viewTimingTable(m)
+	}
+
+	return lipgloss.PlaceHorizontal(
+		m.width,
+		lipgloss.Center,
+		t,
+		lipgloss.WithWhitespaceChars("."),
+		lipgloss.WithWhitespaceForeground(m.styles.Color.Subtle),
+	)
+}
+
+// viewTimingTable returns the timing table with the configured columns for the current session;
+// the rows of favourite drivers are highlighted. Columns are dropped when the table is wider than
+// the terminal and only the rows in the scroll window are shown when it is taller.
+func viewTimingTable(m TimingTable) string {
+	drivers := sortDrivers(m.drivers)
+	names := m.tableColumns()
+
+	data := tableData{meeting: m.meeting, drivers: m.drivers, selected: m.selected, styles: m.styles}
+	if slices.Contains(names, ColumnPitRejoin) {
+		pitLoss, _ := m.pitLoss.Estimate(m.meeting)
+		data.rejoins = strategy.PredictRejoin(m.drivers, pitLoss)
+	}
+
+	compact := m.isCompact()
+	visible := drivers[m.offset:min(len(drivers), m.offset+m.visibleRows(compact))]
+	names = m.fitColumns(names, m.width, func(names []string) string {
+		return renderTimingTable(m, visible, names, data, compact)
+	})
+	return renderTimingTable(m, visible, names, data, compact)
+}
+
+// renderTimingTable renders the timing table of the given drivers and columns; compact tables draw
+// a single line per driver.
+func renderTimingTable(m TimingTable, drivers []domain.Driver, names []string, data tableData, compact bool) string {
+	s := m.styles
+	baseStyle := s.TableRow
+	if compact {
+		baseStyle = baseStyle.UnsetPaddingBottom()
+	}
+	rows := make([][]string, 0, len(drivers))
+	headers := make([]string, 0, len(names))
+	for _, name := range names {
+		headers = append(headers, columns[name].header)
+	}
+
+	for _, d := range drivers {
+		row := make([]string, 0, len(names))
+		for _, name := range names {
+			row = append(row, columns[name].render(d, data))
+		}
+		rows = append(rows, row)
+	}
+
+	t := table.New().
+		Border(lipgloss.RoundedBorder()).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			style := baseStyle
+
+			if row == len(rows)-1 {
+				style = style.Padding(0, 1)
+			}
+			if columns[names[col]].alignRight {
+				style = style.Align(lipgloss.Right)
+			}
+			if row >= 0 && m.isFavourite(drivers[row]) {
+				style = style.Inherit(s.FavouriteRow)
+			}
+
+			return style
+		}).
+		Headers(headers...).
+		Rows(rows...)
+
+	return t.Render()
+}
+
+// driverPosition returns the driver position formatted for the timing table, marking the driver
+// that is currently selected.
+func driverPosition(s *styles.Style, d domain.Driver, selected string) string {
+	v := "-"
+	if pos := d.TimingData.Position; pos != 0 {
+		v = strconv.Itoa(pos)
+	}
+	if d.TimingData.IsRetired {
+		v = "DNF"
+	}
+	if d.TimingData.IsKnockedOut || d.TimingData.IsRetired || !d.TimingData.ShowPosition {
+		v = lipgloss.NewStyle().Foreground(s.Color.Subtle).Render(v)
+	}
+	if d.Number == selected {
+		v = "▸ " + v
+	}
+	return v
+}
+
+// driverName returns the driver name formatted with the team color and fastsest lap indicator when
+// appropriate formatted for the timing table
+func driverName(s *styles.Style, d domain.Driver, m domain.Meeting) string {
+	c := lipgloss.Color(d.TeamColor)
+	n := lipgloss.NewStyle().Foreground(c).Render("▍")
+
+	if d.TimingData.IsKnockedOut || d.TimingData.IsRetired {
+		n += lipgloss.NewStyle().Foreground(s.Color.Subtle).Render(d.ShortName) + " "
+	} else {
+		n += d.ShortName + " "
+	}
+
+	if m.Session.Type == domain.SessionTypeRace && d.Number == m.Session.FastestLapOwner {
+		n += s.Fastest.Render(s.Glyphs.FastestLap)
+		return n
+	} else if !d.TimingData.IsRetired && d.TimingData.IsInPit {
+		n += lipgloss.NewStyle().Foreground(s.Color.Subtle).Render("P")
+	} else if m.Session.Type == domain.SessionTypeQualifying && !d.TimingData.IsKnockedOut {
+		n += driverTireCompound(s, d)
+	}
+	return n
+}
+
+var (
+	leaderRe = regexp.MustCompile(`LAP`)
+)
+
+// driverIntervalGap returns the driver interval to the car ahead formatted for the timing table.
+func driverIntervalGap(s *styles.Style, d domain.Driver) string {
+	if d.TimingData.IntervalGap == "" || leaderRe.MatchString(d.TimingData.IntervalGap) {
+		return "-"
+	}
+	if d.TimingData.IsRetired || d.TimingData.IsKnockedOut {
+		return s.Subtle.Render("-")
+	}
+	return d.TimingData.IntervalGap
+}
+
+// driverLeaderGap returns the driver interval to the leader car formatted for the timing table.
+func driverLeaderGap(d domain.Driver) string {
+	if d.TimingData.LeaderGap == "" || d.TimingData.IsRetired || d.TimingData.IsKnockedOut || leaderRe.MatchString(d.TimingData.LeaderGap) {
+		return "-"
+	}
+	return d.TimingData.LeaderGap
+}
+
+// driverTireCompound returns the driver's current tire compound formatted for the timing table.
+func driverTireCompound(s *styles.Style, d domain.Driver) string {
+	if d.TimingData.IsRetired {
+		return "-"
+	}
+	return tireCompound(s, d.TimingData.TireCompound)
+}
+
+// tireCompound returns the abbreviated tire compound colored by compound.
+func tireCompound(s *styles.Style, c domain.TireCompound) string {
+	if c == "" {
+		return "-"
+	}
+	t := c[:1]
+	tireStyle := lipgloss.NewStyle()
+	switch c {
+	case domain.TireCompoundSoft:
+		tireStyle = tireStyle.Foreground(s.Color.SoftTire)
+	case domain.TireCompoundMedium:
+		tireStyle = tireStyle.Foreground(s.Color.MediumTire)
+	case domain.TireCompoundIntermediate:
+		tireStyle = tireStyle.Foreground(s.Color.IntermediateTire)
+	case domain.TireCompoundFullWet:
+		tireStyle = tireStyle.Foreground(s.Color.WetTire)
+	case domain.TireCompoundUnknown:
+		t = "X"
+	}
+
+	return tireStyle.Render(string(t))
+}
+
+func driverStint(s *styles.Style, d domain.Driver) string {
+	if d.TimingData.IsRetired {
+		return s.Subtle.Render("-")
+	}
+
+	return fmt.Sprintf("%s %d Laps", driverTireCompound(s, d), d.TimingData.TireLapCount)
+}
+
+func driverLastLap(s *styles.Style, d domain.Driver, m domain.Meeting) string {
+	v := "-"
+
+	if d.TimingData.LastLap.Time != "" {
+		v = d.TimingData.LastLap.Time
+
+		if d.TimingData.IsRetired {
+			v = s.Subtle.Render(v)
+		} else if d.Number == m.Session.FastestLapOwner && d.TimingData.LastLap.Time == d.TimingData.BestLapTime {
+			v = s.Fastest.Render(v)
+		} else if d.TimingData.LastLap.IsPersonalBest {
+			v = s.PersonalBest.Render(v)
+		} else {
+			v = s.NoImprovement.Render(v)
+		}
+	}
+
+	return v
+}
+
+func driverBestLap(s *styles.Style, d domain.Driver, m domain.Meeting) string {
+	v := d.TimingData.BestLapTime
+
+	if d.TimingData.BestLapTime == "" {
+		v = "-"
+	}
+
+	if d.TimingData.IsKnockedOut || d.TimingData.IsRetired {
+		return s.Subtle.Render(v)
+	}
+
+	if d.Number == m.Session.FastestLapOwner {
+		v = s.Fastest.Render(v)
+	}
+
+	return v
+}
+
+func driverBestLapInPart(s *styles.Style, d domain.Driver, part int) string {
+	v := d.TimingData.BestLapTimes[part]
+
+	if v == "" {
+		v = "-"
+	}
+
+	if d.TimingData.IsKnockedOut || d.TimingData.IsRetired {
+		return s.Subtle.Render(v)
+	}
+
+	return v
+}
+
+// driverPitRejoin returns the predicted rejoin position and the driver that would be directly ahead
+// if the driver were to pit now formatted for the timing table.
+func driverPitRejoin(s *styles.Style, d domain.Driver, rejoins map[string]strategy.Rejoin, drivers map[string]domain.Driver) string {
+	r, ok := rejoins[d.Number]
+	if !ok {
+		return s.Subtle.Render("-")
+	}
+	v := fmt.Sprintf("P%d", r.Position)
+	if r.Ahead != "" {
+		v += fmt.Sprintf(" %s +%.1f", drivers[r.Ahead].ShortName, r.GapAhead)
+	}
+	return v
+}
+
+func driverSectors(s *styles.Style, d domain.Driver, m domain.Meeting) string {
+	if d.TimingData.IsKnockedOut || d.TimingData.IsRetired || len(d.TimingData.Sectors) < 1 {
+		return s.Subtle.Render("-")
+	}
+
+	if m.Session.Type == domain.SessionTypeQualifying && d.TimingData.IsPitOut {
+		return s.Subtle.Render("OUT LAP ")
+	}
+
+	segments := make([]string, 0)
+
+	// iterate through the sectors (there's always 3)
+	for i := 0; i < 3; i++ {
+		// iterate through the segments in order (there's a variable number)
+		secNum := strconv.Itoa(i)
+		segKeys := make([]string, 0, len(d.TimingData.Sectors[secNum].Segments))
+		for k := range d.TimingData.Sectors[secNum].Segments {
+			segKeys = append(segKeys, k)
+		}
+		sort.Strings(segKeys)
+		for _, segKey := range segKeys {
+			switch d.TimingData.Sectors[secNum].Segments[segKey].Status {
+			case domain.SectorStatusNotPersonalBest:
+				segments = append(segments, s.NoImprovement.Render(s.Glyphs.SectorNoImprovement))
+			case domain.SectorStatusPersonalBest:
+				segments = append(segments, s.PersonalBest.Render(s.Glyphs.SectorPersonalBest))
+			case domain.SectorStatusOverallBest:
+				segments = append(segments, s.Fastest.Render(s.Glyphs.SectorFastest))
+			default:
+				segments = append(segments, s.Subtle.Render(s.Glyphs.Sector))
+			}
+		}
+	}
+
+	return lipgloss.JoinHorizontal(
+		lipgloss.Center,
+		segments...,
+	)
+}
+
+// viewStrategy returns the strategy view component comparing the pace and degradation of each tire
+// compound and listing the fitted stints of each driver.
+func sortDrivers(driverMap map[string]domain.Driver) []domain.Driver {
+	drivers := make([]domain.Driver, 0, len(driverMap))
+	for _, driver := range driverMap {
+		drivers = append(drivers, driver)
+	}
+
+	sort.Slice(drivers, func(i, j int) bool {
+		p1 := drivers[i].TimingData.Position
+		p2 := drivers[j].TimingData.Position
+		if drivers[i].TimingData.IsRetired {
+			// DNF drivers should appear at the bottom of the timing board and be ordered by number of
+			// laps completed
+			p1 = 100 - drivers[i].TimingData.NumberOfLaps
+		}
+		if drivers[j].TimingData.IsRetired {
+			p2 = 100 - drivers[j].TimingData.NumberOfLaps
+		}
+		// In the case that two drivers DNF on the same lap or otherwise are reported to have the same
+		// position simply rank them by their driver number
+		if p1 == p2 {
+			num1, _ := strconv.Atoi(drivers[i].Number)
+			num2, _ := strconv.Atoi(drivers[j].Number)
+			return p1-num1 < p2-num2
+		}
+
+		return p1 < p2
+	})
+
+	return drivers
+}
+
+// viewDriverDetail returns the detail view component for the selected driver including the
+// predicted outcome of a pit stop during races.
+func viewDriverDetail(m TimingTable) string {
+	s := m.styles
+	d, ok := m.drivers[m.selected]
+	if !m.showDetail || !ok {
+		return ""
+	}
+
+	lines := []string{
+		s.TitleBar.UnsetPaddingBottom().Render(fmt.Sprintf("%s  #%s  %s", d.Name, d.Number, d.TeamName)),
+		fmt.Sprintf("Position: %s   Interval: %s   Leader: %s", driverPosition(s, d, ""), driverIntervalGap(s, d), driverLeaderGap(d)),
+		fmt.Sprintf("Last Lap: %s   Best Lap: %s   Tire: %s", driverLastLap(s, d, m.meeting), driverBestLap(s, d, m.meeting), driverStint(s, d)),
+	}
+
+	if m.meeting.Session.Type == domain.SessionTypeRace {
+		pitLoss, src := m.pitLoss.Estimate(m.meeting)
+		lines = append(lines, "", fmt.Sprintf("If %s pits now (pit loss %.1fs, %s)", d.ShortName, pitLoss, pitLossSource(src, m.pitLoss.Observed())))
+		r, ok := strategy.PredictRejoin(m.drivers, pitLoss)[d.Number]
+		if !ok {
+			lines = append(lines, s.Subtle.Render("no prediction available"))
+		} else {
+			lines = append(lines, fmt.Sprintf("Rejoins: P%d", r.Position))
+			if r.Ahead != "" {
+				lines = append(lines, fmt.Sprintf("Ahead:   %s +%.1fs", m.drivers[r.Ahead].ShortName, r.GapAhead))
+			}
+			if r.Behind != "" {
+				lines = append(lines, fmt.Sprintf("Behind:  %s -%.1fs", m.drivers[r.Behind].ShortName, r.GapBehind))
+			}
+		}
+	}
+
+	return lipgloss.PlaceHorizontal(
+		m.width,
+		lipgloss.Center,
+		s.DetailPanel.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)),
+		lipgloss.WithWhitespaceChars("."),
+		lipgloss.WithWhitespaceForeground(s.Color.Subtle),
+	)
+}
+
+// pitLossSource returns a human readable description of where the pit loss estimate came from.
+func pitLossSource(src strategy.PitLossSource, observed int) string {
+	switch src {
+	case strategy.PitLossSourceConfigured:
+		return "configured"
+	case strategy.PitLossSourceObserved:
+		return fmt.Sprintf("learned from %d stops", observed)
+	case strategy.PitLossSourceCircuit:
+		return "circuit default"
+	default:
+		return "default"
+	}
+}
+
+// isFavourite indicates if the driver or their team was configured as a favourite.
+func (m TimingTable) isFavourite(d domain.Driver) bool {
+	return m.favourites[d.Number] || m.favourites[strings.ToUpper(d.ShortName)] || m.favourites["TEAM:"+strings.ToUpper(d.TeamName)]
+}
+
+// moveSelection returns the number of the driver that is offset positions away from the currently
+// selected driver on the timing board; the first driver is selected if there is no selection.
+func moveSelection(driverMap map[string]domain.Driver, selected string, offset int) string {
+	drivers := sortDrivers(driverMap)
+	if len(drivers) == 0 {
+		return selected
+	}
+	for i, d := range drivers {
+		if d.Number == selected {
+			return drivers[max(0, min(len(drivers)-1, i+offset))].Number
+		}
+	}
+	return drivers[0].Number
+}
+
+// handleWindowSizeMsg is a tea.Msg handler that handles window resize events and stores the current
+/* Layout
+------------------------------------------------------------------------------------------------- */
+
+const (
+	// tableFrameHeight is the number of lines of the timing table that aren't driver rows, i.e. the
+	// top and bottom borders, the headers and the header separator
+	tableFrameHeight = 4
+)
+
+// tableHeight returns the number of lines of the screen available to the timing table once the
+// scroll indicator and driver detail are drawn.
+func (m TimingTable) tableHeight() int {
+	h := m.height - 1
+	if m.showDetail {
+		h -= lipgloss.Height(viewDriverDetail(m)) + 1
+	}
+	return h
+}
+
+// isCompact indicates if the timing table is drawn with a single line per driver, either because
+// compact mode is enabled or because the padded rows of every driver don't fit in the terminal.
+func (m TimingTable) isCompact() bool {
+	return m.compact || m.visibleRows(false) < len(m.drivers)
+}
+
+// visibleRows returns the number of driver rows that fit in the timing table; at least one row is
+// always shown.
+func (m TimingTable) visibleRows(compact bool) int {
+	if m.height <= 0 {
+		return len(m.drivers)
+	}
+	h := m.tableHeight() - tableFrameHeight
+	if !compact {
+		// padded rows take two lines except for the last row
+		h = (h + 1) / 2
+	}
+	return max(1, h)
+}
+
+// scroll returns the screen with the scroll window of the timing table moved so that the selected
+// driver is visible.
+func (m TimingTable) scroll() TimingTable {
+	i := slices.IndexFunc(sortDrivers(m.drivers), func(d domain.Driver) bool { return d.Number == m.selected })
+	m.offset = scrollOffset(m.offset, i, m.visibleRows(m.isCompact()), len(m.drivers))
+	return m
+}
+
+// viewScrollPadding returns the padding below the timing table, indicating how many drivers are
+// hidden above and below the scroll window.
+func viewScrollPadding(m TimingTable) string {
+	shown := min(len(m.drivers)-m.offset, m.visibleRows(m.isCompact()))
+	return viewScrollIndicator(m.styles, m.width, m.offset, len(m.drivers)-m.offset-shown)
+}
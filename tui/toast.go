@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"github.com/bcdxn/f1cli/domain"
+	"github.com/bcdxn/f1cli/tui/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/wordwrap"
+)
+
+// RaceCtrlToast is the component showing the latest race control message, colored by its flag or
+// category. It is updated by RaceCtrlMsg and sized to the width of a tea.WindowSizeMsg; nothing is
+// shown until the first message is received.
+type RaceCtrlToast struct {
+	msg    domain.RaceCtrlMsg
+	styles *styles.Style
+	width  int
+}
+
+// NewRaceCtrlToast returns the race control toast component configured by the given options.
+func NewRaceCtrlToast(opts ...TUIOption) RaceCtrlToast {
+	return newRaceCtrlToast(newOptions(opts))
+}
+
+// newRaceCtrlToast returns the race control toast built from the configuration; the theme is the
+// only option that applies to it.
+func newRaceCtrlToast(o options) RaceCtrlToast {
+	return RaceCtrlToast{styles: o.styles}
+}
+
+func (m RaceCtrlToast) Init() tea.Cmd {
+	return nil
+}
+
+func (m RaceCtrlToast) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+	case RaceCtrlMsg:
+		m.msg = domain.RaceCtrlMsg(msg)
+	}
+	return m, nil
+}
+
+func (m RaceCtrlToast) View() string {
+	s := m.styles
+	if m.msg.Body == "" {
+		return ""
+	}
+	title := m.msg.Title
+	body := m.msg.Body
+	titleStyle, bodyStyle := raceCtrlMsgStyles(s, m.msg)
+
+	renderedTitle := titleStyle.Render(title)
+	if m.width > 0 {
+		// narrow terminals wrap the message body rather than overflowing
+		bodyStyle = bodyStyle.MaxWidth(max(bodyStyle.GetHorizontalFrameSize()+1, min(bodyStyle.GetMaxWidth(), m.width-lipgloss.Width(renderedTitle))))
+	}
+	renderedBody := bodyStyle.Render(wordwrap.String(body, bodyStyle.GetMaxWidth()-(bodyStyle.GetPaddingLeft()+bodyStyle.GetPaddingRight())))
+
+	if lipgloss.Height(renderedTitle) > lipgloss.Height(renderedBody) {
+		renderedBody = bodyStyle.Height(lipgloss.Height(renderedTitle)).Render(body)
+	} else {
+		renderedTitle = titleStyle.Height(lipgloss.Height(renderedBody)).Render(title)
+	}
+
+	return lipgloss.PlaceHorizontal(
+		m.width,
+		lipgloss.Center,
+		lipgloss.JoinHorizontal(
+			lipgloss.Center,
+			renderedTitle,
+			renderedBody,
+		),
+		lipgloss.WithWhitespaceChars("."),
+		lipgloss.WithWhitespaceForeground(s.Color.Subtle),
+	)
+}
+
+// raceCtrlMsgStyles returns the styles of the title and body of a race control message, colored by
+// the flag or the category of the message; other messages keep the plain toast styles.
+func raceCtrlMsgStyles(s *styles.Style, msg domain.RaceCtrlMsg) (titleStyle, bodyStyle lipgloss.Style) {
+	titleStyle, bodyStyle = s.ToastMsgTitle, s.ToastMsgBody
+	switch msg.Category {
+	case domain.RaceCtrlMsgCategoryFIA:
+		titleStyle = s.ToastMsgTitle.Background(s.Color.FiaBlue).Foreground(s.Color.Light)
+		bodyStyle = s.ToastMsgBody.Background(s.Color.Light).Foreground(s.Color.FiaBlue)
+	case domain.RaceCtrlMsgCategoryTrackStatus:
+		bodyStyle = s.ToastMsgBody.Background(s.Color.Light).Foreground(s.Color.Dark)
+		switch msg.Title {
+		case domain.RaceCtrlMsgTitleFlagBlue:
+			titleStyle = s.ToastMsgTitle.Background(s.Color.Blue).Foreground(s.Color.Dark)
+		case domain.RaceCtrlMsgTitleFlagYellow:
+			titleStyle = s.ToastMsgTitle.Background(s.Color.Yellow).Foreground(s.Color.Dark)
+		case domain.RaceCtrlMsgTitleFlagDoubleYellow:
+			titleStyle = s.ToastMsgTitle.Background(s.Color.Yellow).Foreground(s.Color.Dark)
+		case domain.RaceCtrlMsgTitleVSC:
+			titleStyle = s.ToastMsgTitle.Background(s.Color.Yellow).Foreground(s.Color.Dark)
+		case domain.RaceCtrlMsgTitleSC:
+			titleStyle = s.ToastMsgTitle.Background(s.Color.Yellow).Foreground(s.Color.Dark)
+		case domain.RaceCtrlMsgTitleFlagBW:
+			titleStyle = s.ToastMsgTitle.Background(s.Color.Dark).Foreground(s.Color.Light)
+		case domain.RaceCtrlMsgTitleFlagRed:
+			titleStyle = s.ToastMsgTitle.Background(s.Color.Red).Foreground(s.Color.Light)
+		case domain.RaceCtrlMsgTitleFlagGreen:
+			titleStyle = s.ToastMsgTitle.Background(s.Color.Green).Foreground(s.Color.Dark)
+		default:
+			titleStyle = s.ToastMsgTitle.Background(s.Color.Dark)
+		}
+	}
+	return titleStyle, bodyStyle
+}
@@ -0,0 +1,133 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/bcdxn/f1cli/domain"
+	"github.com/bcdxn/f1cli/tui/styles"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// maxWeatherSamples is the number of weather readings kept to draw the temperature trends
+	maxWeatherSamples = 60
+)
+
+// weatherScreen is the screen showing the latest weather at the circuit and the trend of the air and
+// track temperatures during the session.
+type weatherScreen struct {
+	// session state
+	weather domain.Weather
+	samples []domain.Weather // samples are the distinct weather readings received, oldest first
+	// configuration
+	keys   KeyMap
+	styles *styles.Style
+	// screen size
+	width  int
+	height int
+}
+
+// newWeatherScreen returns the weather screen built from the configuration.
+func newWeatherScreen(o options) weatherScreen {
+	return weatherScreen{
+		samples: make([]domain.Weather, 0),
+		keys:    o.keys,
+		styles:  o.styles,
+	}
+}
+
+func (m weatherScreen) Init() tea.Cmd {
+	return nil
+}
+
+func (m weatherScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case MeetingMsg:
+		w := msg.Weather
+		// the meeting is sent on every session update; only new readings are kept for the trends
+		if w != (domain.Weather{}) && w != m.weather {
+			m.weather = w
+			m.samples = append(m.samples, w)
+			if len(m.samples) > maxWeatherSamples {
+				m.samples = m.samples[1:]
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m weatherScreen) View() string {
+	s := m.styles
+	if len(m.samples) == 0 {
+		return lipgloss.PlaceHorizontal(m.width, lipgloss.Center, s.Subtle.Render("waiting for weather data"))
+	}
+	w := m.weather
+	rain := s.Subtle.Render("dry")
+	if w.Rainfall {
+		rain = lipgloss.NewStyle().Bold(true).Foreground(s.Color.WetTire).Render("RAIN")
+	}
+	lines := []string{
+		s.TitleBar.UnsetPaddingBottom().Render("Weather"),
+		fmt.Sprintf("Air:      %5.1f°C   %s", w.AirTemp, sparkline(s, m.samples, func(w domain.Weather) float64 { return w.AirTemp })),
+		fmt.Sprintf("Track:    %5.1f°C   %s", w.TrackTemp, sparkline(s, m.samples, func(w domain.Weather) float64 { return w.TrackTemp })),
+		fmt.Sprintf("Humidity: %5.1f%%    Pressure: %.1f mbar", w.Humidity, w.Pressure),
+		fmt.Sprintf("Wind:     %5.1f m/s  from %s (%d°)", w.WindSpeed, compassPoint(w.WindDirection), w.WindDirection),
+		fmt.Sprintf("Rainfall: %s", rain),
+	}
+	return lipgloss.PlaceHorizontal(
+		m.width,
+		lipgloss.Center,
+		s.DetailPanel.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)),
+		lipgloss.WithWhitespaceChars("."),
+		lipgloss.WithWhitespaceForeground(s.Color.Subtle),
+	)
+}
+
+func (m weatherScreen) ShortHelp() []key.Binding {
+	return nil
+}
+
+func (m weatherScreen) FullHelp() [][]key.Binding {
+	return nil
+}
+
+func (m weatherScreen) title() string {
+	return "Weather"
+}
+
+var (
+	sparks = []rune("▁▂▃▄▅▆▇█")
+	// compassPoints are the 8 principal winds starting from north
+	compassPoints = []string{"N", "NE", "E", "SE", "S", "SW", "W", "NW"}
+)
+
+// sparkline returns a line chart of the value of each weather sample scaled between the lowest and
+// highest values.
+func sparkline(s *styles.Style, samples []domain.Weather, value func(w domain.Weather) float64) string {
+	values := make([]float64, 0, len(samples))
+	for _, w := range samples {
+		values = append(values, value(w))
+	}
+	lo, hi := slices.Min(values), slices.Max(values)
+	var sb strings.Builder
+	for _, v := range values {
+		i := 0
+		if hi > lo {
+			i = int((v - lo) / (hi - lo) * float64(len(sparks)-1))
+		}
+		sb.WriteRune(sparks[i])
+	}
+	return s.Subtle.Render(sb.String())
+}
+
+// compassPoint returns the principal wind closest to the given direction in degrees.
+func compassPoint(deg int) string {
+	return compassPoints[((deg%360+360)%360*2+45)/90%len(compassPoints)]
+}